@@ -0,0 +1,117 @@
+package keystore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// scanInterval is how often the key directory is scanned for key files added,
+// changed or removed by other processes.
+const scanInterval = 2 * time.Second
+
+// keyFileInfo is what is cached of a key file to rescan it only if it changed.
+type keyFileInfo struct {
+	address account.Address
+	modTime time.Time
+	size    int64
+}
+
+// scan reads the key files of the directory changed since the previous scan,
+// and returns the key files found by address. Files which are not key files, or
+// key files without the address, are skipped.
+func (ks *KeyStore) scan() map[account.Recipient]KeyFile {
+	files, err := ioutil.ReadDir(ks.keydir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Debug("Failed to read keystore directory", "dir", ks.keydir, "err", err)
+	}
+	var (
+		infos  = make(map[string]*keyFileInfo, len(files))
+		byAddr = make(map[account.Recipient]KeyFile, len(files))
+	)
+	// directory entries are sorted by name, the first file of an address wins
+	for _, fi := range files {
+		name := fi.Name()
+		if fi.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		path := filepath.Join(ks.keydir, name)
+		info := ks.files[path]
+		if info == nil || !info.modTime.Equal(fi.ModTime()) || info.size != fi.Size() {
+			addr, err := readAddress(path)
+			if err != nil {
+				log.Debug("Skipped file in keystore directory", "path", path, "err", err)
+				continue
+			}
+			info = &keyFileInfo{address: addr, modTime: fi.ModTime(), size: fi.Size()}
+		}
+		infos[path] = info
+		if dup, ok := byAddr[info.address.Recipient]; ok {
+			log.Warn("Multiple key files for the same address", "address", info.address.ToHex(), "used", dup.Path, "skipped", path)
+			continue
+		}
+		byAddr[info.address.Recipient] = KeyFile{Address: info.address, Path: path}
+	}
+	ks.files = infos
+	return byAddr
+}
+
+// readAddress returns the address stored in the key file, without decrypting
+// the key.
+func readAddress(path string) (account.Address, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return account.Address{}, err
+	}
+	var keyJSON account.EncryptedKeyJSON
+	if err := json.Unmarshal(data, &keyJSON); err != nil {
+		return account.Address{}, err
+	}
+	return account.CreatAddressFromBytes(common.FromHex(keyJSON.Address))
+}
+
+// reload rescans the key directory and updates the accounts, locking the ones
+// whose key file was removed.
+func (ks *KeyStore) reload() {
+	ks.scanMu.Lock()
+	byAddr := ks.scan()
+	ks.scanMu.Unlock()
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for recipient, u := range ks.unlocked {
+		if _, ok := byAddr[recipient]; !ok {
+			ks.relock(recipient, u)
+		}
+	}
+	accounts := make([]KeyFile, 0, len(byAddr))
+	for _, kf := range byAddr {
+		accounts = append(accounts, kf)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Path < accounts[j].Path })
+	ks.accounts = accounts
+	ks.byAddr = byAddr
+}
+
+// watch rescans the key directory until the keystore is closed.
+func (ks *KeyStore) watch() {
+	defer ks.wg.Done()
+	ticker := time.NewTicker(scanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ks.reload()
+		case <-ks.quit:
+			return
+		}
+	}
+}
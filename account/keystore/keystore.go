@@ -0,0 +1,333 @@
+// Package keystore manages the encrypted key files of a directory, which other
+// processes can add to or remove from while it runs, and signs transactions
+// with the keys of the accounts unlocked with their passphrases.
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pborman/uuid"
+)
+
+var (
+	ErrLocked               = errors.New("account is locked")
+	ErrNoMatch              = errors.New("no key for given address")
+	ErrDecrypt              = errors.New("could not decrypt key with given passphrase")
+	ErrAccountAlreadyExists = errors.New("account already exists")
+)
+
+// KeyFile is an account of the keystore, with the address of its default full
+// shard key stored in its key file.
+type KeyFile struct {
+	Address account.Address
+	Path    string
+}
+
+type unlocked struct {
+	key   *ecdsa.PrivateKey
+	abort chan struct{} // nil if unlocked until locked
+}
+
+// KeyStore manages the key files of a directory.
+type KeyStore struct {
+	keydir string
+
+	scanMu sync.Mutex
+	files  map[string]*keyFileInfo // key files scanned by path
+
+	mu       sync.RWMutex
+	accounts []KeyFile // sorted by path
+	byAddr   map[account.Recipient]KeyFile
+	unlocked map[account.Recipient]*unlocked
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewKeyStore creates a keystore of the key files of keydir, which is created
+// when the first key is stored, and starts watching it for changes.
+func NewKeyStore(keydir string) *KeyStore {
+	ks := &KeyStore{
+		keydir:   keydir,
+		files:    make(map[string]*keyFileInfo),
+		byAddr:   make(map[account.Recipient]KeyFile),
+		unlocked: make(map[account.Recipient]*unlocked),
+		quit:     make(chan struct{}),
+	}
+	ks.reload()
+	ks.wg.Add(1)
+	go ks.watch()
+	return ks
+}
+
+// Close stops watching the key directory and locks all accounts.
+func (ks *KeyStore) Close() {
+	close(ks.quit)
+	ks.wg.Wait()
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for recipient, u := range ks.unlocked {
+		ks.relock(recipient, u)
+	}
+}
+
+// Accounts returns the accounts of the key directory, ordered by the path of
+// their key file.
+func (ks *KeyStore) Accounts() []KeyFile {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	accounts := make([]KeyFile, len(ks.accounts))
+	copy(accounts, ks.accounts)
+	return accounts
+}
+
+// Find returns the account of the recipient.
+func (ks *KeyStore) Find(recipient account.Recipient) (KeyFile, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	kf, ok := ks.byAddr[recipient]
+	if !ok {
+		return KeyFile{}, ErrNoMatch
+	}
+	return kf, nil
+}
+
+// NewAccount generates a new key and stores it encrypted with the passphrase.
+func (ks *KeyStore) NewAccount(passphrase string) (KeyFile, error) {
+	acc, err := account.NewAccountWithoutKey()
+	if err != nil {
+		return KeyFile{}, err
+	}
+	return ks.store(acc, passphrase)
+}
+
+// ImportKey stores the private key encrypted with the passphrase.
+func (ks *KeyStore) ImportKey(key account.Key, passphrase string) (KeyFile, error) {
+	acc, err := account.NewAccountWithKey(key)
+	if err != nil {
+		return KeyFile{}, err
+	}
+	return ks.store(acc, passphrase)
+}
+
+// Import stores the key of the key file encrypted with passphrase, encrypting
+// it with newPassphrase.
+func (ks *KeyStore) Import(keyJSON []byte, passphrase, newPassphrase string) (KeyFile, error) {
+	acc, err := decryptKey(keyJSON, passphrase)
+	if err != nil {
+		return KeyFile{}, err
+	}
+	return ks.store(acc, newPassphrase)
+}
+
+// Export returns the key file of the account encrypted with newPassphrase
+// instead of passphrase.
+func (ks *KeyStore) Export(recipient account.Recipient, passphrase, newPassphrase string) ([]byte, error) {
+	acc, err := ks.decrypt(recipient, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return acc.Dump(newPassphrase, true, false, "")
+}
+
+// Unlock unlocks the account until it is locked.
+func (ks *KeyStore) Unlock(recipient account.Recipient, passphrase string) error {
+	return ks.TimedUnlock(recipient, passphrase, 0)
+}
+
+// TimedUnlock unlocks the account for the timeout, or until it is locked if the
+// timeout is 0. Unlocking an account unlocked until it is locked again with a
+// timeout leaves it unlocked, while unlocking it again with a timeout restarts
+// the timeout.
+func (ks *KeyStore) TimedUnlock(recipient account.Recipient, passphrase string, timeout time.Duration) error {
+	acc, err := ks.decrypt(recipient, passphrase)
+	if err != nil {
+		return err
+	}
+	key, err := crypto.ToECDSA(acc.Identity.GetKey().Bytes())
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if u, ok := ks.unlocked[recipient]; ok {
+		if u.abort == nil {
+			zeroKey(key)
+			return nil
+		}
+		close(u.abort)
+	}
+	u := &unlocked{key: key}
+	if timeout > 0 {
+		u.abort = make(chan struct{})
+		go ks.expire(recipient, u, timeout)
+	}
+	ks.unlocked[recipient] = u
+	return nil
+}
+
+// Lock removes the key of the account from memory.
+func (ks *KeyStore) Lock(recipient account.Recipient) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if u, ok := ks.unlocked[recipient]; ok {
+		ks.relock(recipient, u)
+	}
+	return nil
+}
+
+// IsUnlocked returns whether the account is unlocked.
+func (ks *KeyStore) IsUnlocked(recipient account.Recipient) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	_, ok := ks.unlocked[recipient]
+	return ok
+}
+
+// SignTx signs the transaction with the key of the unlocked account.
+func (ks *KeyStore) SignTx(recipient account.Recipient, tx *types.EvmTransaction) (*types.EvmTransaction, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	u, ok := ks.unlocked[recipient]
+	if !ok {
+		return nil, ErrLocked
+	}
+	return types.SignTx(tx, types.MakeSigner(tx.NetworkId()), u.key)
+}
+
+// SignTxWithPassphrase signs the transaction with the key of the account,
+// decrypted with the passphrase for the signature only.
+func (ks *KeyStore) SignTxWithPassphrase(recipient account.Recipient, passphrase string, tx *types.EvmTransaction) (*types.EvmTransaction, error) {
+	acc, err := ks.decrypt(recipient, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ToECDSA(acc.Identity.GetKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+	return types.SignTx(tx, types.MakeSigner(tx.NetworkId()), key)
+}
+
+// expire locks the account when the timeout of its unlock expires, unless it
+// was unlocked again or locked before.
+func (ks *KeyStore) expire(recipient account.Recipient, u *unlocked, timeout time.Duration) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-u.abort:
+	case <-t.C:
+		ks.mu.Lock()
+		if ks.unlocked[recipient] == u {
+			ks.relock(recipient, u)
+		}
+		ks.mu.Unlock()
+	}
+}
+
+// relock removes the unlocked key of the account. It is called with mu held.
+func (ks *KeyStore) relock(recipient account.Recipient, u *unlocked) {
+	if u.abort != nil {
+		select {
+		case <-u.abort:
+		default:
+			close(u.abort)
+		}
+	}
+	zeroKey(u.key)
+	delete(ks.unlocked, recipient)
+}
+
+// decrypt returns the account of the key file of the recipient.
+func (ks *KeyStore) decrypt(recipient account.Recipient, passphrase string) (account.Account, error) {
+	kf, err := ks.Find(recipient)
+	if err != nil {
+		return account.Account{}, err
+	}
+	keyJSON, err := ioutil.ReadFile(kf.Path)
+	if err != nil {
+		return account.Account{}, err
+	}
+	acc, err := decryptKey(keyJSON, passphrase)
+	if err != nil {
+		return account.Account{}, err
+	}
+	if acc.QKCAddress.Recipient != recipient {
+		return account.Account{}, errors.New("key file " + kf.Path + " does not match its address")
+	}
+	return acc, nil
+}
+
+// store writes the key file of the account unless the keystore has it.
+func (ks *KeyStore) store(acc account.Account, passphrase string) (KeyFile, error) {
+	if _, err := ks.Find(acc.QKCAddress.Recipient); err == nil {
+		return KeyFile{}, ErrAccountAlreadyExists
+	}
+	keyJSON, err := acc.Dump(passphrase, true, false, "")
+	if err != nil {
+		return KeyFile{}, err
+	}
+	path := filepath.Join(ks.keydir, acc.ID.String()+".json")
+	if err := writeKeyFile(path, keyJSON); err != nil {
+		return KeyFile{}, err
+	}
+	ks.reload()
+	return KeyFile{Address: acc.QKCAddress, Path: path}, nil
+}
+
+func decryptKey(keyJSON []byte, passphrase string) (account.Account, error) {
+	var encrypted account.EncryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &encrypted); err != nil {
+		return account.Account{}, err
+	}
+	key, err := account.DecodeKeyStoreJSON(encrypted, passphrase)
+	if err != nil {
+		return account.Account{}, ErrDecrypt
+	}
+	acc, err := account.NewAccountWithKey(account.BytesToIdentityKey(key))
+	if err != nil {
+		return account.Account{}, err
+	}
+	if id := uuid.Parse(encrypted.ID); id != nil {
+		acc.ID = id
+	}
+	return acc, nil
+}
+
+// writeKeyFile writes the key file through a temporary file, for the key
+// directory not to have partial key files.
+func writeKeyFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), path)
+}
+
+func zeroKey(k *ecdsa.PrivateKey) {
+	b := k.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
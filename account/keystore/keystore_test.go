@@ -0,0 +1,130 @@
+package keystore
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func tmpKeyStore(t *testing.T) (string, *KeyStore) {
+	dir, err := ioutil.TempDir("", "keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, NewKeyStore(filepath.Join(dir, "keystore"))
+}
+
+func testTx() *types.EvmTransaction {
+	return types.NewEvmTransaction(0, account.Recipient{0x01}, big.NewInt(1), 21000, big.NewInt(1), 0, 0, 3, 0, nil, 0, 0)
+}
+
+func TestKeyStore(t *testing.T) {
+	assert := assert.New(t)
+	dir, ks := tmpKeyStore(t)
+	defer os.RemoveAll(dir)
+	defer ks.Close()
+
+	kf, err := ks.NewAccount("foo")
+	assert.NoError(err)
+	assert.Equal([]KeyFile{kf}, ks.Accounts())
+	recipient := kf.Address.Recipient
+
+	_, err = ks.SignTx(recipient, testTx())
+	assert.Equal(ErrLocked, err)
+	assert.Equal(ErrDecrypt, ks.Unlock(recipient, "bar"))
+	assert.Equal(ErrNoMatch, ks.Unlock(account.Recipient{0x02}, "foo"))
+
+	assert.NoError(ks.Unlock(recipient, "foo"))
+	tx, err := ks.SignTx(recipient, testTx())
+	assert.NoError(err)
+	sender, err := types.Sender(types.MakeSigner(tx.NetworkId()), tx)
+	assert.NoError(err)
+	assert.Equal(recipient, sender)
+	assert.NoError(ks.Lock(recipient))
+	assert.False(ks.IsUnlocked(recipient))
+
+	tx, err = ks.SignTxWithPassphrase(recipient, "foo", testTx())
+	assert.NoError(err)
+	sender, err = types.Sender(types.MakeSigner(tx.NetworkId()), tx)
+	assert.NoError(err)
+	assert.Equal(recipient, sender)
+	assert.False(ks.IsUnlocked(recipient), "should not unlock signing with the passphrase")
+
+	// a key exported with a new passphrase is imported into another keystore
+	keyJSON, err := ks.Export(recipient, "foo", "bar")
+	assert.NoError(err)
+	_, err = ks.Import(keyJSON, "bar", "baz")
+	assert.Equal(ErrAccountAlreadyExists, err)
+	dir2, ks2 := tmpKeyStore(t)
+	defer os.RemoveAll(dir2)
+	defer ks2.Close()
+	_, err = ks2.Import(keyJSON, "foo", "baz")
+	assert.Equal(ErrDecrypt, err)
+	imported, err := ks2.Import(keyJSON, "bar", "baz")
+	assert.NoError(err)
+	assert.Equal(kf.Address, imported.Address)
+	assert.NoError(ks2.Unlock(recipient, "baz"))
+}
+
+func TestTimedUnlock(t *testing.T) {
+	assert := assert.New(t)
+	dir, ks := tmpKeyStore(t)
+	defer os.RemoveAll(dir)
+	defer ks.Close()
+
+	kf, err := ks.NewAccount("foo")
+	assert.NoError(err)
+	recipient := kf.Address.Recipient
+
+	assert.NoError(ks.TimedUnlock(recipient, "foo", 100*time.Millisecond))
+	_, err = ks.SignTx(recipient, testTx())
+	assert.NoError(err)
+	time.Sleep(250 * time.Millisecond)
+	_, err = ks.SignTx(recipient, testTx())
+	assert.Equal(ErrLocked, err, "should be locked after the timeout")
+
+	// unlocking with a timeout does not shorten an unlock until locked
+	assert.NoError(ks.Unlock(recipient, "foo"))
+	assert.NoError(ks.TimedUnlock(recipient, "foo", 100*time.Millisecond))
+	time.Sleep(250 * time.Millisecond)
+	assert.True(ks.IsUnlocked(recipient))
+}
+
+func TestWatchKeyDir(t *testing.T) {
+	assert := assert.New(t)
+	dir, ks := tmpKeyStore(t)
+	defer os.RemoveAll(dir)
+	defer ks.Close()
+
+	// a key file added by another process
+	acc, err := account.NewAccountWithoutKey()
+	assert.NoError(err)
+	keyJSON, err := acc.Dump("foo", true, false, "")
+	assert.NoError(err)
+	path := filepath.Join(ks.keydir, "added.json")
+	assert.NoError(writeKeyFile(path, keyJSON))
+	assert.NoError(ioutil.WriteFile(filepath.Join(ks.keydir, "notakey.json"), []byte("{}"), 0600))
+
+	waitFor := func(want int) {
+		deadline := time.Now().Add(3 * scanInterval)
+		for len(ks.Accounts()) != want && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	waitFor(1)
+	assert.Equal([]KeyFile{{Address: acc.QKCAddress, Path: path}}, ks.Accounts())
+	assert.NoError(ks.Unlock(acc.QKCAddress.Recipient, "foo"))
+
+	// removing the key file locks the account
+	assert.NoError(os.Remove(path))
+	waitFor(0)
+	assert.Empty(ks.Accounts())
+	assert.False(ks.IsUnlocked(acc.QKCAddress.Recipient))
+}
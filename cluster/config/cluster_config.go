@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sort"
 
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/common"
 	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/qkcdb"
 	ethcom "github.com/ethereum/go-ethereum/common"
 )
 
@@ -24,8 +26,18 @@ type ClusterConfig struct {
 	JSONRPCHOST              string            `json:"JSON_RPC_HOST"`
 	PrivateJSONRPCPort       uint16            `json:"PRIVATE_JSON_RPC_PORT"`
 	PrivateJSONRPCHOST       string            `json:"PRIVATE_JSON_RPC_HOST"`
+	WSPort                   uint16            `json:"WEBSOCKET_JSON_RPC_PORT"`
+	MetricsPort              uint16            `json:"METRICS_PORT"`
+	StratumHost              string            `json:"STRATUM_HOST"`
+	StratumPort              uint16            `json:"STRATUM_PORT"`
 	EnableTransactionHistory bool              `json:"ENABLE_TRANSACTION_HISTORY"`
+	SyncMode                 string            `json:"SYNC_MODE"`
+	GCMode                   string            `json:"GC_MODE"`
+	StateRetention           uint64            `json:"STATE_RETENTION"`
 	DbPathRoot               string            `json:"DB_PATH_ROOT"`
+	DbBackend                string            `json:"DB_BACKEND"`
+	DbCacheSize              int               `json:"DB_CACHE_SIZE"`
+	DbCompression            string            `json:"DB_COMPRESSION"`
 	LogLevel                 string            `json:"LOG_LEVEL"`
 	StartSimulatedMining     bool              `json:"START_SIMULATED_MINING"`
 	Clean                    bool              `json:"CLEAN"`
@@ -49,8 +61,18 @@ func NewClusterConfig() *ClusterConfig {
 		JSONRPCHOST:              "0.0.0.0",
 		PrivateJSONRPCPort:       DefaultPrivRpcPort,
 		PrivateJSONRPCHOST:       DefaultHost,
+		WSPort:                   DefaultMasterWSPort,
+		MetricsPort:              DefaultMasterMetricsPort,
+		StratumHost:              "0.0.0.0",
+		StratumPort:              0,
 		EnableTransactionHistory: false,
+		SyncMode:                 SyncModeFull,
+		GCMode:                   GCModeArchive,
+		StateRetention:           DefaultStateRetention,
 		DbPathRoot:               "./db",
+		DbBackend:                qkcdb.DefaultBackend,
+		DbCacheSize:              128,
+		DbCompression:            "snappy",
 		LogLevel:                 "info",
 		StartSimulatedMining:     false,
 		Clean:                    false,
@@ -92,6 +114,7 @@ type QuarkChainConfig struct {
 	ChainSize                         uint32      `json:"CHAIN_SIZE"`
 	MaxNeighbors                      uint32      `json:"MAX_NEIGHBORS"`
 	NetworkID                         uint32      `json:"NETWORK_ID"`
+	BaseEthChainID                    uint32      `json:"BASE_ETH_CHAIN_ID"`
 	TransactionQueueSizeLimitPerShard uint64      `json:"TRANSACTION_QUEUE_SIZE_LIMIT_PER_SHARD"`
 	BlockExtraDataSizeLimit           uint32      `json:"BLOCK_EXTRA_DATA_SIZE_LIMIT"`
 	GuardianPublicKey                 []byte      `json:"-"`
@@ -115,6 +138,7 @@ type QuarkChainConfig struct {
 	allowTokenIDs                     map[uint64]bool
 	EnableEvmTimeStamp                uint64      `json:"ENABLE_EVM_TIMESTAMP"`
 	EnableQkcHashXHeight              uint64      `json:"ENABLE_QKCHASHX_HEIGHT"`
+	EnableEthTxHeight                 uint64      `json:"ENABLE_ETH_TX_HEIGHT"`
 	DisablePowCheck                   bool        `json:"DISABLE_POW_CHECK"`
 	XShardGasDDOSFixRootHeight        uint64      `json:"XSHARD_GAS_DDOS_FIX_ROOT_HEIGHT"`
 	MinTXPoolGasPrice                 *big.Int    `json:"MIN_TX_POOL_GAS_PRICE"`
@@ -157,6 +181,8 @@ func (q *QuarkChainConfig) MarshalJSON() ([]byte, error) {
 
 func (q *QuarkChainConfig) UnmarshalJSON(input []byte) error {
 	jConfig := &jsonConfig{}
+	// eth txs stay disabled if the config has no activation height
+	jConfig.EnableEthTxHeight = math.MaxUint64
 	if err := json.Unmarshal(input, jConfig); err != nil {
 		return err
 	}
@@ -353,6 +379,29 @@ func (q *QuarkChainConfig) GetShardSizeByChainId(ID uint32) (uint32, error) {
 	return data, nil
 }
 
+// GetEthChainID returns the EIP-155 chain id of Ethereum txs sent to the chain,
+// or 0 if Ethereum txs are not accepted as BASE_ETH_CHAIN_ID is not set.
+func (q *QuarkChainConfig) GetEthChainID(chainID uint32) uint64 {
+	if q.BaseEthChainID == 0 {
+		return 0
+	}
+	return uint64(q.BaseEthChainID) + uint64(chainID)
+}
+
+// IsEthTxEnabled returns whether Ethereum txs may be included in the minor
+// blocks at height, which is never the case below ENABLE_ETH_TX_HEIGHT.
+func (q *QuarkChainConfig) IsEthTxEnabled(height uint64) bool {
+	return height >= q.EnableEthTxHeight
+}
+
+// GetChainIDByEthChainID returns the chain Ethereum txs signed for ethChainID are sent to.
+func (q *QuarkChainConfig) GetChainIDByEthChainID(ethChainID uint64) (uint32, error) {
+	if q.BaseEthChainID == 0 || ethChainID < uint64(q.BaseEthChainID) || ethChainID-uint64(q.BaseEthChainID) >= uint64(q.ChainSize) {
+		return 0, fmt.Errorf("no chain for eth chain id %d", ethChainID)
+	}
+	return uint32(ethChainID - uint64(q.BaseEthChainID)), nil
+}
+
 func NewQuarkChainConfig() *QuarkChainConfig {
 	grpchost, _ := common.GetIPV4Addr()
 	var ret = QuarkChainConfig{
@@ -378,6 +427,7 @@ func NewQuarkChainConfig() *QuarkChainConfig {
 		GRPCHost:                          grpchost,
 		GRPCPort:                          DefaultGrpcPort,
 		EnableEvmTimeStamp:                1569567600,
+		EnableEthTxHeight:                 math.MaxUint64,
 		RootChainPoSWContractBytecodeHash: ethcom.HexToHash("0000000000000000000000000000000000000000000000000000000000000000"),
 	}
 
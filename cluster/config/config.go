@@ -27,12 +27,30 @@ const (
 	// PoWQkchash is the consensus type running qkchash algorithm.
 	PoWQkchash = "POW_QKCHASH"
 
-	DefaultGrpcPort    uint16 = 38191
-	DefaultP2PPort     uint16 = 38291
-	DefaultPubRpcPort  uint16 = 38391
-	DefaultPrivRpcPort uint16 = 38491
-	DefaultWSPort      uint16 = 38590
-	DefaultHost               = "localhost"
+	// SyncModeFull syncs the shards by executing every minor block.
+	SyncModeFull = "full"
+	// SyncModeState syncs the shards by downloading the state at a pivot
+	// block from peers instead of executing the blocks before it.
+	SyncModeState = "state"
+
+	// GCModeArchive writes the state of every minor block to the database.
+	GCModeArchive = "archive"
+	// GCModePruned keeps the state of recent minor blocks in memory and only
+	// writes some of them, like those confirmed by root blocks, to the database.
+	GCModePruned = "pruned"
+	// DefaultStateRetention is how many recent minor blocks a pruned node
+	// keeps the state of.
+	DefaultStateRetention = 256
+
+	DefaultGrpcPort          uint16 = 38191
+	DefaultP2PPort           uint16 = 38291
+	DefaultPubRpcPort        uint16 = 38391
+	DefaultPrivRpcPort       uint16 = 38491
+	DefaultWSPort            uint16 = 38590
+	DefaultMasterWSPort      uint16 = 38690
+	DefaultMetricsPort       uint16 = 38790
+	DefaultMasterMetricsPort uint16 = 38890
+	DefaultHost                     = "localhost"
 
 	HeartbeatInterval = time.Duration(4 * time.Second)
 )
@@ -54,12 +72,16 @@ var (
 type POWConfig struct {
 	TargetBlockTime uint32 `json:"TARGET_BLOCK_TIME"`
 	RemoteMine      bool   `json:"REMOTE_MINE"`
+	// ShareDifficulty is the minimum difficulty of the shares Stratum miners
+	// submit, 0 to only accept solutions meeting the difficulty of the block.
+	ShareDifficulty uint64 `json:"SHARE_DIFFICULTY"`
 }
 
 func NewPOWConfig() *POWConfig {
 	return &POWConfig{
 		TargetBlockTime: 10,
 		RemoteMine:      false,
+		ShareDifficulty: 0,
 	}
 }
 
@@ -245,6 +267,7 @@ type MonitoringConfig struct {
 	MinerTopic       string `json:"MINER_TOPIC"`        // "qkc_miner"
 	PropagationTopic string `json:"PROPAGATION_TOPIC"`  // "block_propagation"
 	Errors           string `json:"ERRORS"`             // "error"
+	SampleFile       string `json:"SAMPLE_FILE"`        // file, or "stdout", the samples are written to instead of Kafka
 }
 
 func NewMonitoringConfig() *MonitoringConfig {
@@ -151,6 +151,22 @@ func TestLoadClusterConfig(t *testing.T) {
 	}
 }
 
+func TestEnableEthTxHeight(t *testing.T) {
+	// eth txs are disabled unless the config has an activation height
+	var cfg ClusterConfig
+	assert.NoError(t, loadConfig("./test_config.json", &cfg))
+	assert.False(t, cfg.Quarkchain.IsEthTxEnabled(1<<62))
+	assert.False(t, NewQuarkChainConfig().IsEthTxEnabled(1<<62))
+
+	qkcCfg := NewQuarkChainConfig()
+	qkcCfg.EnableEthTxHeight = 100
+	data, err := json.Marshal(qkcCfg)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, qkcCfg))
+	assert.False(t, qkcCfg.IsEthTxEnabled(99))
+	assert.True(t, qkcCfg.IsEthTxEnabled(100))
+}
+
 func TestShardGenesis(t *testing.T) {
 	var (
 		shardGensis ShardGenesis
@@ -11,6 +11,7 @@ type SlaveConfig struct {
 	Port          uint16             `json:"PORT"` // 38392
 	ID            string             `json:"ID"`
 	WSPort        uint16             `json:"WEBSOCKET_JSON_RPC_PORT"`
+	MetricsPort   uint16             `json:"METRICS_PORT"`
 	ChainMaskList []*types.ChainMask `json:"-"`
 }
 
@@ -38,6 +39,9 @@ func (s *SlaveConfig) UnmarshalJSON(input []byte) error {
 	}
 	*s = SlaveConfig(jsonConfig.SlaveConfigAlias)
 	s.WSPort = DefaultWSPort
+	if s.MetricsPort == 0 {
+		s.MetricsPort = DefaultMetricsPort
+	}
 	s.ChainMaskList = make([]*types.ChainMask, len(jsonConfig.ChainMaskList))
 	for i, value := range jsonConfig.ChainMaskList {
 		s.ChainMaskList[i] = types.NewChainMask(value)
@@ -47,9 +51,10 @@ func (s *SlaveConfig) UnmarshalJSON(input []byte) error {
 
 func NewDefaultSlaveConfig() *SlaveConfig {
 	slaveConfig := SlaveConfig{
-		IP:     DefaultHost,
-		Port:   slavePort,
-		WSPort: DefaultWSPort,
+		IP:          DefaultHost,
+		Port:        slavePort,
+		WSPort:      DefaultWSPort,
+		MetricsPort: DefaultMetricsPort,
 	}
 	return &slaveConfig
 }
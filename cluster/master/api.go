@@ -0,0 +1,230 @@
+package master
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/cluster/rpc"
+	"github.com/QuarkChain/goquarkchain/core"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
+	qrpc "github.com/QuarkChain/goquarkchain/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	shardEventChanSize     = 128
+	rootHeadChanSize       = 10
+	eventStreamRetryPeriod = 3 * time.Second
+)
+
+// eventStream fans in the events of one type of all the slaves into feed. The
+// streams from the slaves are only open while there are subscribers.
+type eventStream struct {
+	feed   event.Feed
+	refs   int
+	cancel context.CancelFunc
+}
+
+// eventSubscription releases its event stream once unsubscribed.
+type eventSubscription struct {
+	event.Subscription
+	api  *PublicFilterAPI
+	typ  uint8
+	once sync.Once
+}
+
+func (s *eventSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.Subscription.Unsubscribe()
+		s.api.release(s.typ)
+	})
+}
+
+// PublicFilterAPI offers websocket subscriptions to the events of all the
+// shards of the cluster and to the root chain, so that clients do not have to
+// connect to each slave.
+type PublicFilterAPI struct {
+	backend *QKCMasterBackend
+	mu      sync.Mutex
+	streams map[uint8]*eventStream
+}
+
+// NewPublicFilterAPI returns a new PublicFilterAPI instance.
+func NewPublicFilterAPI(backend *QKCMasterBackend) *PublicFilterAPI {
+	return &PublicFilterAPI{
+		backend: backend,
+		streams: make(map[uint8]*eventStream),
+	}
+}
+
+// subscribe subscribes ch to the events of type typ of all the shards, opening
+// the streams from the slaves if it is the first subscriber.
+func (api *PublicFilterAPI) subscribe(typ uint8, ch chan<- *rpc.ShardEvent) event.Subscription {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	stream, ok := api.streams[typ]
+	if !ok {
+		stream = new(eventStream)
+		api.streams[typ] = stream
+	}
+	sub := stream.feed.Subscribe(ch)
+	if stream.refs++; stream.refs == 1 {
+		var ctx context.Context
+		ctx, stream.cancel = context.WithCancel(context.Background())
+		for _, conn := range api.backend.GetSlaveConns() {
+			go api.streamEvents(ctx, conn, typ, &stream.feed)
+		}
+	}
+	return &eventSubscription{Subscription: sub, api: api, typ: typ}
+}
+
+// release closes the streams of the events of type typ from the slaves once
+// the last subscriber is gone.
+func (api *PublicFilterAPI) release(typ uint8) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	stream := api.streams[typ]
+	if stream.refs--; stream.refs == 0 {
+		stream.cancel()
+	}
+}
+
+// streamEvents sends the events of type typ of the slave to feed until ctx is
+// done, opening the stream again if it breaks.
+func (api *PublicFilterAPI) streamEvents(ctx context.Context, conn rpc.ISlaveConn, typ uint8, feed *event.Feed) {
+	for {
+		err := conn.SubscribeEvents(ctx, typ, func(ev *rpc.ShardEvent) {
+			feed.Send(ev)
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventStreamRetryPeriod):
+			log.Warn("event stream from slave broken", "slave", conn.GetSlaveID(), "type", typ, "err", err)
+		}
+	}
+}
+
+// shardSubscription creates a subscription notified with the encoded events of
+// type typ of the shard fullShardId, or of all the shards if it is nil.
+func (api *PublicFilterAPI) shardSubscription(ctx context.Context, typ uint8, fullShardId *hexutil.Uint,
+	notify func(notifier *qrpc.Notifier, id qrpc.ID, ev *rpc.ShardEvent)) (*qrpc.Subscription, error) {
+	notifier, supported := qrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &qrpc.Subscription{}, qrpc.ErrNotificationsUnsupported
+	}
+	if fullShardId != nil && api.backend.GetOneSlaveConnById(uint32(*fullShardId)) == nil {
+		return nil, ErrNoBranchConn
+	}
+
+	var (
+		rpcSub = notifier.CreateSubscription()
+		events = make(chan *rpc.ShardEvent, shardEventChanSize)
+		sub    = api.subscribe(typ, events)
+	)
+
+	go func() {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				if fullShardId == nil || ev.Branch == uint32(*fullShardId) {
+					notify(notifier, rpcSub.ID, ev)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// NewHeads sends a notification each time a new block header is appended to the
+// chain of the shard fullShardId, or of any shard if it is omitted.
+func (api *PublicFilterAPI) NewHeads(ctx context.Context, fullShardId *hexutil.Uint) (*qrpc.Subscription, error) {
+	return api.shardSubscription(ctx, rpc.NewHeadsEvent, fullShardId, func(notifier *qrpc.Notifier, id qrpc.ID, ev *rpc.ShardEvent) {
+		hd, err := encoder.MinorBlockHeaderEncoder(ev.Header)
+		if err != nil {
+			log.Error("encode MinorBlockHeader error", "err", err)
+			return
+		}
+		notifier.Notify(id, hd)
+	})
+}
+
+// Logs creates a subscription that fires for all the new logs matching crit of
+// the shard fullShardId, or of any shard if it is omitted.
+func (api *PublicFilterAPI) Logs(ctx context.Context, crit qrpc.FilterQuery, fullShardId *hexutil.Uint) (*qrpc.Subscription, error) {
+	return api.shardSubscription(ctx, rpc.LogsEvent, fullShardId, func(notifier *qrpc.Notifier, id qrpc.ID, ev *rpc.ShardEvent) {
+		for _, l := range core.FilterLogs(ev.Logs, crit.FromBlock, crit.ToBlock, crit.Addresses, crit.Topics) {
+			field := encoder.LogEncoder(l, ev.Removed)
+			field["fullShardId"] = hexutil.Uint64(ev.Branch)
+			notifier.Notify(id, field)
+		}
+	})
+}
+
+// NewPendingTransactions creates a subscription that is triggered each time a
+// transaction enters the tx pool of the shard fullShardId, or of any shard if
+// it is omitted.
+func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, fullShardId *hexutil.Uint) (*qrpc.Subscription, error) {
+	return api.shardSubscription(ctx, rpc.PendingTxsEvent, fullShardId, func(notifier *qrpc.Notifier, id qrpc.ID, ev *rpc.ShardEvent) {
+		header := types.GetEmptyMinorBlock().Header()
+		header.Branch.Value = ev.Branch
+		for _, tx := range ev.Txs {
+			block := types.NewMinorBlockWithHeader(header, &types.MinorBlockMeta{})
+			block.AddTx(tx)
+			data, err := encoder.TxEncoder(block, 0)
+			if err != nil {
+				log.Error("failed to encode tx when subscription pending transactions", "err", err)
+				continue
+			}
+			notifier.Notify(id, data)
+		}
+	})
+}
+
+// NewRootHeads sends a notification each time a new root block is appended to
+// the root chain.
+func (api *PublicFilterAPI) NewRootHeads(ctx context.Context) (*qrpc.Subscription, error) {
+	notifier, supported := qrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &qrpc.Subscription{}, qrpc.ErrNotificationsUnsupported
+	}
+
+	var (
+		rpcSub  = notifier.CreateSubscription()
+		heads   = make(chan core.RootChainHeadEvent, rootHeadChanSize)
+		headSub = api.backend.rootBlockChain.SubscribeChainHeadEvent(heads)
+	)
+
+	go func() {
+		defer headSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-heads:
+				block, err := encoder.RootBlockEncoder(ev.Block, nil)
+				if err != nil {
+					log.Error("encode RootBlock error", "err", err)
+					continue
+				}
+				notifier.Notify(rpcSub.ID, block)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
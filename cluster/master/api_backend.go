@@ -19,6 +19,7 @@ import (
 	"math/big"
 	"net"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -163,6 +164,69 @@ func (s *QKCMasterBackend) GetTransactionReceipt(txHash common.Hash, branch acco
 	return slaveConn.GetTransactionReceipt(txHash, branch)
 }
 
+func (s *QKCMasterBackend) GetTxPoolContent(branch account.Branch) ([]*rpc.TxPoolAccount, []*rpc.TxPoolAccount, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return nil, nil, ErrNoBranchConn
+	}
+	return slaveConn.GetTxPoolContent(branch)
+}
+
+func (s *QKCMasterBackend) GetTxPoolStatus(branch account.Branch) (uint32, uint32, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return 0, 0, ErrNoBranchConn
+	}
+	return slaveConn.GetTxPoolStatus(branch)
+}
+
+func (s *QKCMasterBackend) GetXShardDepositReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return nil, 0, nil, ErrNoBranchConn
+	}
+	return slaveConn.GetXShardDepositReceipt(txHash, branch)
+}
+
+func (s *QKCMasterBackend) GetXShardDepositBlock(branch account.Branch, rHash, mHash, txHash common.Hash) (*types.MinorBlockHeader, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return nil, ErrNoBranchConn
+	}
+	return slaveConn.GetXShardDepositBlock(branch, rHash, mHash, txHash)
+}
+
+func (s *QKCMasterBackend) TraceTransaction(txHash common.Hash, branch account.Branch, config *rpc.TraceConfig) ([]byte, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return nil, ErrNoBranchConn
+	}
+	return slaveConn.TraceTransaction(txHash, branch, config)
+}
+
+func (s *QKCMasterBackend) TraceBlockByHash(blockHash common.Hash, branch account.Branch, config *rpc.TraceConfig) ([]byte, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return nil, ErrNoBranchConn
+	}
+	return slaveConn.TraceBlockByHash(blockHash, branch, config)
+}
+
+func (s *QKCMasterBackend) TraceCall(tx *types.Transaction, address *account.Address, height *uint64, config *rpc.TraceConfig) ([]byte, error) {
+	fromShardSize, err := s.clusterConfig.Quarkchain.GetShardSizeByChainId(tx.EvmTx.FromChainID())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.EvmTx.SetFromShardSize(fromShardSize); err != nil {
+		return nil, fmt.Errorf("Failed to set fromShardSize, fromShardSize: %d, err: %v", fromShardSize, err)
+	}
+	slaveConn := s.GetOneSlaveConnById(tx.EvmTx.FromFullShardId())
+	if slaveConn == nil {
+		return nil, ErrNoBranchConn
+	}
+	return slaveConn.TraceCall(tx, address, height, config)
+}
+
 func (s *QKCMasterBackend) GetTransactionsByAddress(address *account.Address, start []byte, limit uint32, transferTokenID *uint64) ([]*rpc.TransactionDetail, []byte, error) {
 	fullShardID, err := s.clusterConfig.Quarkchain.GetFullShardIdByFullShardKey(address.FullShardKey)
 	if err != nil {
@@ -192,6 +256,73 @@ func (s *QKCMasterBackend) GetLogs(args *qrpc.FilterQuery) ([]*types.Log, error)
 	return slaveConn.GetLogs(args)
 }
 
+// Filters are installed and expire in the slave of their shard, so the id
+// returned to clients is the id of the slave prefixed with the full shard id
+// as 8 hex digits, which routes the polls without keeping filters in master.
+func encodeFilterID(fullShardId uint32, id string) qrpc.ID {
+	return qrpc.ID(fmt.Sprintf("0x%08x%s", fullShardId, strings.TrimPrefix(id, "0x")))
+}
+
+func decodeFilterID(id qrpc.ID) (uint32, string, error) {
+	str := string(id)
+	if len(str) <= 10 || !strings.HasPrefix(str, "0x") {
+		return 0, "", ErrFilterNotFound
+	}
+	fullShardId, err := strconv.ParseUint(str[2:10], 16, 32)
+	if err != nil {
+		return 0, "", ErrFilterNotFound
+	}
+	return uint32(fullShardId), "0x" + str[10:], nil
+}
+
+func (s *QKCMasterBackend) NewFilter(args *qrpc.FilterQuery) (qrpc.ID, error) {
+	slaveConn := s.GetOneSlaveConnById(args.FullShardId)
+	if slaveConn == nil {
+		return "", ErrNoBranchConn
+	}
+	id, err := slaveConn.NewFilter(args)
+	if err != nil {
+		return "", err
+	}
+	return encodeFilterID(args.FullShardId, id), nil
+}
+
+func (s *QKCMasterBackend) NewBlockFilter(branch account.Branch) (qrpc.ID, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return "", ErrNoBranchConn
+	}
+	id, err := slaveConn.NewBlockFilter(branch)
+	if err != nil {
+		return "", err
+	}
+	return encodeFilterID(branch.Value, id), nil
+}
+
+func (s *QKCMasterBackend) GetFilterChanges(id qrpc.ID) ([]common.Hash, []*types.Log, error) {
+	fullShardId, slaveID, err := decodeFilterID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	slaveConn := s.GetOneSlaveConnById(fullShardId)
+	if slaveConn == nil {
+		return nil, nil, ErrFilterNotFound
+	}
+	return slaveConn.GetFilterChanges(slaveID)
+}
+
+func (s *QKCMasterBackend) UninstallFilter(id qrpc.ID) (bool, error) {
+	fullShardId, slaveID, err := decodeFilterID(id)
+	if err != nil {
+		return false, nil
+	}
+	slaveConn := s.GetOneSlaveConnById(fullShardId)
+	if slaveConn == nil {
+		return false, nil
+	}
+	return slaveConn.UninstallFilter(slaveID)
+}
+
 func (s *QKCMasterBackend) EstimateGas(tx *types.Transaction, fromAddress *account.Address) (uint32, error) {
 	evmTx := tx.EvmTx
 	fromShardSize, err := s.clusterConfig.Quarkchain.GetShardSizeByChainId(tx.EvmTx.FromChainID())
@@ -201,13 +332,26 @@ func (s *QKCMasterBackend) EstimateGas(tx *types.Transaction, fromAddress *accou
 	if err := tx.EvmTx.SetFromShardSize(fromShardSize); err != nil {
 		return 0, errors.New(fmt.Sprintf("Failed to set fromShardSize, fromShardSize: %d, err: %v", fromShardSize, err))
 	}
-	slaveConn := s.GetOneSlaveConnById(evmTx.FromFullShardId())
-	if slaveConn == nil {
-		return 0, ErrNoBranchConn
+	toShardSize, err := s.clusterConfig.Quarkchain.GetShardSizeByChainId(tx.EvmTx.ToChainID())
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.EvmTx.SetToShardSize(toShardSize); err != nil {
+		return 0, errors.New(fmt.Sprintf("Failed to set toShardSize, toShardSize: %d, err: %v", toShardSize, err))
 	}
 	if !evmTx.IsCrossShard() {
+		slaveConn := s.GetOneSlaveConnById(evmTx.FromFullShardId())
+		if slaveConn == nil {
+			return 0, ErrNoBranchConn
+		}
 		return slaveConn.EstimateGas(tx, fromAddress)
 	}
+	// a cross-shard tx is estimated as a tx of the sender in the target shard,
+	// which may be run by another slave
+	slaveConn := s.GetOneSlaveConnById(evmTx.ToFullShardId())
+	if slaveConn == nil {
+		return 0, ErrNoBranchConn
+	}
 	fAddr := account.Address{Recipient: fromAddress.Recipient, FullShardKey: evmTx.ToFullShardKey()}
 	res, err := slaveConn.EstimateGas(tx, &fAddr)
 	if err != nil {
@@ -248,6 +392,16 @@ func (s *QKCMasterBackend) GasPrice(branch account.Branch, tokenID uint64) (uint
 	return slaveConn.GasPrice(branch, tokenID)
 }
 
+// GasPriceRate returns the gas price paid in tokenID in the recent blocks of the
+// shard, failing if no tx paid its gas in tokenID.
+func (s *QKCMasterBackend) GasPriceRate(branch account.Branch, tokenID uint64) (uint64, error) {
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return 0, ErrNoBranchConn
+	}
+	return slaveConn.GasPriceRate(branch, tokenID)
+}
+
 // return root chain work if branch is nil
 func (s *QKCMasterBackend) GetWork(fullShardId *uint32, addr *common.Address) (*consensus.MiningWork, error) {
 	coinbaseAddr := &account.Address{}
@@ -285,6 +439,21 @@ func (s *QKCMasterBackend) SubmitWork(fullShardId *uint32, headerHash common.Has
 	return slaveConn.SubmitWork(&rpc.SubmitWorkRequest{Branch: branch.Value, HeaderHash: headerHash, Nonce: nonce, MixHash: mixHash})
 }
 
+// SubmitShare submits a share with a difficulty lower than the one of the
+// block, returning whether it sealed the block and an error if it is invalid.
+func (s *QKCMasterBackend) SubmitShare(fullShardId *uint32, headerHash common.Hash, nonce uint64, mixHash common.Hash, shareDiff *big.Int) (bool, error) {
+	if fullShardId == nil {
+		return s.miner.SubmitShare(nonce, headerHash, mixHash, shareDiff)
+	}
+
+	branch := account.NewBranch(*fullShardId)
+	slaveConn := s.GetOneSlaveConnById(branch.Value)
+	if slaveConn == nil {
+		return false, ErrNoBranchConn
+	}
+	return slaveConn.SubmitShare(&rpc.SubmitShareRequest{Branch: branch.Value, HeaderHash: headerHash, Nonce: nonce, MixHash: mixHash, ShareDifficulty: shareDiff})
+}
+
 func (s *QKCMasterBackend) GetRootBlockByNumber(blockNumber *uint64, needExtraInfo bool) (*types.RootBlock, *rpc.PoSWInfo, error) {
 	if blockNumber == nil {
 		temp := s.rootBlockChain.CurrentBlock().NumberU64()
@@ -365,7 +534,12 @@ func (s *QKCMasterBackend) CreateBlockToMine(addr *account.Address) (types.IBloc
 
 func (s *QKCMasterBackend) InsertMinedBlock(block types.IBlock) error {
 	rBlock := block.(*types.RootBlock)
-	return s.AddRootBlock(rBlock)
+	if err := s.AddRootBlock(rBlock); err != nil {
+		s.telemetry.InsertError(rBlock.Header(), err)
+		return err
+	}
+	s.telemetry.BlockMined(rBlock.Header())
+	return nil
 }
 
 func (s *QKCMasterBackend) AddMinorBlock(branch uint32, mBlock *types.MinorBlock) error {
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/account/keystore"
 	"github.com/QuarkChain/goquarkchain/cluster/config"
 	"github.com/QuarkChain/goquarkchain/cluster/miner"
 	"github.com/QuarkChain/goquarkchain/cluster/rpc"
 	"github.com/QuarkChain/goquarkchain/cluster/service"
+	"github.com/QuarkChain/goquarkchain/cluster/stratum"
 	Synchronizer "github.com/QuarkChain/goquarkchain/cluster/sync"
+	"github.com/QuarkChain/goquarkchain/cluster/telemetry"
 	"github.com/QuarkChain/goquarkchain/consensus"
 	"github.com/QuarkChain/goquarkchain/consensus/doublesha256"
 	"github.com/QuarkChain/goquarkchain/consensus/ethash"
@@ -42,7 +45,8 @@ const (
 )
 
 var (
-	ErrNoBranchConn = errors.New("no such branch's connection")
+	ErrNoBranchConn   = errors.New("no such branch's connection")
+	ErrFilterNotFound = errors.New("filter not found")
 )
 
 type TxForQueue struct {
@@ -65,7 +69,9 @@ type QKCMasterBackend struct {
 	shardStatsChan     chan *rpc.ShardStatus
 
 	SlaveConnManager
-	miner *miner.Miner
+	miner    *miner.Miner
+	stratum  *stratum.Server
+	keystore *keystore.KeyStore
 
 	maxPeers int
 	srvr     *p2p.Server
@@ -77,6 +83,7 @@ type QKCMasterBackend struct {
 	txCountHistory     *deque.Deque
 	logInfo            string
 	exitCh             chan struct{}
+	telemetry          *telemetry.Publisher
 }
 
 // New new master with config
@@ -105,7 +112,11 @@ func New(ctx *service.ServiceContext, cfg *config.ClusterConfig) (*QKCMasterBack
 		return nil, err
 	}
 
-	if mstr.engine, err = createConsensusEngine(cfg.Quarkchain.Root, cfg.Quarkchain.GuardianPublicKey, cfg.Quarkchain.EnableQkcHashXHeight); err != nil {
+	if mstr.telemetry, err = telemetry.New(cfg.Monitoring); err != nil {
+		return nil, err
+	}
+
+	if mstr.engine, err = CreateConsensusEngine(cfg.Quarkchain.Root, cfg.Quarkchain.GuardianPublicKey, cfg.Quarkchain.EnableQkcHashXHeight); err != nil {
 		return nil, err
 	}
 
@@ -129,8 +140,15 @@ func New(ctx *service.ServiceContext, cfg *config.ClusterConfig) (*QKCMasterBack
 	if mstr.protocolManager, err = NewProtocolManager(*cfg, mstr.rootBlockChain, mstr.shardStatsChan, mstr.synchronizer, &mstr.SlaveConnManager); err != nil {
 		return nil, err
 	}
+	mstr.protocolManager.telemetry = mstr.telemetry
 
 	mstr.miner = miner.New(ctx, mstr, mstr.engine)
+	if cfg.StratumPort != 0 {
+		mstr.stratum = stratum.New(mstr, cfg.Quarkchain, fmt.Sprintf("%s:%d", cfg.StratumHost, cfg.StratumPort))
+	}
+	if keydir := ctx.KeyStoreDir(); keydir != "" {
+		mstr.keystore = keystore.NewKeyStore(keydir)
+	}
 
 	return mstr, nil
 }
@@ -143,7 +161,8 @@ func createDB(ctx *service.ServiceContext, name string, clean bool, isReadOnly b
 	return db, nil
 }
 
-func createConsensusEngine(cfg *config.RootConfig, pubKey []byte, qkcHashXHeight uint64) (consensus.Engine, error) {
+// CreateConsensusEngine creates the consensus engine of the root chain.
+func CreateConsensusEngine(cfg *config.RootConfig, pubKey []byte, qkcHashXHeight uint64) (consensus.Engine, error) {
 	diffCalculator := consensus.EthDifficultyCalculator{
 		MinimumDifficulty: big.NewInt(int64(cfg.Genesis.Difficulty)),
 		AdjustmentCutoff:  cfg.DifficultyAdjustmentCutoffTime,
@@ -245,8 +264,7 @@ func (s *QKCMasterBackend) CheckDB() {
 
 // APIs return all apis for master Server
 func (s *QKCMasterBackend) APIs() []qrpc.API {
-	apis := qkcapi.GetAPIs(s)
-	return append(apis, []qrpc.API{
+	apis := append(qkcapi.GetAPIs(s), []qrpc.API{
 		{
 			Namespace: "grpc",
 			Version:   "3.0",
@@ -254,17 +272,64 @@ func (s *QKCMasterBackend) APIs() []qrpc.API {
 			Public:    false,
 		},
 	}...)
+	if s.ctx.WSIsAlive() {
+		apis = append(apis, qrpc.API{
+			Namespace: "ws",
+			Version:   "3.0",
+			Service:   NewPublicFilterAPI(s),
+			Public:    true,
+		})
+	}
+	if s.keystore != nil {
+		signingAPI := qkcapi.NewPrivateSigningAPI(s, s.keystore)
+		apis = append(apis, []qrpc.API{
+			{
+				Namespace: "personal",
+				Version:   "1.0",
+				Service:   qkcapi.NewPrivatePersonalAPI(s, s.keystore),
+				Public:    false,
+			},
+			{
+				Namespace: "qkc",
+				Version:   "1.0",
+				Service:   signingAPI,
+				Public:    false,
+			},
+			{
+				Namespace: "eth",
+				Version:   "1.0",
+				Service:   qkcapi.NewPrivateEthSigningAPI(signingAPI),
+				Public:    false,
+			},
+		}...)
+	}
+	if s.stratum != nil {
+		apis = append(apis, qrpc.API{
+			Namespace: "stratum",
+			Version:   "3.0",
+			Service:   stratum.NewPrivateStratumAPI(s.stratum),
+			Public:    false,
+		})
+	}
+	return apis
 }
 
 // Stop stop node -> stop qkcMaster
 func (s *QKCMasterBackend) Stop() error {
 	s.synchronizer.Close()
 	s.protocolManager.Stop()
+	if s.stratum != nil {
+		s.stratum.Stop()
+	}
+	if s.keystore != nil {
+		s.keystore.Close()
+	}
 	s.miner.Stop()
 	s.engine.Close()
 	s.rootBlockChain.Stop()
 	s.eventMux.Stop()
 	s.chainDb.Close()
+	s.telemetry.Stop()
 	close(s.exitCh)
 	for _, slv := range s.GetSlaveConns() {
 		conn := slv.(*SlaveConnection)
@@ -323,6 +388,11 @@ func (s *QKCMasterBackend) Start() error {
 	if s.clusterConfig.Quarkchain.Root.ConsensusConfig.RemoteMine {
 		s.SetMining(true)
 	}
+	if s.stratum != nil {
+		if err := s.stratum.Start(); err != nil {
+			return err
+		}
+	}
 
 	log.Info("Start cluster successful", "slaveSize", s.ConnCount())
 	return nil
@@ -561,6 +631,7 @@ func (s *QKCMasterBackend) AddRootBlock(rootBlock *types.RootBlock) error {
 	s.rootBlockChain.ClearCommittingHash()
 	if header.Hash() != s.rootBlockChain.CurrentBlock().Hash() {
 		go s.miner.HandleNewTip()
+		s.notifyStratum(nil)
 	}
 	return nil
 }
@@ -610,6 +681,14 @@ func (s *QKCMasterBackend) CreateTransactions(numTxPerShard, xShardPercent uint3
 	return g.Wait()
 }
 
+// notifyStratum pushes new work to the Stratum miners of the shard, or of all
+// chains if fullShardId is nil, after a tip change.
+func (s *QKCMasterBackend) notifyStratum(fullShardId *uint32) {
+	if s.stratum != nil {
+		s.stratum.NotifyNewTip(fullShardId)
+	}
+}
+
 // UpdateShardStatus update shard status for branchg
 func (s *QKCMasterBackend) UpdateShardStatus(status *rpc.ShardStatus) {
 	s.lock.Lock()
@@ -10,6 +10,7 @@ import (
 	"github.com/QuarkChain/goquarkchain/cluster/config"
 	"github.com/QuarkChain/goquarkchain/cluster/rpc"
 	qkcsync "github.com/QuarkChain/goquarkchain/cluster/sync"
+	"github.com/QuarkChain/goquarkchain/cluster/telemetry"
 	qkcom "github.com/QuarkChain/goquarkchain/common"
 	"github.com/QuarkChain/goquarkchain/core"
 	"github.com/QuarkChain/goquarkchain/core/types"
@@ -55,6 +56,8 @@ type ProtocolManager struct {
 	quitSync    chan struct{}
 	noMorePeers chan struct{}
 
+	telemetry *telemetry.Publisher
+
 	log string
 	wg  sync.WaitGroup
 }
@@ -176,7 +179,7 @@ func (pm *ProtocolManager) handle(peer *Peer) error {
 	defer pm.removePeer(peer.id)
 	log.Info(pm.log, "peer add succ id ", peer.PeerID())
 
-	err := pm.synchronizer.AddTask(qkcsync.NewRootChainTask(peer, peer.RootHead(), pm.stats, pm.statsChan, pm.slaveConns))
+	err := pm.synchronizer.AddTask(pm.newRootChainTask(peer, peer.RootHead()))
 	if err != nil {
 		return err
 	}
@@ -376,12 +379,59 @@ func (pm *ProtocolManager) handleMsg(peer *Peer) error {
 			log.Warn(fmt.Sprintf("chan for rpc %d is missing", qkcMsg.RpcID))
 		}
 
+	case qkcMsg.Op == p2p.GetTrieNodesRequestMsg:
+		go func() {
+			resp, err := pm.HandleGetTrieNodesRequest(peer.id, qkcMsg.MetaData.Branch, qkcMsg.Data)
+			if err != nil {
+				peer.handleMsgErr = err
+			}
+			err = peer.SendResponseWithData(p2p.GetTrieNodesResponseMsg, p2p.Metadata{Branch: qkcMsg.MetaData.Branch}, qkcMsg.RpcID, resp)
+			if err != nil {
+				peer.handleMsgErr = err
+			}
+		}()
+
+	case qkcMsg.Op == p2p.GetTrieNodesResponseMsg:
+		if c := peer.getChan(qkcMsg.RpcID); c != nil {
+			c <- qkcMsg.Data
+		} else {
+			log.Warn(fmt.Sprintf("chan for rpc %d is missing", qkcMsg.RpcID))
+		}
+
+	case qkcMsg.Op == p2p.GetCrossShardTxListsRequestMsg:
+		go func() {
+			resp, err := pm.HandleGetCrossShardTxListsRequest(peer.id, qkcMsg.MetaData.Branch, qkcMsg.Data)
+			if err != nil {
+				peer.handleMsgErr = err
+			}
+			err = peer.SendResponseWithData(p2p.GetCrossShardTxListsResponseMsg, p2p.Metadata{Branch: qkcMsg.MetaData.Branch}, qkcMsg.RpcID, resp)
+			if err != nil {
+				peer.handleMsgErr = err
+			}
+		}()
+
+	case qkcMsg.Op == p2p.GetCrossShardTxListsResponseMsg:
+		if c := peer.getChan(qkcMsg.RpcID); c != nil {
+			c <- qkcMsg.Data
+		} else {
+			log.Warn(fmt.Sprintf("chan for rpc %d is missing", qkcMsg.RpcID))
+		}
+
 	default:
 		return fmt.Errorf("unknown msg code %d", qkcMsg.Op)
 	}
 	return nil
 }
 
+// newRootChainTask returns the task syncing the root chain up to header from
+// the peer in the sync mode of the cluster.
+func (pm *ProtocolManager) newRootChainTask(peer *Peer, header *types.RootBlockHeader) qkcsync.Task {
+	if pm.clusterConfig.SyncMode == config.SyncModeState {
+		return qkcsync.NewRootChainStateTask(peer, header, pm.stats, pm.statsChan, pm.slaveConns)
+	}
+	return qkcsync.NewRootChainTask(peer, header, pm.stats, pm.statsChan, pm.slaveConns)
+}
+
 func (pm *ProtocolManager) HandleNewRootTip(tip *p2p.Tip, peer *Peer) error {
 	if len(tip.MinorBlockHeaderList) != 0 {
 		return errors.New("minor block header list must not be empty")
@@ -395,7 +445,8 @@ func (pm *ProtocolManager) HandleNewRootTip(tip *p2p.Tip, peer *Peer) error {
 	}
 	peer.SetRootHead(tip.RootBlockHeader)
 	if tip.RootBlockHeader.NumberU64() > pm.rootBlockChain.CurrentBlock().NumberU64() {
-		err := pm.synchronizer.AddTask(qkcsync.NewRootChainTask(peer, tip.RootBlockHeader, pm.stats, pm.statsChan, pm.slaveConns))
+		pm.telemetry.BlockReceived(tip.RootBlockHeader, peer.id)
+		err := pm.synchronizer.AddTask(pm.newRootChainTask(peer, tip.RootBlockHeader))
 		if err != nil {
 			log.Error("Failed to add root chain task,", "hash", tip.RootBlockHeader.Hash(), "height", tip.RootBlockHeader.NumberU64())
 		}
@@ -651,6 +702,24 @@ func (pm *ProtocolManager) HandleGetMinorBlockHeaderListWithSkipRequest(peerId s
 	return conn.GetMinorBlockHeaderListWithSkip(&rpc.P2PRedirectRequest{PeerID: peerId, Branch: branch, Data: data})
 }
 
+func (pm *ProtocolManager) HandleGetTrieNodesRequest(peerId string, branch uint32, data []byte) ([]byte, error) {
+	conn := pm.slaveConns.GetOneSlaveConnById(branch)
+	if conn == nil {
+		return nil, fmt.Errorf("invalid peerID %s for branch request %d", peerId, branch)
+	}
+
+	return conn.GetTrieNodes(&rpc.P2PRedirectRequest{PeerID: peerId, Branch: branch, Data: data})
+}
+
+func (pm *ProtocolManager) HandleGetCrossShardTxListsRequest(peerId string, branch uint32, data []byte) ([]byte, error) {
+	conn := pm.slaveConns.GetOneSlaveConnById(branch)
+	if conn == nil {
+		return nil, fmt.Errorf("invalid peerID %s for branch request %d", peerId, branch)
+	}
+
+	return conn.GetCrossShardTxLists(&rpc.P2PRedirectRequest{PeerID: peerId, Branch: branch, Data: data})
+}
+
 func (pm *ProtocolManager) tipBroadcastLoop() {
 	for {
 		select {
@@ -703,7 +772,7 @@ func (pm *ProtocolManager) synchronise(peer *Peer) {
 		return
 	}
 	if peer.RootHead() != nil {
-		err := pm.synchronizer.AddTask(qkcsync.NewRootChainTask(peer, peer.RootHead(), pm.stats, pm.statsChan, pm.slaveConns))
+		err := pm.synchronizer.AddTask(pm.newRootChainTask(peer, peer.RootHead()))
 		if err != nil {
 			log.Error("AddTask to synchronizer.", "error", err.Error())
 		}
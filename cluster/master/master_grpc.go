@@ -28,6 +28,8 @@ func (m *MasterServerSideOp) AddMinorBlockHeader(ctx context.Context, req *rpc.R
 	m.master.rootBlockChain.AddValidatedMinorBlockHeader(data.MinorBlockHeader.Hash(), data.CoinbaseAmountMap)
 	m.master.UpdateShardStatus(data.ShardStats)
 	m.master.UpdateTxCountHistory(data.TxCount, data.XShardTxCount, data.MinorBlockHeader.Time)
+	fullShardId := data.MinorBlockHeader.Branch.GetFullShardID()
+	m.master.notifyStratum(&fullShardId)
 
 	rsp := new(rpc.AddMinorBlockHeaderResponse)
 	rsp.ArtificialTxConfig = m.master.artificialTxConfig
@@ -111,6 +113,30 @@ func (m *MasterServerSideOp) GetMinorBlockList(ctx context.Context, req *rpc.Req
 	}, nil
 }
 
+func (m *MasterServerSideOp) GetTrieNodes(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	rep := new(rpc.P2PRedirectRequest)
+	if err := serialize.DeserializeFromBytes(req.Data, rep); err != nil {
+		return nil, err
+	}
+	data, err := m.p2pApi.GetTrieNodes(rep)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.Response{Data: data, RpcId: req.RpcId}, nil
+}
+
+func (m *MasterServerSideOp) GetCrossShardTxLists(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	rep := new(rpc.P2PRedirectRequest)
+	if err := serialize.DeserializeFromBytes(req.Data, rep); err != nil {
+		return nil, err
+	}
+	data, err := m.p2pApi.GetCrossShardTxLists(rep)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.Response{Data: data, RpcId: req.RpcId}, nil
+}
+
 func (m *MasterServerSideOp) GetMinorBlockHeaderListWithSkip(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
 	var (
 		err             error
@@ -2,6 +2,8 @@ package master
 
 import (
 	"bou.ke/monkey"
+	"context"
+	"crypto/ecdsa"
 	"errors"
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/cluster/config"
@@ -11,13 +13,18 @@ import (
 	"github.com/QuarkChain/goquarkchain/consensus"
 	"github.com/QuarkChain/goquarkchain/core/rawdb"
 	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
+	"github.com/QuarkChain/goquarkchain/internal/qkcapi"
+	"github.com/QuarkChain/goquarkchain/params"
 	qrpc "github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/QuarkChain/goquarkchain/serialize"
 	eth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 	"math/big"
 	"testing"
 	"time"
@@ -25,8 +32,48 @@ import (
 
 var (
 	testGenesisTokenID = qkcCommon.TokenIDEncode("QKC")
+	testEventRecipient = account.BytesToIdentityRecipient(common.FromHex("0x000000000000000000000000000000000000abcd"))
+	testEventKey       *ecdsa.PrivateKey
+	testEventTx        *types.Transaction
+	// a cross-shard tx from shard 2 to shard 3
+	testXShardTx *types.Transaction
+	// an in-shard tx between full shard keys 2 and 4, both in shard 2
+	testInShardTx *types.Transaction
+	// the txs pending in the tx pools of the slaves
+	testPendingTxs = make(map[common.Hash]*types.Transaction)
+	// the gas prices paid in each token in the recent blocks of the slaves
+	testGasPriceRates = map[uint64]uint64{testGenesisTokenID: 10000000000}
 )
 
+func init() {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	testEventKey = key
+	evmTx, err := types.SignTx(types.NewEvmTransaction(0, testEventRecipient, big.NewInt(1), 21000, big.NewInt(1), 2, 2, 3, 0, nil, 0, 0),
+		types.MakeSigner(3), key)
+	if err != nil {
+		panic(err)
+	}
+	testEventTx = &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}
+	evmTx, err = types.SignTx(types.NewEvmTransaction(1, testEventRecipient, big.NewInt(1), 30000, big.NewInt(1), 2, 3, 3, 0, nil, 0, 0),
+		types.MakeSigner(3), key)
+	if err != nil {
+		panic(err)
+	}
+	testXShardTx = &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}
+	evmTx, err = types.SignTx(types.NewEvmTransaction(2, testEventRecipient, big.NewInt(1), 21000, big.NewInt(1), 2, 4, 3, 0, nil, 0, 0),
+		types.MakeSigner(3), key)
+	if err != nil {
+		panic(err)
+	}
+	testInShardTx = &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}
+	for _, tx := range []*types.Transaction{testEventTx, testXShardTx, testInShardTx} {
+		testPendingTxs[tx.Hash()] = tx
+	}
+}
+
 type fakeRpcClient struct {
 	target       string
 	chainMaskLst []*types.ChainMask
@@ -147,6 +194,14 @@ func (c *fakeRpcClient) Call(hostport string, req *rpc.Request) (*rpc.Response,
 			return nil, err
 		}
 		return &rpc.Response{Data: data}, nil
+	case rpc.OpTraceTransaction, rpc.OpTraceCall, rpc.OpTraceBlockByHash:
+		rsp := new(rpc.TraceResponse)
+		rsp.Result = []byte(`{"gas":21000}`)
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
 	case rpc.OpGetMinorBlock:
 		rsp := new(rpc.GetMinorBlockResponse)
 		rsp.MinorBlock = types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
@@ -156,9 +211,63 @@ func (c *fakeRpcClient) Call(hostport string, req *rpc.Request) (*rpc.Response,
 		}
 		return &rpc.Response{Data: data}, nil
 	case rpc.OpGetTransaction:
+		reqData := new(rpc.GetTransactionRequest)
+		if err := serialize.DeserializeFromBytes(req.Data, reqData); err != nil {
+			return nil, err
+		}
 		rsp := new(rpc.GetTransactionResponse)
-		rsp.MinorBlock = types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
-		rsp.Index = 1
+		if tx, ok := testPendingTxs[reqData.TxHash]; ok {
+			rsp.MinorBlock = types.GetEmptyMinorBlock()
+			rsp.MinorBlock.AddTx(tx)
+		} else {
+			rsp.MinorBlock = types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
+			rsp.Index = 1
+		}
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
+	case rpc.OpGetTxPoolContent:
+		rsp := new(rpc.GetTxPoolContentResponse)
+		evmTx := types.NewEvmTransaction(3, testEventRecipient, big.NewInt(1), 21000, big.NewInt(1), 2, 2, 3, 0, nil, 0, 0)
+		rsp.Pending = []*rpc.TxPoolAccount{{Sender: testEventRecipient, Txs: []*types.Transaction{{EvmTx: evmTx, TxType: types.EvmTx}}}}
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
+	case rpc.OpGetTxPoolStatus:
+		rsp := &rpc.GetTxPoolStatusResponse{Pending: 1, Queued: 2}
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
+	case rpc.OpGetXShardDepositReceipt:
+		reqData := new(rpc.GetTransactionReceiptRequest)
+		if err := serialize.DeserializeFromBytes(req.Data, reqData); err != nil {
+			return nil, err
+		}
+		rsp := new(rpc.GetXShardDepositReceiptResponse)
+		if reqData.TxHash != (common.Hash{}) {
+			rsp.MinorBlock = types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
+			rsp.Receipt = &types.Receipt{TxHash: reqData.TxHash, CumulativeGasUsed: 123}
+		}
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
+	case rpc.OpGetXShardDepositBlock:
+		reqData := new(rpc.GetXShardDepositBlockRequest)
+		if err := serialize.DeserializeFromBytes(req.Data, reqData); err != nil {
+			return nil, err
+		}
+		rsp := new(rpc.GetXShardDepositBlockResponse)
+		if reqData.TxHash != (common.Hash{}) {
+			rsp.Header = &types.MinorBlockHeader{Version: 111, Branch: account.Branch{Value: reqData.Branch}}
+		}
 		data, err := serialize.SerializeToBytes(rsp)
 		if err != nil {
 			return nil, err
@@ -171,10 +280,16 @@ func (c *fakeRpcClient) Call(hostport string, req *rpc.Request) (*rpc.Response,
 			panic(err)
 		}
 		rsp := new(rpc.GetTransactionReceiptResponse)
-		rsp.MinorBlock = types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
-		rsp.Index = 1
-		rsp.Receipt = &types.Receipt{
-			CumulativeGasUsed: 123,
+		if tx, ok := testPendingTxs[reqData.TxHash]; ok {
+			// a pending tx has no receipt
+			rsp.MinorBlock = types.GetEmptyMinorBlock()
+			rsp.MinorBlock.AddTx(tx)
+		} else {
+			rsp.MinorBlock = types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
+			rsp.Index = 1
+			rsp.Receipt = &types.Receipt{
+				CumulativeGasUsed: 123,
+			}
 		}
 		data, err := serialize.SerializeToBytes(rsp)
 		if err != nil {
@@ -202,6 +317,38 @@ func (c *fakeRpcClient) Call(hostport string, req *rpc.Request) (*rpc.Response,
 			return nil, err
 		}
 		return &rpc.Response{Data: data}, nil
+	case rpc.OpNewFilter, rpc.OpNewBlockFilter:
+		rsp := new(rpc.NewFilterResponse)
+		rsp.FilterID = "0xabc"
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
+	case rpc.OpGetFilterChanges:
+		reqData := new(rpc.FilterRequest)
+		if err := serialize.DeserializeFromBytes(req.Data, reqData); err != nil {
+			return nil, err
+		}
+		if reqData.FilterID != "0xabc" {
+			return nil, errors.New("filter not found")
+		}
+		rsp := new(rpc.GetFilterChangesResponse)
+		rsp.Logs = append(rsp.Logs, &types.Log{Data: []byte("qkc")})
+		rsp.Removed = append(rsp.Removed, true)
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
+	case rpc.OpUninstallFilter:
+		rsp := new(rpc.UninstallFilterResponse)
+		rsp.Uninstalled = true
+		data, err := serialize.SerializeToBytes(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
 	case rpc.OpEstimateGas:
 		rsp := new(rpc.EstimateGasResponse)
 		rsp.Result = 123
@@ -234,6 +381,20 @@ func (c *fakeRpcClient) Call(hostport string, req *rpc.Request) (*rpc.Response,
 			return nil, err
 		}
 		return &rpc.Response{Data: data}, nil
+	case rpc.OpGasPriceRate:
+		reqData := new(rpc.GasPriceRequest)
+		if err := serialize.DeserializeFromBytes(req.Data, reqData); err != nil {
+			return nil, err
+		}
+		rate, ok := testGasPriceRates[reqData.TokenID]
+		if !ok {
+			return nil, errors.New("no gas price paid in the token")
+		}
+		data, err := serialize.SerializeToBytes(&rpc.GasPriceResponse{Result: rate})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Response{Data: data}, nil
 	case rpc.OpMasterInfo:
 		rsp := new(rpc.MasterInfo)
 		data, err := serialize.SerializeToBytes(rsp)
@@ -262,6 +423,54 @@ func (c *fakeRpcClient) Call(hostport string, req *rpc.Request) (*rpc.Response,
 	}
 }
 
+// fakeEventsStream streams the given events, then blocks until it is closed.
+type fakeEventsStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events []*rpc.ShardEvent
+}
+
+func (s *fakeEventsStream) Recv() (*rpc.Response, error) {
+	if len(s.events) == 0 {
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	data, err := serialize.SerializeToBytes(s.events[0])
+	if err != nil {
+		return nil, err
+	}
+	s.events = s.events[1:]
+	return &rpc.Response{Data: data}, nil
+}
+
+// SubscribeEvents streams one event of the requested type for each shard of the slave.
+func (c *fakeRpcClient) SubscribeEvents(ctx context.Context, hostport string, req *rpc.Request) (rpc.SlaveServerSideOp_SubscribeEventsClient, error) {
+	reqData := new(rpc.SubscribeEventsRequest)
+	if err := serialize.DeserializeFromBytes(req.Data, reqData); err != nil {
+		return nil, err
+	}
+	stream := &fakeEventsStream{ctx: ctx}
+	for _, branch := range c.branchs {
+		ev := &rpc.ShardEvent{Branch: branch.Value}
+		switch reqData.Type {
+		case rpc.NewHeadsEvent:
+			ev.Header = types.GetEmptyMinorBlock().Header()
+			ev.Header.Branch = *branch
+		case rpc.LogsEvent:
+			ev.Logs = []*types.Log{
+				{Recipient: testEventRecipient, Data: []byte("qkc")},
+				{Recipient: account.Recipient{}, Data: []byte("qkc")},
+			}
+		case rpc.PendingTxsEvent:
+			ev.Txs = []*types.Transaction{testEventTx}
+		default:
+			return nil, errors.New("unknown event type")
+		}
+		stream.events = append(stream.events, ev)
+	}
+	return stream, nil
+}
+
 func initEnv(t *testing.T, chanOp chan uint32) *QKCMasterBackend {
 	return initEnvWithConsensusType(t, chanOp, config.PoWSimulate, "")
 }
@@ -528,6 +737,88 @@ func TestGetTransactionReceipt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, MinorBlock.Hash(), fakeMinorBlock.Hash())
 	assert.Equal(t, rep.CumulativeGasUsed, uint64(123))
+
+	// the receipt of a pending tx is nil
+	MinorBlock, index, rep, err := master.GetTransactionReceipt(testEventTx.Hash(), account.Branch{Value: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), index)
+	assert.Equal(t, testEventTx.Hash(), MinorBlock.Transactions()[index].Hash())
+	assert.Nil(t, rep)
+}
+
+func TestGetXShardDepositBlock(t *testing.T) {
+	master := initEnv(t, nil)
+	header, err := master.GetXShardDepositBlock(account.Branch{Value: 2}, common.Hash{}, common.Hash{}, common.HexToHash("0x01"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(111), header.Version)
+	assert.Equal(t, uint32(2), header.Branch.Value)
+	header, err = master.GetXShardDepositBlock(account.Branch{Value: 2}, common.Hash{}, common.Hash{}, common.Hash{})
+	assert.NoError(t, err)
+	assert.Nil(t, header)
+	_, err = master.GetXShardDepositBlock(account.Branch{Value: 222222222}, common.Hash{}, common.Hash{}, common.Hash{})
+	assert.Error(t, err)
+}
+
+func TestGetXShardDepositReceipt(t *testing.T) {
+	master := initEnv(t, nil)
+	txHash := common.HexToHash("0x01")
+	fakeMinorBlock := types.NewMinorBlock(&types.MinorBlockHeader{Version: 111}, &types.MinorBlockMeta{}, nil, nil, nil)
+	block, _, receipt, err := master.GetXShardDepositReceipt(txHash, account.Branch{Value: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, fakeMinorBlock.Hash(), block.Hash())
+	assert.Equal(t, txHash, receipt.TxHash)
+	block, _, receipt, err = master.GetXShardDepositReceipt(common.Hash{}, account.Branch{Value: 2})
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+	assert.Nil(t, receipt)
+	_, _, _, err = master.GetXShardDepositReceipt(txHash, account.Branch{Value: 222222222})
+	assert.Error(t, err)
+}
+
+func TestGetTxPool(t *testing.T) {
+	master := initEnv(t, nil)
+	pending, queued, err := master.GetTxPoolContent(account.Branch{Value: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(queued))
+	assert.Equal(t, 1, len(pending))
+	assert.Equal(t, testEventRecipient, pending[0].Sender)
+	assert.Equal(t, uint64(3), pending[0].Txs[0].EvmTx.Nonce())
+	_, _, err = master.GetTxPoolContent(account.Branch{Value: 222222222})
+	assert.Error(t, err)
+
+	pendingCount, queuedCount, err := master.GetTxPoolStatus(account.Branch{Value: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), pendingCount)
+	assert.Equal(t, uint32(2), queuedCount)
+	_, _, err = master.GetTxPoolStatus(account.Branch{Value: 222222222})
+	assert.Error(t, err)
+}
+
+func TestTraceTransaction(t *testing.T) {
+	master := initEnv(t, nil)
+	id1, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	add1 := account.NewAddress(id1.GetRecipient(), 3)
+	evmTx := types.NewEvmTransaction(0, id1.GetRecipient(), new(big.Int), 0, new(big.Int), 2, 2, 1, 0, []byte{}, 0, 0)
+	tx := &types.Transaction{
+		EvmTx:  evmTx,
+		TxType: types.EvmTx,
+	}
+	data, err := master.TraceTransaction(tx.Hash(), account.Branch{Value: 2}, &rpc.TraceConfig{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"gas":21000}`), data)
+	_, err = master.TraceTransaction(tx.Hash(), account.Branch{Value: 222222222}, nil)
+	assert.Error(t, err)
+
+	data, err = master.TraceCall(tx, &add1, nil, &rpc.TraceConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"gas":21000}`), data)
+
+	data, err = master.TraceBlockByHash(common.Hash{}, account.Branch{Value: 2}, &rpc.TraceConfig{Tracer: "callTracer"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"gas":21000}`), data)
+	_, err = master.TraceBlockByHash(common.Hash{}, account.Branch{Value: 222222222}, nil)
+	assert.Error(t, err)
 }
 
 func TestGetTransactionsByAddress(t *testing.T) {
@@ -557,6 +848,112 @@ func TestGetLogs(t *testing.T) {
 	assert.Equal(t, logs[0].Data, []byte("qkc"))
 }
 
+func TestFilters(t *testing.T) {
+	master := initEnv(t, nil)
+
+	id, err := master.NewFilter(&qrpc.FilterQuery{FullShardId: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, qrpc.ID("0x00000002abc"), id)
+	_, err = master.NewBlockFilter(account.Branch{Value: 222222222})
+	assert.Error(t, err)
+	id, err = master.NewBlockFilter(account.Branch{Value: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, qrpc.ID("0x00000002abc"), id)
+
+	hashes, logs, err := master.GetFilterChanges(id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(hashes))
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, []byte("qkc"), logs[0].Data)
+	assert.True(t, logs[0].Removed)
+	for _, invalid := range []qrpc.ID{"0xabc", "0x0000000gabc", "0x0d4a11d5abc"} {
+		_, _, err = master.GetFilterChanges(invalid)
+		assert.Equal(t, ErrFilterNotFound, err)
+	}
+
+	uninstalled, err := master.UninstallFilter(id)
+	assert.NoError(t, err)
+	assert.True(t, uninstalled)
+	uninstalled, err = master.UninstallFilter("0xabc")
+	assert.NoError(t, err)
+	assert.False(t, uninstalled)
+}
+
+func TestSubscriptions(t *testing.T) {
+	master := initEnv(t, nil)
+	server := qrpc.NewServer()
+	assert.NoError(t, server.RegisterName("ws", NewPublicFilterAPI(master)))
+	client := qrpc.DialInProc(server)
+	defer client.Close()
+
+	ctx := context.Background()
+	fullShardIds := master.clusterConfig.Quarkchain.GetGenesisShardIds()
+	receive := func(ch chan map[string]interface{}, count int) []map[string]interface{} {
+		fields := make([]map[string]interface{}, 0, count)
+		for len(fields) < count {
+			select {
+			case field := <-ch:
+				fields = append(fields, field)
+			case <-time.After(5 * time.Second):
+				t.Fatal("subscription timeout")
+			}
+		}
+		return fields
+	}
+
+	// the heads of all the shards
+	ch := make(chan map[string]interface{})
+	sub, err := client.Subscribe(ctx, "ws", ch, "newHeads")
+	assert.NoError(t, err)
+	shards := make(map[string]bool)
+	for _, field := range receive(ch, len(fullShardIds)) {
+		shards[field["fullShardId"].(string)] = true
+	}
+	assert.Equal(t, len(fullShardIds), len(shards))
+	sub.Unsubscribe()
+
+	// the heads of one shard
+	_, err = client.Subscribe(ctx, "ws", ch, "newHeads", hexutil.Uint(222222222))
+	assert.Error(t, err)
+	ch = make(chan map[string]interface{})
+	sub, err = client.Subscribe(ctx, "ws", ch, "newHeads", hexutil.Uint(fullShardIds[1]))
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(fullShardIds[1]).String(), receive(ch, 1)[0]["fullShardId"])
+	sub.Unsubscribe()
+
+	// the logs of all the shards matching the address
+	ch = make(chan map[string]interface{})
+	sub, err = client.Subscribe(ctx, "ws", ch, "logs", map[string]interface{}{"address": testEventRecipient})
+	assert.NoError(t, err)
+	for _, field := range receive(ch, len(fullShardIds)) {
+		assert.Equal(t, hexutil.Bytes("qkc").String(), field["data"])
+		assert.Equal(t, testEventRecipient.Hex(), common.HexToAddress(field["recipient"].(string)).Hex())
+	}
+	sub.Unsubscribe()
+
+	// the pending txs of one shard
+	ch = make(chan map[string]interface{})
+	sub, err = client.Subscribe(ctx, "ws", ch, "newPendingTransactions", hexutil.Uint(fullShardIds[0]))
+	assert.NoError(t, err)
+	field := receive(ch, 1)[0]
+	assert.Equal(t, testEventTx.Hash().Hex(), field["hash"])
+	assert.Equal(t, hexutil.Uint64(fullShardIds[0]).String(), field["fullShardId"])
+	sub.Unsubscribe()
+
+	// the root heads
+	ch = make(chan map[string]interface{})
+	sub, err = client.Subscribe(ctx, "ws", ch, "newRootHeads")
+	assert.NoError(t, err)
+	id1, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	add1 := account.NewAddress(id1.GetRecipient(), 3)
+	rootBlock, err := master.rootBlockChain.CreateBlockToMine(nil, &add1, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, master.AddRootBlock(rootBlock))
+	assert.Equal(t, rootBlock.Hash().Hex(), receive(ch, 1)[0]["hash"])
+	sub.Unsubscribe()
+}
+
 func TestEstimateGas(t *testing.T) {
 	master := initEnv(t, nil)
 	id1, err := account.CreatRandomIdentity()
@@ -575,6 +972,17 @@ func TestEstimateGas(t *testing.T) {
 		assert.Equal(t, data, uint32(123+9000))
 	}
 
+	// a cross-shard tx is estimated in the target shard with the x-shard cost
+	evmTx = types.NewEvmTransaction(0, id1.GetRecipient(), new(big.Int), 0, new(big.Int), 0, 1, 1, 0, []byte{}, 0, 0)
+	tx = &types.Transaction{
+		EvmTx:  evmTx,
+		TxType: types.EvmTx,
+	}
+	data, err = master.EstimateGas(tx, &add1)
+	assert.NoError(t, err)
+	assert.True(t, tx.EvmTx.IsCrossShard())
+	assert.Equal(t, uint32(123+9000), data)
+
 	evmTx = types.NewEvmTransaction(0, id1.GetRecipient(), new(big.Int), 0, new(big.Int), 2222222, 2, 1, 0, []byte{}, 0, 0)
 	tx = &types.Transaction{
 		EvmTx:  evmTx,
@@ -612,6 +1020,107 @@ func TestGasPrice(t *testing.T) {
 	assert.Equal(t, data, uint64(123))
 }
 
+func TestGasPriceRate(t *testing.T) {
+	master := initEnv(t, nil)
+	rate, err := master.GasPriceRate(account.Branch{Value: 2}, testGenesisTokenID)
+	assert.NoError(t, err)
+	assert.Equal(t, testGasPriceRates[testGenesisTokenID], rate)
+	_, err = master.GasPriceRate(account.Branch{Value: 2}, 0)
+	assert.Error(t, err)
+	_, err = master.GasPriceRate(account.Branch{Value: 222222222}, testGenesisTokenID)
+	assert.Error(t, err)
+}
+
+func dialQkcAPI(t *testing.T, master *QKCMasterBackend) *qrpc.Client {
+	server := qrpc.NewServer()
+	for _, api := range qkcapi.GetAPIs(master) {
+		if api.Namespace == "qkc" && api.Public {
+			assert.NoError(t, server.RegisterName(api.Namespace, api.Service))
+		}
+	}
+	return qrpc.DialInProc(server)
+}
+
+func TestGetCrossShardTransactionStatus(t *testing.T) {
+	master := initEnv(t, nil)
+	client := dialQkcAPI(t, master)
+	defer client.Close()
+
+	var status map[string]interface{}
+	txID := hexutil.Bytes(encoder.IDEncoder(testXShardTx.Hash().Bytes(), 2))
+	assert.NoError(t, client.Call(&status, "qkc_getCrossShardTransactionStatus", txID))
+	assert.Equal(t, "pending", status["status"])
+	assert.Equal(t, "0x2", status["fromFullShardId"])
+	assert.Equal(t, "0x3", status["toFullShardId"])
+	assert.Nil(t, status["minorBlock"])
+
+	txID = hexutil.Bytes(encoder.IDEncoder(testInShardTx.Hash().Bytes(), 2))
+	err := client.Call(&status, "qkc_getCrossShardTransactionStatus", txID)
+	assert.EqualError(t, err, "not a cross-shard transaction")
+}
+
+func TestGetCrossShardDepositReceipt(t *testing.T) {
+	master := initEnv(t, nil)
+	client := dialQkcAPI(t, master)
+	defer client.Close()
+
+	var receipt map[string]interface{}
+	txID := hexutil.Bytes(encoder.IDEncoder(testXShardTx.Hash().Bytes(), 2))
+	assert.NoError(t, client.Call(&receipt, "qkc_getCrossShardDepositReceipt", txID))
+	assert.Equal(t, txID.String(), receipt["transactionId"])
+	assert.Equal(t, testXShardTx.Hash().Hex(), receipt["transactionHash"])
+	assert.Equal(t, "0x7b", receipt["cumulativeGasUsed"])
+
+	txID = hexutil.Bytes(encoder.IDEncoder(testInShardTx.Hash().Bytes(), 2))
+	err := client.Call(&receipt, "qkc_getCrossShardDepositReceipt", txID)
+	assert.EqualError(t, err, "not a cross-shard transaction")
+}
+
+func TestCancelTransaction(t *testing.T) {
+	master := initEnv(t, nil)
+	client := dialQkcAPI(t, master)
+	defer client.Close()
+
+	// testEventTx is pending in shard 2, paying gas in token 0
+	txID := hexutil.Bytes(encoder.IDEncoder(testEventTx.Hash().Bytes(), 2))
+	var cancel map[string]interface{}
+	err := client.Call(&cancel, "qkc_getCancelTransaction", txID, nil)
+	assert.Error(t, err, "token 0 is not allowed")
+	// no gas price is paid in token 0 to convert the price of the tx to QKC
+	err = client.Call(&cancel, "qkc_getCancelTransaction", txID, hexutil.Uint64(testGenesisTokenID))
+	assert.Error(t, err)
+
+	testGasPriceRates[0] = 1
+	defer delete(testGasPriceRates, 0)
+	assert.NoError(t, client.Call(&cancel, "qkc_getCancelTransaction", txID, hexutil.Uint64(testGenesisTokenID)))
+	sender := crypto.PubkeyToAddress(testEventKey.PublicKey)
+	// the price of the tx in QKC bumped by 10%
+	price := big.NewInt(11000000000)
+	assert.Equal(t, hexutil.Bytes(sender.Bytes()).String(), cancel["to"])
+	assert.Equal(t, "0x0", cancel["nonce"])
+	assert.Equal(t, hexutil.EncodeBig(price), cancel["gasPrice"])
+	assert.Equal(t, hexutil.Uint64(testGenesisTokenID).String(), cancel["gas_token_id"])
+
+	evmTx, err := types.SignTx(types.NewEvmTransaction(0, account.Recipient(sender), new(big.Int), params.DefaultInShardTxGasLimit.Uint64(),
+		price, 2, 2, 3, 0, nil, testGenesisTokenID, testGenesisTokenID), types.MakeSigner(3), testEventKey)
+	assert.NoError(t, err)
+	assert.Equal(t, types.MakeSigner(3).Hash(evmTx).Hex(), cancel["hashToSign"])
+	v, r, s := evmTx.RawSignatureValues()
+	args := map[string]interface{}{
+		"gas_token_id": hexutil.Uint64(testGenesisTokenID),
+		"v":            (*hexutil.Big)(v),
+		"r":            (*hexutil.Big)(r),
+		"s":            (*hexutil.Big)(s),
+	}
+	var id hexutil.Bytes
+	err = client.Call(&id, "qkc_cancelTransaction", txID, args)
+	assert.EqualError(t, err, "missing gasPrice")
+	args["gasPrice"] = (*hexutil.Big)(price)
+	assert.NoError(t, client.Call(&id, "qkc_cancelTransaction", txID, args))
+	tx := &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}
+	assert.Equal(t, hexutil.Bytes(encoder.IDEncoder(tx.Hash().Bytes(), 2)), id)
+}
+
 func TestGetWork(t *testing.T) {
 	master := initEnv(t, nil)
 	var id uint32 = 2
@@ -73,6 +73,22 @@ func (api *PrivateP2PAPI) GetMinorBlockList(req *rpc.P2PRedirectRequest) ([]byte
 	return data, err
 }
 
+func (api *PrivateP2PAPI) GetTrieNodes(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	peer := api.peers.Peer(req.PeerID)
+	if peer == nil {
+		return nil, errNotRegistered
+	}
+	return peer.GetTrieNodes(req)
+}
+
+func (api *PrivateP2PAPI) GetCrossShardTxLists(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	peer := api.peers.Peer(req.PeerID)
+	if peer == nil {
+		return nil, errNotRegistered
+	}
+	return peer.GetCrossShardTxLists(req)
+}
+
 func (api *PrivateP2PAPI) GetMinorBlockHeaderListWithSkip(req *rpc.P2PRedirectRequest) ([]byte, error) {
 	peer := api.peers.Peer(req.PeerID)
 	if peer == nil {
@@ -17,6 +17,7 @@ import (
 	"github.com/QuarkChain/goquarkchain/p2p/nodefilter"
 	"github.com/QuarkChain/goquarkchain/serialize"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
 )
 
 var (
@@ -24,6 +25,8 @@ var (
 	errAlreadyRegistered = errors.New("peer is already registered")
 	errNotRegistered     = errors.New("peer is not registered")
 	errTimeout           = errors.New("request timeout")
+
+	peerCountGauge = metrics.NewRegisteredGauge("p2p/peers", nil)
 )
 
 const (
@@ -442,6 +445,59 @@ func (p *Peer) GetMinorBlockList(req *rpc.P2PRedirectRequest) ([]byte, error) {
 	}
 }
 
+func (p *Peer) requestWithSerializedData(op p2p.P2PCommandOp, rpcId uint64, req *rpc.P2PRedirectRequest) error {
+	msg, err := p2p.MakeMsgWithSerializedData(op, rpcId, p2p.Metadata{Branch: req.Branch}, req.Data)
+	if err != nil {
+		return err
+	}
+	return p.rw.WriteMsg(msg)
+}
+
+// GetTrieNodes requests the state trie nodes of a shard for state sync.
+func (p *Peer) GetTrieNodes(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	rpcId, rpcchan := p.getRpcIdWithChan()
+	defer p.deleteChan(rpcId)
+
+	if err := p.requestWithSerializedData(p2p.GetTrieNodesRequestMsg, rpcId, req); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(requestTimeout)
+	select {
+	case obj := <-rpcchan:
+		if ret, ok := obj.([]byte); !ok {
+			panic("invalid return result in GetTrieNodes")
+		} else {
+			return ret, nil
+		}
+	case <-timeout.C:
+		return nil, fmt.Errorf("peer %v return GetTrieNodes disc Read Time out for rpcid %d", p.id, rpcId)
+	}
+}
+
+// GetCrossShardTxLists requests the xshard tx lists a shard keeps for its
+// neighbor minor blocks.
+func (p *Peer) GetCrossShardTxLists(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	rpcId, rpcchan := p.getRpcIdWithChan()
+	defer p.deleteChan(rpcId)
+
+	if err := p.requestWithSerializedData(p2p.GetCrossShardTxListsRequestMsg, rpcId, req); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(requestTimeout)
+	select {
+	case obj := <-rpcchan:
+		if ret, ok := obj.([]byte); !ok {
+			panic("invalid return result in GetCrossShardTxLists")
+		} else {
+			return ret, nil
+		}
+	case <-timeout.C:
+		return nil, fmt.Errorf("peer %v return GetCrossShardTxLists disc Read Time out for rpcid %d", p.id, rpcId)
+	}
+}
+
 func (p *Peer) SendResponseWithData(op p2p.P2PCommandOp, metadata p2p.Metadata, rpcId uint64, data []byte) error {
 	msg, err := p2p.MakeMsgWithSerializedData(op, rpcId, metadata, data)
 	if err != nil {
@@ -577,6 +633,7 @@ func (ps *peerSet) Register(p *Peer) error {
 		return errAlreadyRegistered
 	}
 	ps.peers[p.id] = p
+	peerCountGauge.Update(int64(len(ps.peers)))
 	go p.broadcast()
 
 	return nil
@@ -593,6 +650,7 @@ func (ps *peerSet) Unregister(id string) error {
 		return errNotRegistered
 	}
 	delete(ps.peers, id)
+	peerCountGauge.Update(int64(len(ps.peers)))
 	p.close()
 
 	return nil
@@ -1,6 +1,7 @@
 package master
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -293,6 +294,82 @@ func (s *SlaveConnection) GetTransactionReceipt(txHash common.Hash, branch accou
 	return rsp.MinorBlock, rsp.Index, rsp.Receipt, nil
 }
 
+func (s *SlaveConnection) GetXShardDepositReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error) {
+	var (
+		req = rpc.GetTransactionReceiptRequest{Branch: branch.Value, TxHash: txHash}
+		rsp = new(rpc.GetXShardDepositReceiptResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetXShardDepositReceipt, Data: bytes})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, 0, nil, err
+	}
+	return rsp.MinorBlock, rsp.Index, rsp.Receipt, nil
+}
+
+func (s *SlaveConnection) GetTxPoolContent(branch account.Branch) ([]*rpc.TxPoolAccount, []*rpc.TxPoolAccount, error) {
+	var (
+		req = rpc.TxPoolRequest{Branch: branch.Value}
+		rsp = new(rpc.GetTxPoolContentResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetTxPoolContent, Data: bytes})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, nil, err
+	}
+	return rsp.Pending, rsp.Queued, nil
+}
+
+func (s *SlaveConnection) GetTxPoolStatus(branch account.Branch) (uint32, uint32, error) {
+	var (
+		req = rpc.TxPoolRequest{Branch: branch.Value}
+		rsp = new(rpc.GetTxPoolStatusResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetTxPoolStatus, Data: bytes})
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return 0, 0, err
+	}
+	return rsp.Pending, rsp.Queued, nil
+}
+
+func (s *SlaveConnection) GetXShardDepositBlock(branch account.Branch, rHash, mHash, txHash common.Hash) (*types.MinorBlockHeader, error) {
+	var (
+		req = rpc.GetXShardDepositBlockRequest{Branch: branch.Value, RootBlockHash: rHash, MinorBlockHash: mHash, TxHash: txHash}
+		rsp = new(rpc.GetXShardDepositBlockResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetXShardDepositBlock, Data: bytes})
+	if err != nil {
+		return nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Header, nil
+}
+
 func (s *SlaveConnection) GetTransactionsByAddress(address *account.Address, start []byte, limit uint32, transferTokenID *uint64) ([]*rpc.TransactionDetail, []byte, error) {
 	var (
 		req   = rpc.GetTransactionListByAddressRequest{Address: address, TransferTokenID: transferTokenID, Start: start, Limit: limit}
@@ -355,6 +432,109 @@ func (s *SlaveConnection) GetLogs(args *qrpc.FilterQuery) ([]*types.Log, error)
 
 }
 
+func (s *SlaveConnection) NewFilter(args *qrpc.FilterQuery) (string, error) {
+	rsp := new(rpc.NewFilterResponse)
+	bytes, err := serialize.SerializeToBytes(args)
+	if err != nil {
+		return "", err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpNewFilter, Data: bytes})
+	if err != nil {
+		return "", err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return "", err
+	}
+	return rsp.FilterID, nil
+}
+
+func (s *SlaveConnection) NewBlockFilter(branch account.Branch) (string, error) {
+	var (
+		req = rpc.NewBlockFilterRequest{Branch: branch.Value}
+		rsp = new(rpc.NewFilterResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return "", err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpNewBlockFilter, Data: bytes})
+	if err != nil {
+		return "", err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return "", err
+	}
+	return rsp.FilterID, nil
+}
+
+func (s *SlaveConnection) GetFilterChanges(filterID string) ([]common.Hash, []*types.Log, error) {
+	var (
+		req = rpc.FilterRequest{FilterID: filterID}
+		rsp = new(rpc.GetFilterChangesResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetFilterChanges, Data: bytes})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, nil, err
+	}
+	if len(rsp.Removed) != len(rsp.Logs) {
+		return nil, nil, errors.New("GetFilterChanges: removed flags do not match logs")
+	}
+	for i, l := range rsp.Logs {
+		l.Removed = rsp.Removed[i]
+	}
+	return rsp.Hashes, rsp.Logs, nil
+}
+
+func (s *SlaveConnection) UninstallFilter(filterID string) (bool, error) {
+	var (
+		req = rpc.FilterRequest{FilterID: filterID}
+		rsp = new(rpc.UninstallFilterResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return false, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpUninstallFilter, Data: bytes})
+	if err != nil {
+		return false, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return false, err
+	}
+	return rsp.Uninstalled, nil
+}
+
+// SubscribeEvents streams the events of type typ of all the shards of the slave
+// to handle until ctx is done or the stream breaks.
+func (s *SlaveConnection) SubscribeEvents(ctx context.Context, typ uint8, handle func(*rpc.ShardEvent)) error {
+	bytes, err := serialize.SerializeToBytes(rpc.SubscribeEventsRequest{Type: typ})
+	if err != nil {
+		return err
+	}
+	stream, err := s.client.SubscribeEvents(ctx, s.target, &rpc.Request{Data: bytes})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		ev := new(rpc.ShardEvent)
+		if err = serialize.DeserializeFromBytes(res.Data, ev); err != nil {
+			return err
+		}
+		handle(ev)
+	}
+}
+
 func (s *SlaveConnection) EstimateGas(tx *types.Transaction, fromAddress *account.Address) (uint32, error) {
 	var (
 		req = rpc.EstimateGasRequest{
@@ -440,6 +620,27 @@ func (s *SlaveConnection) GasPrice(branch account.Branch, tokenID uint64) (uint6
 	return rsp.Result, err
 }
 
+func (s *SlaveConnection) GasPriceRate(branch account.Branch, tokenID uint64) (uint64, error) {
+	var (
+		req = rpc.GasPriceRequest{
+			Branch:  branch.Value,
+			TokenID: tokenID,
+		}
+		rsp = new(rpc.GasPriceResponse)
+		res = new(rpc.Response)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return 0, err
+	}
+	res, err = s.client.Call(s.target, &rpc.Request{Op: rpc.OpGasPriceRate, Data: bytes})
+	if err != nil {
+		return 0, err
+	}
+	err = serialize.Deserialize(serialize.NewByteBuffer(res.Data), rsp)
+	return rsp.Result, err
+}
+
 func (s *SlaveConnection) GetWork(branch account.Branch, coinbaseAddr *account.Address) (*consensus.MiningWork, error) {
 	var (
 		req = rpc.GetWorkRequest{
@@ -482,6 +683,26 @@ func (s *SlaveConnection) SubmitWork(work *rpc.SubmitWorkRequest) (success bool,
 	return gRes.Success, nil
 }
 
+func (s *SlaveConnection) SubmitShare(share *rpc.SubmitShareRequest) (block bool, err error) {
+	var (
+		gRes  rpc.SubmitShareResponse
+		bytes []byte
+		res   *rpc.Response
+	)
+	bytes, err = serialize.SerializeToBytes(share)
+	if err != nil {
+		return
+	}
+	res, err = s.client.Call(s.target, &rpc.Request{Op: rpc.OpSubmitShare, Data: bytes})
+	if err != nil {
+		return
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, &gRes); err != nil {
+		return
+	}
+	return gRes.Block, nil
+}
+
 func (s *SlaveConnection) SendMiningConfigToSlaves(artificialTxConfig *rpc.ArtificialTxConfig, mining bool) error {
 	var (
 		req = rpc.MineRequest{
@@ -615,6 +836,30 @@ func (s *SlaveConnection) GetMinorBlockHeaderListWithSkip(req *rpc.P2PRedirectRe
 	return res.Data, nil
 }
 
+func (s *SlaveConnection) GetTrieNodes(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetTrieNodes, Data: bytes})
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+func (s *SlaveConnection) GetCrossShardTxLists(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpGetCrossShardTxLists, Data: bytes})
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
 func (s *SlaveConnection) GetMinorBlockHeaderList(req *rpc.P2PRedirectRequest) ([]byte, error) {
 	bytes, err := serialize.SerializeToBytes(req)
 	if err != nil {
@@ -727,3 +972,60 @@ func (s *SlaveConnection) GetRootChainStakes(address account.Address, lastMinor
 	}
 	return getRootChainStakesResponse.Stakes, getRootChainStakesResponse.Signer, nil
 }
+
+func (s *SlaveConnection) TraceTransaction(txHash common.Hash, branch account.Branch, config *rpc.TraceConfig) ([]byte, error) {
+	var (
+		req = rpc.TraceTransactionRequest{TxHash: txHash, Branch: branch.Value, Config: config}
+		rsp = new(rpc.TraceResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpTraceTransaction, Data: bytes})
+	if err != nil {
+		return nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Result, nil
+}
+
+func (s *SlaveConnection) TraceBlockByHash(blockHash common.Hash, branch account.Branch, config *rpc.TraceConfig) ([]byte, error) {
+	var (
+		req = rpc.TraceBlockByHashRequest{BlockHash: blockHash, Branch: branch.Value, Config: config}
+		rsp = new(rpc.TraceResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpTraceBlockByHash, Data: bytes})
+	if err != nil {
+		return nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Result, nil
+}
+
+func (s *SlaveConnection) TraceCall(tx *types.Transaction, fromAddress *account.Address, height *uint64, config *rpc.TraceConfig) ([]byte, error) {
+	var (
+		req = rpc.TraceCallRequest{Tx: tx, FromAddress: fromAddress, BlockHeight: height, Config: config}
+		rsp = new(rpc.TraceResponse)
+	)
+	bytes, err := serialize.SerializeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Call(s.target, &rpc.Request{Op: rpc.OpTraceCall, Data: bytes})
+	if err != nil {
+		return nil, err
+	}
+	if err = serialize.DeserializeFromBytes(res.Data, rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Result, nil
+}
@@ -1,6 +1,7 @@
 package miner
 
 import (
+	"errors"
 	"math/big"
 	"runtime"
 	"sync"
@@ -164,6 +165,10 @@ func (m *Miner) GetWork(coinbaseAddr *account.Address) (*consensus.MiningWork, e
 	}
 
 	work, err := m.engine.GetWork(addrForGetWork)
+	if err == nil && work.Number <= m.getTip() {
+		// the tip moved past the work before HandleNewTip refreshed it
+		err = consensus.ErrNoMiningWork
+	}
 	if err != nil {
 		if err == consensus.ErrNoMiningWork {
 			block, diff, optionalDivider, err := m.api.CreateBlockToMine(&addrForGetWork)
@@ -186,6 +191,15 @@ func (m *Miner) SubmitWork(nonce uint64, hash, digest common.Hash, signature *[6
 	return m.engine.SubmitWork(nonce, hash, digest, signature)
 }
 
+// SubmitShare submits a share of remote mining with a difficulty lower than the
+// one of the block, and returns whether it sealed the block.
+func (m *Miner) SubmitShare(nonce uint64, hash, digest common.Hash, shareDiff *big.Int) (bool, error) {
+	if !m.IsMining() || m.api.IsSyncing() {
+		return false, errors.New("not mining")
+	}
+	return m.engine.SubmitShare(nonce, hash, digest, shareDiff)
+}
+
 func (m *Miner) HandleNewTip() {
 	log.Debug(m.logInfo, "handle new tip: height", m.getTip())
 	m.engine.RefreshWork(m.api.GetTip())
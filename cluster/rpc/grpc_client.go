@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 )
@@ -60,6 +61,21 @@ const (
 	OpSetMining
 	OpAddMinorBlockHeaderList
 	OpCheckMinorBlocksInRoot
+	OpTraceTransaction
+	OpTraceCall
+	OpTraceBlockByHash
+	OpNewFilter
+	OpNewBlockFilter
+	OpGetFilterChanges
+	OpUninstallFilter
+	OpGetXShardDepositBlock
+	OpGetXShardDepositReceipt
+	OpGetTrieNodes
+	OpGetCrossShardTxLists
+	OpGetTxPoolContent
+	OpGetTxPoolStatus
+	OpSubmitShare
+	OpGasPriceRate
 
 	MasterServer = serverType(1)
 	SlaveServer  = serverType(0)
@@ -79,6 +95,8 @@ var (
 		OpGetMinorBlockList:               {name: "GetMinorBlockList"},
 		OpGetMinorBlockHeaderList:         {name: "GetMinorBlockHeaderList"},
 		OpGetMinorBlockHeaderListWithSkip: {name: "GetMinorBlockHeaderListWithSkip"},
+		OpGetTrieNodes:                    {name: "GetTrieNodes"},
+		OpGetCrossShardTxLists:            {name: "GetCrossShardTxLists"},
 	}
 	// slave apis
 	slaveApis = map[uint32]opType{
@@ -112,6 +130,19 @@ var (
 		OpSetMining:                   {name: "SetMining"},
 		OpCheckMinorBlocksInRoot:      {name: "CheckMinorBlocksInRoot"},
 		OpGetRootChainStakes:          {name: "GetRootChainStakes"},
+		OpTraceTransaction:            {name: "TraceTransaction"},
+		OpTraceCall:                   {name: "TraceCall"},
+		OpTraceBlockByHash:            {name: "TraceBlockByHash"},
+		OpNewFilter:                   {name: "NewFilter"},
+		OpNewBlockFilter:              {name: "NewBlockFilter"},
+		OpGetFilterChanges:            {name: "GetFilterChanges"},
+		OpUninstallFilter:             {name: "UninstallFilter"},
+		OpGetXShardDepositBlock:       {name: "GetXShardDepositBlock"},
+		OpGetXShardDepositReceipt:     {name: "GetXShardDepositReceipt"},
+		OpGetTxPoolContent:            {name: "GetTxPoolContent"},
+		OpGetTxPoolStatus:             {name: "GetTxPoolStatus"},
+		OpSubmitShare:                 {name: "SubmitShare"},
+		OpGasPriceRate:                {name: "GasPriceRate"},
 		// p2p api
 		OpGetMinorBlockList:               {name: "GetMinorBlockList"},
 		OpGetMinorBlockHeaderList:         {name: "GetMinorBlockHeaderList"},
@@ -119,6 +150,8 @@ var (
 		OpHandleNewTip:                    {name: "HandleNewTip"},
 		OpAddTransactions:                 {name: "AddTransactions"},
 		OpHandleNewMinorBlock:             {name: "HandleNewMinorBlock"},
+		OpGetTrieNodes:                    {name: "GetTrieNodes"},
+		OpGetCrossShardTxLists:            {name: "GetCrossShardTxLists"},
 	}
 )
 
@@ -134,6 +167,7 @@ type opNode struct {
 // Client wraps the GRPC client.
 type Client interface {
 	Call(hostport string, req *Request) (*Response, error)
+	SubscribeEvents(ctx context.Context, hostport string, req *Request) (SlaveServerSideOp_SubscribeEventsClient, error)
 	GetOpName(uint32) string
 	Close()
 }
@@ -141,6 +175,7 @@ type Client interface {
 type rpcClient struct {
 	connVals map[string]*opNode
 	funcs    map[uint32]opType
+	timers   map[uint32]metrics.Timer // latency of the calls of each op
 
 	mu      sync.RWMutex
 	timeout time.Duration
@@ -162,6 +197,20 @@ func (c *rpcClient) Call(hostport string, req *Request) (*Response, error) {
 	return c.grpcOp(hostport, req)
 }
 
+// SubscribeEvents opens the stream of the events of the slave at hostport, which
+// is closed when ctx is done.
+func (c *rpcClient) SubscribeEvents(ctx context.Context, hostport string, req *Request) (SlaveServerSideOp_SubscribeEventsClient, error) {
+	if c.tp != SlaveServer {
+		return nil, errors.New("invalid op")
+	}
+	node, err := c.getConn(hostport)
+	if err != nil {
+		return nil, err
+	}
+	req.RpcId = c.addRpcId()
+	return node.client.Interface().(SlaveServerSideOpClient).SubscribeEvents(ctx, req)
+}
+
 func (c *rpcClient) Close() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -192,6 +241,7 @@ func (c *rpcClient) grpcOp(hostport string, req *Request) (*Response, error) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
+	defer c.timers[req.Op].UpdateSince(time.Now())
 
 	var (
 		val = []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req)}
@@ -246,9 +296,18 @@ func NewClient(serverType serverType) Client {
 	} else if serverType != MasterServer {
 		return nil
 	}
+	prefix := "grpc/master/"
+	if serverType == SlaveServer {
+		prefix = "grpc/slave/"
+	}
+	timers := make(map[uint32]metrics.Timer, len(rpcFuncs))
+	for op, fn := range rpcFuncs {
+		timers[op] = metrics.GetOrRegisterTimer(prefix+fn.name, nil)
+	}
 	return &rpcClient{
 		connVals: make(map[string]*opNode),
 		funcs:    rpcFuncs,
+		timers:   timers,
 		tp:       serverType,
 		timeout:  time.Duration(timeOut) * time.Second,
 		logger:   log.New("rpcclient"),
@@ -154,7 +154,7 @@ type GetTransactionRequest struct {
 }
 
 type GetTransactionResponse struct {
-	MinorBlock *types.MinorBlock `json:"minor_block" gencodec:"required"`
+	MinorBlock *types.MinorBlock `json:"minor_block" ser:"nil"`
 	Index      uint32            `json:"index" gencodec:"required"`
 }
 
@@ -174,9 +174,46 @@ type GetTransactionReceiptRequest struct {
 }
 
 type GetTransactionReceiptResponse struct {
-	MinorBlock *types.MinorBlock `json:"minor_block" gencodec:"required"`
+	MinorBlock *types.MinorBlock `json:"minor_block" ser:"nil"`
+	Index      uint32            `json:"index" gencodec:"required"`
+	Receipt    *types.Receipt    `json:"receipt" ser:"nil"`
+}
+
+type GetXShardDepositBlockRequest struct {
+	Branch         uint32      `json:"branch" gencodec:"required"`
+	RootBlockHash  common.Hash `json:"root_block_hash" gencodec:"required"`
+	MinorBlockHash common.Hash `json:"minor_block_hash" gencodec:"required"`
+	TxHash         common.Hash `json:"tx_hash" gencodec:"required"`
+}
+
+type GetXShardDepositBlockResponse struct {
+	Header *types.MinorBlockHeader `json:"header" ser:"nil"`
+}
+
+type GetXShardDepositReceiptResponse struct {
+	MinorBlock *types.MinorBlock `json:"minor_block" ser:"nil"`
 	Index      uint32            `json:"index" gencodec:"required"`
-	Receipt    *types.Receipt    `json:"receipt" gencodec:"required" bytesizeofslicelen:"4"`
+	Receipt    *types.Receipt    `json:"receipt" ser:"nil"`
+}
+
+type TxPoolRequest struct {
+	Branch uint32 `json:"branch" gencodec:"required"`
+}
+
+// TxPoolAccount is the txs of a sender in the tx pool, sorted by nonce.
+type TxPoolAccount struct {
+	Sender common.Address       `json:"sender" gencodec:"required"`
+	Txs    []*types.Transaction `json:"txs" gencodec:"required" bytesizeofslicelen:"4"`
+}
+
+type GetTxPoolContentResponse struct {
+	Pending []*TxPoolAccount `json:"pending" gencodec:"required" bytesizeofslicelen:"4"`
+	Queued  []*TxPoolAccount `json:"queued" gencodec:"required" bytesizeofslicelen:"4"`
+}
+
+type GetTxPoolStatusResponse struct {
+	Pending uint32 `json:"pending" gencodec:"required"`
+	Queued  uint32 `json:"queued" gencodec:"required"`
 }
 
 type GetTransactionListByAddressRequest struct {
@@ -321,6 +358,7 @@ type AddBlockListForSyncRequest struct {
 	Branch             uint32        `json:"branch" gencodec:"required"`
 	PeerId             string        `json:"peer_id" gencodec:"required"`
 	MinorBlockHashList []common.Hash `json:"minor_block_list" gencodec:"required" bytesizeofslicelen:"4"`
+	WithoutState       bool          `json:"without_state" gencodec:"required"`
 }
 
 type AddBlockListForSyncResponse struct {
@@ -337,6 +375,53 @@ type GetLogResponse struct {
 	Logs []*types.Log `json:"logs" gencodec:"required" bytesizeofslicelen:"4"`
 }
 
+type NewBlockFilterRequest struct {
+	Branch uint32 `json:"branch" gencodec:"required"`
+}
+
+type NewFilterResponse struct {
+	FilterID string `json:"filter_id" gencodec:"required"`
+}
+
+type FilterRequest struct {
+	FilterID string `json:"filter_id" gencodec:"required"`
+}
+
+// GetFilterChangesResponse carries the block hashes of a block filter or the logs
+// of a log filter. Removed is the Removed flag of each log, which is not
+// serialized with the log.
+type GetFilterChangesResponse struct {
+	Hashes  []common.Hash `json:"hashes" gencodec:"required" bytesizeofslicelen:"4"`
+	Logs    []*types.Log  `json:"logs" gencodec:"required" bytesizeofslicelen:"4"`
+	Removed []bool        `json:"removed" gencodec:"required" bytesizeofslicelen:"4"`
+}
+
+type UninstallFilterResponse struct {
+	Uninstalled bool `json:"uninstalled" gencodec:"required"`
+}
+
+// Types of the events streamed by SubscribeEvents.
+const (
+	NewHeadsEvent uint8 = iota + 1
+	LogsEvent
+	PendingTxsEvent
+)
+
+type SubscribeEventsRequest struct {
+	Type uint8 `json:"type" gencodec:"required"`
+}
+
+// ShardEvent is an event of a shard streamed by SubscribeEvents, which is the
+// header of a new block, the new or removed logs of a block, or the txs added
+// to the tx pool.
+type ShardEvent struct {
+	Branch  uint32                  `json:"branch" gencodec:"required"`
+	Header  *types.MinorBlockHeader `json:"header" ser:"nil"`
+	Logs    []*types.Log            `json:"logs" gencodec:"required" bytesizeofslicelen:"4"`
+	Removed bool                    `json:"removed" gencodec:"required"`
+	Txs     []*types.Transaction    `json:"txs" gencodec:"required" bytesizeofslicelen:"4"`
+}
+
 type EstimateGasRequest struct {
 	Tx          *types.Transaction `json:"tx" gencodec:"required"`
 	FromAddress *account.Address   `json:"from_address" gencodec:"required"`
@@ -396,6 +481,18 @@ type SubmitWorkResponse struct {
 	Success bool `json:"success" gencodec:"required"`
 }
 
+type SubmitShareRequest struct {
+	Branch          uint32      `json:"branch"           gencodec:"required"`
+	HeaderHash      common.Hash `json:"header_hash"      gencodec:"required"`
+	Nonce           uint64      `json:"nonce"            gencodec:"required"`
+	MixHash         common.Hash `json:"mix_hash"         gencodec:"required"`
+	ShareDifficulty *big.Int    `json:"share_difficulty" gencodec:"required"`
+}
+
+type SubmitShareResponse struct {
+	Block bool `json:"block" gencodec:"required"`
+}
+
 type PeerInfoForDisPlay struct {
 	ID   []byte
 	IP   uint32
@@ -412,6 +509,40 @@ type GetRootChainStakesResponse struct {
 	Signer *account.Recipient `json:"signer" gencodec:"required"`
 }
 
+// TraceConfig holds the options of a debug trace request, the struct logger
+// is used unless a named tracer is given
+type TraceConfig struct {
+	DisableStorage bool   `json:"disable_storage"`
+	DisableMemory  bool   `json:"disable_memory"`
+	DisableStack   bool   `json:"disable_stack"`
+	Limit          uint32 `json:"limit"`
+	Tracer         string `json:"tracer"`
+}
+
+type TraceTransactionRequest struct {
+	TxHash common.Hash  `json:"tx_hash" gencodec:"required"`
+	Branch uint32       `json:"branch" gencodec:"required"`
+	Config *TraceConfig `json:"config" gencodec:"required"`
+}
+
+type TraceCallRequest struct {
+	Tx          *types.Transaction `json:"tx" gencodec:"required"`
+	FromAddress *account.Address   `json:"from_address" gencodec:"required"`
+	BlockHeight *uint64            `json:"block_height" ser:"nil"`
+	Config      *TraceConfig       `json:"config" gencodec:"required"`
+}
+
+type TraceBlockByHashRequest struct {
+	BlockHash common.Hash  `json:"block_hash" gencodec:"required"`
+	Branch    uint32       `json:"branch" gencodec:"required"`
+	Config    *TraceConfig `json:"config" gencodec:"required"`
+}
+
+// TraceResponse carries the json encoded trace result
+type TraceResponse struct {
+	Result []byte `json:"result" gencodec:"required" bytesizeofslicelen:"4"`
+}
+
 type P2PRedirectRequest struct {
 	PeerID string `json:"peerid" gencodec:"required"`
 	Branch uint32
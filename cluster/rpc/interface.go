@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/consensus"
 	"github.com/QuarkChain/goquarkchain/core/types"
@@ -34,6 +35,8 @@ type ISlaveConn interface {
 	GetMinorBlocks(request *P2PRedirectRequest) ([]byte, error)
 	GetMinorBlockHeaderList(req *P2PRedirectRequest) ([]byte, error)
 	GetMinorBlockHeaderListWithSkip(req *P2PRedirectRequest) ([]byte, error)
+	GetTrieNodes(req *P2PRedirectRequest) ([]byte, error)
+	GetCrossShardTxLists(req *P2PRedirectRequest) ([]byte, error)
 	HandleNewTip(request *HandleNewTipRequest) error
 	HandleNewMinorBlock(request *P2PRedirectRequest) error
 	AddBlockListForSync(request *AddBlockListForSyncRequest) (*ShardStatus, error)
@@ -52,16 +55,30 @@ type ISlaveConn interface {
 	ExecuteTransaction(tx *types.Transaction, fromAddress *account.Address, height *uint64) ([]byte, error)
 	GetTransactionByHash(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, error)
 	GetTransactionReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error)
+	GetXShardDepositReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error)
+	GetXShardDepositBlock(branch account.Branch, rHash, mHash, txHash common.Hash) (*types.MinorBlockHeader, error)
 	GetTransactionsByAddress(address *account.Address, start []byte, limit uint32, transferTokenID *uint64) ([]*TransactionDetail, []byte, error)
+	GetTxPoolContent(branch account.Branch) ([]*TxPoolAccount, []*TxPoolAccount, error)
+	GetTxPoolStatus(branch account.Branch) (uint32, uint32, error)
 	GetAllTx(branch account.Branch, start []byte, limit uint32) ([]*TransactionDetail, []byte, error)
 	GetLogs(args *rpc.FilterQuery) ([]*types.Log, error)
+	NewFilter(args *rpc.FilterQuery) (string, error)
+	NewBlockFilter(branch account.Branch) (string, error)
+	GetFilterChanges(filterID string) ([]common.Hash, []*types.Log, error)
+	UninstallFilter(filterID string) (bool, error)
+	SubscribeEvents(ctx context.Context, typ uint8, handle func(*ShardEvent)) error
 	EstimateGas(tx *types.Transaction, fromAddress *account.Address) (uint32, error)
 	GetStorageAt(address *account.Address, key common.Hash, height *uint64) (common.Hash, error)
 	GetCode(address *account.Address, height *uint64) ([]byte, error)
 	GasPrice(branch account.Branch, tokenID uint64) (uint64, error)
+	GasPriceRate(branch account.Branch, tokenID uint64) (uint64, error)
 	GetWork(branch account.Branch, address *account.Address) (*consensus.MiningWork, error)
 	SubmitWork(work *SubmitWorkRequest) (success bool, err error)
+	SubmitShare(share *SubmitShareRequest) (block bool, err error)
 	SetMining(mining bool) error
 	GetRootChainStakes(address account.Address, lastMinor common.Hash) (*big.Int, *account.Recipient, error)
 	CheckMinorBlocksInRoot(rootBlock *types.RootBlock) error
+	TraceTransaction(txHash common.Hash, branch account.Branch, config *TraceConfig) ([]byte, error)
+	TraceCall(tx *types.Transaction, fromAddress *account.Address, height *uint64, config *TraceConfig) ([]byte, error)
+	TraceBlockByHash(blockHash common.Hash, branch account.Branch, config *TraceConfig) ([]byte, error)
 }
@@ -136,44 +136,54 @@ func init() {
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor_77a6da22d6a3feb1) }
 
 var fileDescriptor_77a6da22d6a3feb1 = []byte{
-	// 590 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x96, 0x5b, 0x4f, 0x13, 0x41,
-	0x14, 0xc7, 0x2d, 0x77, 0x8e, 0x05, 0xc3, 0x22, 0xd0, 0xe8, 0x83, 0x84, 0x44, 0x53, 0x51, 0xd1,
-	0x70, 0x27, 0xf1, 0xc1, 0x2d, 0xe0, 0x42, 0x02, 0x4a, 0x76, 0x6b, 0xf0, 0xcd, 0x0c, 0x33, 0x87,
-	0xee, 0xa4, 0xed, 0xcc, 0x3a, 0x73, 0x8a, 0xe5, 0x93, 0xfa, 0x4d, 0x7c, 0x36, 0xdb, 0x92, 0x96,
-	0x4d, 0x24, 0x33, 0x7d, 0xf5, 0xad, 0xcd, 0x9c, 0xdf, 0x9c, 0x33, 0xff, 0x73, 0x5b, 0x98, 0x35,
-	0x19, 0xdf, 0xc8, 0x8c, 0x26, 0x1d, 0x8c, 0x9b, 0x8c, 0xaf, 0x1d, 0xc1, 0x74, 0x8c, 0x3f, 0x3b,
-	0x68, 0x29, 0x98, 0x87, 0x31, 0x9d, 0x55, 0x4a, 0xab, 0xa5, 0xea, 0x5c, 0x3c, 0xa6, 0xb3, 0x60,
-	0x09, 0xa6, 0x4c, 0xc6, 0x7f, 0x48, 0x51, 0x19, 0x5b, 0x2d, 0x55, 0xc7, 0xe3, 0x49, 0x93, 0xf1,
-	0x53, 0x11, 0x04, 0x30, 0x21, 0x18, 0xb1, 0xca, 0xe4, 0x6a, 0xa9, 0x5a, 0x8e, 0x7b, 0xbf, 0xd7,
-	0x76, 0x60, 0x26, 0x46, 0x9b, 0x69, 0x65, 0x71, 0x70, 0x5e, 0x1a, 0x9e, 0x3f, 0x70, 0xd5, 0xe6,
-	0xef, 0x71, 0x08, 0xce, 0x99, 0x25, 0x34, 0x09, 0x9a, 0x1b, 0x34, 0x89, 0x14, 0xf8, 0x35, 0x0b,
-	0xb6, 0x61, 0x31, 0x14, 0xe2, 0x5c, 0x2a, 0x6d, 0x6a, 0x2d, 0xcd, 0x9b, 0x27, 0xc8, 0x04, 0x9a,
-	0xa0, 0xbc, 0x91, 0xc7, 0x7e, 0x17, 0xed, 0xb3, 0xb9, 0xbb, 0x7f, 0x7d, 0xaf, 0x6b, 0x8f, 0x82,
-	0x7d, 0x58, 0xf9, 0x07, 0x75, 0x26, 0x2d, 0xb9, 0xc8, 0x0f, 0xf0, 0xa4, 0x66, 0x34, 0x13, 0x9c,
-	0x59, 0xfa, 0x82, 0xbf, 0xea, 0x32, 0x73, 0x11, 0xbb, 0xb0, 0x34, 0x20, 0xea, 0x86, 0x29, 0xcb,
-	0x38, 0x49, 0xad, 0xac, 0x8b, 0xdb, 0x83, 0xe5, 0xfb, 0x9e, 0x86, 0xc1, 0xba, 0xc0, 0x4d, 0x58,
-	0x88, 0x90, 0x86, 0xf6, 0x3e, 0xcf, 0xda, 0x87, 0x95, 0x02, 0xe3, 0x2f, 0xc8, 0x27, 0x78, 0xf1,
-	0x00, 0x79, 0x29, 0x29, 0x4d, 0x9a, 0x4e, 0x81, 0x36, 0xff, 0x94, 0x61, 0x21, 0x69, 0xb1, 0x1b,
-	0x2c, 0x24, 0x76, 0x1d, 0x66, 0x53, 0x64, 0x86, 0x6a, 0xc8, 0x9c, 0x31, 0xbc, 0x01, 0xe8, 0x97,
-	0xc6, 0xa9, 0xba, 0xd6, 0x2e, 0xe3, 0x97, 0x30, 0x71, 0x21, 0x55, 0xc3, 0x65, 0xf6, 0x0a, 0x26,
-	0x23, 0x54, 0xf5, 0xae, 0xcb, 0xee, 0x1d, 0x94, 0x43, 0x21, 0x62, 0xad, 0xc9, 0x2b, 0x39, 0x07,
-	0x50, 0x89, 0x90, 0xbe, 0x29, 0xae, 0xd5, 0xb5, 0x34, 0x6d, 0x14, 0xfe, 0x4a, 0xbf, 0x87, 0xf9,
-	0x08, 0x29, 0xe4, 0x5c, 0x77, 0x14, 0x1d, 0xe5, 0xad, 0xe2, 0x06, 0x42, 0x21, 0xee, 0xd5, 0x9c,
-	0x0b, 0xd8, 0x80, 0xb9, 0x42, 0x2e, 0xfd, 0x22, 0x1a, 0xc1, 0xc1, 0x16, 0x04, 0xc7, 0x5d, 0xe4,
-	0x1d, 0xc2, 0x11, 0xa0, 0x5d, 0x58, 0x2a, 0x7a, 0x89, 0x91, 0xa3, 0xcc, 0x9c, 0x7a, 0x7d, 0x84,
-	0xe7, 0x45, 0x2e, 0x17, 0xb9, 0x76, 0x1b, 0x0a, 0x61, 0xd0, 0x3a, 0xdb, 0xef, 0x35, 0xcc, 0xe4,
-	0x6a, 0xb7, 0x5a, 0xee, 0x12, 0xa8, 0xc2, 0x74, 0x84, 0x74, 0xa6, 0x1b, 0xce, 0x4b, 0xdf, 0xc2,
-	0xe3, 0x63, 0x4b, 0xb2, 0xcd, 0x08, 0x23, 0x66, 0x3d, 0x4a, 0x2b, 0x42, 0x4a, 0x48, 0x1b, 0xd6,
-	0xc0, 0x90, 0xfc, 0xc2, 0x38, 0xd4, 0x02, 0x7d, 0xde, 0xc6, 0xec, 0x85, 0x91, 0x1c, 0xfd, 0x2e,
-	0xbd, 0xd4, 0xa6, 0xe9, 0xd1, 0x84, 0x49, 0xe7, 0xaa, 0x2d, 0xbd, 0x8c, 0xb7, 0x20, 0x88, 0x90,
-	0xf2, 0xae, 0x39, 0x4c, 0x99, 0x54, 0x09, 0xb1, 0x26, 0x5a, 0x8f, 0xd9, 0x1b, 0x0a, 0xf1, 0xdd,
-	0xa6, 0xcc, 0x88, 0x7a, 0xd7, 0xa7, 0x65, 0x76, 0xe0, 0x69, 0x8d, 0x11, 0x4f, 0x47, 0xc4, 0x0e,
-	0xa0, 0x52, 0x58, 0x0f, 0x39, 0xf3, 0x59, 0x9b, 0xe4, 0x56, 0x71, 0x17, 0xba, 0x0e, 0xb3, 0x49,
-	0xaf, 0x85, 0x3c, 0x46, 0xcc, 0x1e, 0x2c, 0x1f, 0xa6, 0xc8, 0x9b, 0x43, 0x47, 0xf6, 0x54, 0xe5,
-	0x9a, 0xfc, 0x67, 0x13, 0x3e, 0x2f, 0xe4, 0x13, 0xa6, 0x44, 0x0b, 0xfd, 0x36, 0x66, 0x3f, 0xcf,
-	0xa3, 0xec, 0xca, 0x6d, 0x58, 0x1c, 0x38, 0xf0, 0x1e, 0x5f, 0x57, 0x53, 0xbd, 0x6f, 0x9b, 0xad,
-	0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x1c, 0x14, 0x88, 0x73, 0xe8, 0x08, 0x00, 0x00,
+	// 752 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x97, 0x5b, 0x4f, 0x2b, 0x37,
+	0x10, 0xc7, 0x1b, 0x0e, 0x9c, 0x73, 0x98, 0x72, 0x5d, 0x0a, 0x44, 0xed, 0x43, 0x11, 0x52, 0xab,
+	0x94, 0xb6, 0x5c, 0xc2, 0x5d, 0xea, 0x43, 0x93, 0x00, 0x0b, 0x12, 0xa4, 0x68, 0x37, 0x08, 0xde,
+	0x2a, 0xc7, 0x1e, 0xb2, 0x56, 0x16, 0x7b, 0x6b, 0x4f, 0x20, 0x7c, 0x93, 0x7e, 0x99, 0x7e, 0xb7,
+	0x6a, 0x93, 0x28, 0x21, 0x52, 0x91, 0x9d, 0x97, 0x3e, 0x9c, 0xb7, 0x44, 0xeb, 0xdf, 0xcc, 0x78,
+	0xf6, 0x3f, 0x97, 0x85, 0x59, 0x93, 0xf1, 0xed, 0xcc, 0x68, 0xd2, 0xc1, 0x07, 0x93, 0xf1, 0xcd,
+	0x33, 0xf8, 0x14, 0xe1, 0x5f, 0x1d, 0xb4, 0x14, 0x2c, 0xc0, 0x94, 0xce, 0x8a, 0x85, 0x8d, 0x42,
+	0x69, 0x3e, 0x9a, 0xd2, 0x59, 0xb0, 0x0a, 0x1f, 0x4d, 0xc6, 0xff, 0x94, 0xa2, 0x38, 0xb5, 0x51,
+	0x28, 0x7d, 0x88, 0x66, 0x4c, 0xc6, 0xaf, 0x44, 0x10, 0xc0, 0xb4, 0x60, 0xc4, 0x8a, 0x33, 0x1b,
+	0x85, 0xd2, 0x5c, 0xd4, 0xfb, 0xbd, 0x79, 0x08, 0x9f, 0x23, 0xb4, 0x99, 0x56, 0x16, 0x87, 0xcf,
+	0x0b, 0xa3, 0xe7, 0xef, 0x98, 0x2a, 0xff, 0x33, 0x0d, 0xc1, 0x0d, 0xb3, 0x84, 0x26, 0x46, 0xf3,
+	0x8c, 0x26, 0x96, 0x02, 0xff, 0xc8, 0x82, 0x03, 0x58, 0xa9, 0x08, 0x71, 0x23, 0x95, 0x36, 0xd5,
+	0x54, 0xf3, 0xf6, 0x25, 0x32, 0x81, 0x26, 0x98, 0xdb, 0xce, 0x63, 0x1f, 0x44, 0xfb, 0xed, 0xfc,
+	0xe0, 0x5f, 0xdf, 0xeb, 0xe6, 0x57, 0xc1, 0x09, 0xac, 0xff, 0x07, 0x75, 0x2d, 0x2d, 0xb9, 0xc8,
+	0x5d, 0x58, 0xac, 0x1a, 0xcd, 0x04, 0x67, 0x96, 0xea, 0xf8, 0xd2, 0x90, 0x99, 0x8b, 0x38, 0x82,
+	0xd5, 0x21, 0xd1, 0x30, 0x4c, 0x59, 0xc6, 0x49, 0x6a, 0x65, 0x5d, 0xdc, 0x31, 0xac, 0xbd, 0xf5,
+	0x34, 0x0a, 0xd6, 0x05, 0x96, 0x61, 0x39, 0x44, 0x1a, 0x9d, 0xf7, 0xb9, 0xd6, 0x09, 0xac, 0x8f,
+	0x31, 0xfe, 0x09, 0xf9, 0x1d, 0xbe, 0x7f, 0x87, 0xbc, 0x97, 0x94, 0xc4, 0x6d, 0x77, 0x82, 0x7e,
+	0x85, 0xb9, 0x10, 0xa9, 0x61, 0x24, 0xd6, 0xb5, 0x40, 0x67, 0x5e, 0x0e, 0xe1, 0x9b, 0x10, 0xa9,
+	0x66, 0xb4, 0xb5, 0x71, 0xc2, 0x8c, 0x68, 0x74, 0x73, 0x67, 0x2e, 0xac, 0xfc, 0xf7, 0x0a, 0x2c,
+	0xc7, 0x29, 0x7b, 0xc6, 0x31, 0xf9, 0x6c, 0xc1, 0x6c, 0x82, 0xcc, 0x50, 0x15, 0x99, 0xf3, 0xa6,
+	0x3f, 0x03, 0xf4, 0x05, 0x78, 0xa5, 0x1e, 0xb5, 0xeb, 0xf0, 0x0f, 0x30, 0x7d, 0x2b, 0x55, 0xcb,
+	0x75, 0xec, 0x47, 0x98, 0x09, 0x51, 0x35, 0xba, 0x1e, 0x39, 0xaa, 0x08, 0x11, 0x69, 0x4d, 0x5e,
+	0x12, 0x38, 0x85, 0x62, 0x88, 0x74, 0xa7, 0xb8, 0x56, 0x8f, 0xd2, 0x3c, 0xa1, 0xf0, 0x7f, 0x9f,
+	0x3b, 0xb0, 0x10, 0x22, 0x55, 0x38, 0xd7, 0x1d, 0x45, 0x67, 0x79, 0x41, 0xba, 0x81, 0x8a, 0x10,
+	0x6f, 0x94, 0xed, 0x02, 0xb6, 0x61, 0x7e, 0x4c, 0x31, 0x7e, 0x11, 0x4d, 0xe0, 0x60, 0x1f, 0x82,
+	0xf3, 0x2e, 0xf2, 0x0e, 0xe1, 0x04, 0xd0, 0x11, 0xac, 0x8e, 0x7b, 0x89, 0x90, 0xa3, 0xcc, 0x9c,
+	0xf9, 0xfa, 0x0d, 0xbe, 0x1b, 0xe7, 0xf2, 0x24, 0x57, 0x5f, 0x2b, 0x42, 0x18, 0xb4, 0x4e, 0x31,
+	0xff, 0x04, 0x9f, 0xf3, 0x6c, 0xa7, 0xa9, 0x5b, 0x02, 0x25, 0xf8, 0x14, 0x22, 0x5d, 0xeb, 0x96,
+	0xd3, 0xe8, 0x2f, 0xf0, 0xf5, 0xb9, 0x25, 0xf9, 0xc4, 0x08, 0x43, 0x66, 0xfd, 0xca, 0x2f, 0x26,
+	0x6d, 0x58, 0x0b, 0x2b, 0xe4, 0x17, 0x46, 0x4d, 0x0b, 0xf4, 0xb9, 0x1b, 0xb3, 0xb7, 0x46, 0x72,
+	0xf4, 0x33, 0x7a, 0xaf, 0x4d, 0xdb, 0xa3, 0x08, 0xe3, 0x4e, 0xf3, 0x49, 0x7a, 0x1d, 0xde, 0x87,
+	0x20, 0x44, 0xca, 0xab, 0xa6, 0x96, 0x30, 0xa9, 0x62, 0x62, 0x6d, 0x77, 0x7f, 0xd9, 0x83, 0xa5,
+	0x86, 0x61, 0x7c, 0x12, 0xed, 0x6c, 0xc1, 0x6c, 0x0f, 0xa9, 0xb1, 0x34, 0xf5, 0x35, 0xdf, 0x93,
+	0x7e, 0xf5, 0xf5, 0x92, 0xd9, 0xc4, 0xc3, 0x7c, 0x1d, 0x5f, 0x2e, 0x64, 0x4a, 0xee, 0xc9, 0xb6,
+	0x03, 0x0b, 0x75, 0x7c, 0xe9, 0x19, 0xf7, 0x03, 0xf6, 0x60, 0x29, 0x44, 0xea, 0x9f, 0xad, 0x25,
+	0x4c, 0xb5, 0xdc, 0x19, 0xda, 0x85, 0xc5, 0x3b, 0x25, 0x95, 0x25, 0x96, 0xa6, 0x7e, 0x4e, 0xca,
+	0xb0, 0x18, 0x77, 0x9a, 0x96, 0x1b, 0xd9, 0xc4, 0xf3, 0x67, 0x54, 0xce, 0x76, 0xbd, 0x5b, 0x18,
+	0x14, 0xe4, 0x43, 0xaf, 0xc7, 0x9f, 0x61, 0xa6, 0xad, 0xf4, 0xeb, 0x7d, 0xfd, 0x51, 0x36, 0xc6,
+	0x79, 0x96, 0x72, 0x3f, 0x15, 0x8d, 0xee, 0xad, 0xd6, 0x69, 0x4d, 0x2b, 0x42, 0xe5, 0xb3, 0x0e,
+	0x0c, 0x91, 0x98, 0x18, 0x75, 0x7c, 0x8a, 0xb3, 0x2f, 0xe0, 0x3c, 0x40, 0xf4, 0x29, 0xce, 0x41,
+	0x0d, 0x45, 0x8c, 0xd0, 0x23, 0x9c, 0x8a, 0x10, 0x0f, 0x76, 0x34, 0x17, 0x3d, 0xa6, 0x69, 0x95,
+	0x11, 0x4f, 0x26, 0xc4, 0x4e, 0xa1, 0x38, 0xb6, 0x40, 0xe5, 0xcc, 0x85, 0x36, 0xf1, 0xab, 0xe2,
+	0x1e, 0x6a, 0x8e, 0x7b, 0xed, 0xdf, 0x63, 0x3c, 0x1e, 0xc3, 0x5a, 0x2d, 0x41, 0xde, 0x1e, 0x39,
+	0xb2, 0x57, 0x2a, 0xaf, 0xe7, 0x2f, 0x70, 0x07, 0xba, 0x64, 0x4a, 0xa4, 0xe8, 0xb7, 0x53, 0xf6,
+	0xdf, 0xf3, 0x24, 0xdb, 0xe4, 0x01, 0xac, 0x0c, 0x1d, 0xf8, 0x8f, 0xde, 0xff, 0x65, 0x35, 0x6b,
+	0x7e, 0xec, 0x7d, 0x63, 0xec, 0xff, 0x3b, 0x00, 0xc3, 0x4e, 0x7b, 0xd3, 0x70, 0x0c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -197,6 +207,8 @@ type MasterServerSideOpClient interface {
 	GetMinorBlockList(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	GetMinorBlockHeaderList(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	GetMinorBlockHeaderListWithSkip(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetTrieNodes(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetCrossShardTxLists(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 }
 
 type masterServerSideOpClient struct {
@@ -279,6 +291,24 @@ func (c *masterServerSideOpClient) GetMinorBlockHeaderListWithSkip(ctx context.C
 	return out, nil
 }
 
+func (c *masterServerSideOpClient) GetTrieNodes(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.MasterServerSideOp/GetTrieNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterServerSideOpClient) GetCrossShardTxLists(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.MasterServerSideOp/GetCrossShardTxLists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MasterServerSideOpServer is the server API for MasterServerSideOp service.
 type MasterServerSideOpServer interface {
 	AddMinorBlockHeader(context.Context, *Request) (*Response, error)
@@ -290,6 +320,8 @@ type MasterServerSideOpServer interface {
 	GetMinorBlockList(context.Context, *Request) (*Response, error)
 	GetMinorBlockHeaderList(context.Context, *Request) (*Response, error)
 	GetMinorBlockHeaderListWithSkip(context.Context, *Request) (*Response, error)
+	GetTrieNodes(context.Context, *Request) (*Response, error)
+	GetCrossShardTxLists(context.Context, *Request) (*Response, error)
 }
 
 // UnimplementedMasterServerSideOpServer can be embedded to have forward compatible implementations.
@@ -320,6 +352,12 @@ func (*UnimplementedMasterServerSideOpServer) GetMinorBlockHeaderList(ctx contex
 func (*UnimplementedMasterServerSideOpServer) GetMinorBlockHeaderListWithSkip(ctx context.Context, req *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMinorBlockHeaderListWithSkip not implemented")
 }
+func (*UnimplementedMasterServerSideOpServer) GetTrieNodes(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrieNodes not implemented")
+}
+func (*UnimplementedMasterServerSideOpServer) GetCrossShardTxLists(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrossShardTxLists not implemented")
+}
 
 func RegisterMasterServerSideOpServer(s *grpc.Server, srv MasterServerSideOpServer) {
 	s.RegisterService(&_MasterServerSideOp_serviceDesc, srv)
@@ -469,6 +507,42 @@ func _MasterServerSideOp_GetMinorBlockHeaderListWithSkip_Handler(srv interface{}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MasterServerSideOp_GetTrieNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServerSideOpServer).GetTrieNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MasterServerSideOp/GetTrieNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServerSideOpServer).GetTrieNodes(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterServerSideOp_GetCrossShardTxLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServerSideOpServer).GetCrossShardTxLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MasterServerSideOp/GetCrossShardTxLists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServerSideOpServer).GetCrossShardTxLists(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _MasterServerSideOp_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpc.MasterServerSideOp",
 	HandlerType: (*MasterServerSideOpServer)(nil),
@@ -505,6 +579,14 @@ var _MasterServerSideOp_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetMinorBlockHeaderListWithSkip",
 			Handler:    _MasterServerSideOp_GetMinorBlockHeaderListWithSkip_Handler,
 		},
+		{
+			MethodName: "GetTrieNodes",
+			Handler:    _MasterServerSideOp_GetTrieNodes_Handler,
+		},
+		{
+			MethodName: "GetCrossShardTxLists",
+			Handler:    _MasterServerSideOp_GetCrossShardTxLists_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
@@ -537,6 +619,21 @@ type SlaveServerSideOpClient interface {
 	GetWork(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	SubmitWork(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	GetRootChainStakes(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	TraceTransaction(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	TraceCall(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	TraceBlockByHash(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	NewFilter(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	NewBlockFilter(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetFilterChanges(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	UninstallFilter(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	// streams the events of all the shards of the slave
+	SubscribeEvents(ctx context.Context, in *Request, opts ...grpc.CallOption) (SlaveServerSideOp_SubscribeEventsClient, error)
+	GetXShardDepositBlock(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetXShardDepositReceipt(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetTxPoolContent(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetTxPoolStatus(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	SubmitShare(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GasPriceRate(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	// APIs for neighbor slaves
 	AddXshardTxList(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	BatchAddXshardTxList(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
@@ -550,6 +647,8 @@ type SlaveServerSideOpClient interface {
 	HandleNewTip(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	AddTransactions(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 	HandleNewMinorBlock(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetTrieNodes(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetCrossShardTxLists(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
 }
 
 type slaveServerSideOpClient struct {
@@ -758,6 +857,155 @@ func (c *slaveServerSideOpClient) GetRootChainStakes(ctx context.Context, in *Re
 	return out, nil
 }
 
+func (c *slaveServerSideOpClient) TraceTransaction(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/TraceTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) TraceCall(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/TraceCall", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) TraceBlockByHash(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/TraceBlockByHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) NewFilter(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/NewFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) NewBlockFilter(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/NewBlockFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) GetFilterChanges(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetFilterChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) UninstallFilter(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/UninstallFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) SubscribeEvents(ctx context.Context, in *Request, opts ...grpc.CallOption) (SlaveServerSideOp_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SlaveServerSideOp_serviceDesc.Streams[0], "/rpc.SlaveServerSideOp/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &slaveServerSideOpSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SlaveServerSideOp_SubscribeEventsClient interface {
+	Recv() (*Response, error)
+	grpc.ClientStream
+}
+
+type slaveServerSideOpSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *slaveServerSideOpSubscribeEventsClient) Recv() (*Response, error) {
+	m := new(Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *slaveServerSideOpClient) GetXShardDepositBlock(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetXShardDepositBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) GetXShardDepositReceipt(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetXShardDepositReceipt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) GetTxPoolContent(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetTxPoolContent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) GetTxPoolStatus(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetTxPoolStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) SubmitShare(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/SubmitShare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) GasPriceRate(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GasPriceRate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *slaveServerSideOpClient) AddXshardTxList(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
 	out := new(Response)
 	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/AddXshardTxList", in, out, opts...)
@@ -857,6 +1105,24 @@ func (c *slaveServerSideOpClient) HandleNewMinorBlock(ctx context.Context, in *R
 	return out, nil
 }
 
+func (c *slaveServerSideOpClient) GetTrieNodes(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetTrieNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slaveServerSideOpClient) GetCrossShardTxLists(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/rpc.SlaveServerSideOp/GetCrossShardTxLists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SlaveServerSideOpServer is the server API for SlaveServerSideOp service.
 type SlaveServerSideOpServer interface {
 	HeartBeat(context.Context, *Request) (*Response, error)
@@ -882,6 +1148,21 @@ type SlaveServerSideOpServer interface {
 	GetWork(context.Context, *Request) (*Response, error)
 	SubmitWork(context.Context, *Request) (*Response, error)
 	GetRootChainStakes(context.Context, *Request) (*Response, error)
+	TraceTransaction(context.Context, *Request) (*Response, error)
+	TraceCall(context.Context, *Request) (*Response, error)
+	TraceBlockByHash(context.Context, *Request) (*Response, error)
+	NewFilter(context.Context, *Request) (*Response, error)
+	NewBlockFilter(context.Context, *Request) (*Response, error)
+	GetFilterChanges(context.Context, *Request) (*Response, error)
+	UninstallFilter(context.Context, *Request) (*Response, error)
+	// streams the events of all the shards of the slave
+	SubscribeEvents(*Request, SlaveServerSideOp_SubscribeEventsServer) error
+	GetXShardDepositBlock(context.Context, *Request) (*Response, error)
+	GetXShardDepositReceipt(context.Context, *Request) (*Response, error)
+	GetTxPoolContent(context.Context, *Request) (*Response, error)
+	GetTxPoolStatus(context.Context, *Request) (*Response, error)
+	SubmitShare(context.Context, *Request) (*Response, error)
+	GasPriceRate(context.Context, *Request) (*Response, error)
 	// APIs for neighbor slaves
 	AddXshardTxList(context.Context, *Request) (*Response, error)
 	BatchAddXshardTxList(context.Context, *Request) (*Response, error)
@@ -895,6 +1176,8 @@ type SlaveServerSideOpServer interface {
 	HandleNewTip(context.Context, *Request) (*Response, error)
 	AddTransactions(context.Context, *Request) (*Response, error)
 	HandleNewMinorBlock(context.Context, *Request) (*Response, error)
+	GetTrieNodes(context.Context, *Request) (*Response, error)
+	GetCrossShardTxLists(context.Context, *Request) (*Response, error)
 }
 
 // UnimplementedSlaveServerSideOpServer can be embedded to have forward compatible implementations.
@@ -967,6 +1250,48 @@ func (*UnimplementedSlaveServerSideOpServer) SubmitWork(ctx context.Context, req
 func (*UnimplementedSlaveServerSideOpServer) GetRootChainStakes(ctx context.Context, req *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRootChainStakes not implemented")
 }
+func (*UnimplementedSlaveServerSideOpServer) TraceTransaction(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceTransaction not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) TraceCall(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceCall not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) TraceBlockByHash(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceBlockByHash not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) NewFilter(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewFilter not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) NewBlockFilter(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewBlockFilter not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GetFilterChanges(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilterChanges not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) UninstallFilter(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UninstallFilter not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) SubscribeEvents(req *Request, srv SlaveServerSideOp_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GetXShardDepositBlock(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetXShardDepositBlock not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GetXShardDepositReceipt(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetXShardDepositReceipt not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GetTxPoolContent(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxPoolContent not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GetTxPoolStatus(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxPoolStatus not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) SubmitShare(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitShare not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GasPriceRate(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GasPriceRate not implemented")
+}
 func (*UnimplementedSlaveServerSideOpServer) AddXshardTxList(ctx context.Context, req *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddXshardTxList not implemented")
 }
@@ -1000,6 +1325,12 @@ func (*UnimplementedSlaveServerSideOpServer) AddTransactions(ctx context.Context
 func (*UnimplementedSlaveServerSideOpServer) HandleNewMinorBlock(ctx context.Context, req *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HandleNewMinorBlock not implemented")
 }
+func (*UnimplementedSlaveServerSideOpServer) GetTrieNodes(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrieNodes not implemented")
+}
+func (*UnimplementedSlaveServerSideOpServer) GetCrossShardTxLists(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrossShardTxLists not implemented")
+}
 
 func RegisterSlaveServerSideOpServer(s *grpc.Server, srv SlaveServerSideOpServer) {
 	s.RegisterService(&_SlaveServerSideOp_serviceDesc, srv)
@@ -1401,6 +1732,261 @@ func _SlaveServerSideOp_GetRootChainStakes_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SlaveServerSideOp_TraceTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).TraceTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/TraceTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).TraceTransaction(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_TraceCall_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).TraceCall(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/TraceCall",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).TraceCall(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_TraceBlockByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).TraceBlockByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/TraceBlockByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).TraceBlockByHash(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_NewFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).NewFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/NewFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).NewFilter(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_NewBlockFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).NewBlockFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/NewBlockFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).NewBlockFilter(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_GetFilterChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetFilterChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetFilterChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetFilterChanges(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_UninstallFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).UninstallFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/UninstallFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).UninstallFilter(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Request)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SlaveServerSideOpServer).SubscribeEvents(m, &slaveServerSideOpSubscribeEventsServer{stream})
+}
+
+type SlaveServerSideOp_SubscribeEventsServer interface {
+	Send(*Response) error
+	grpc.ServerStream
+}
+
+type slaveServerSideOpSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *slaveServerSideOpSubscribeEventsServer) Send(m *Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SlaveServerSideOp_GetXShardDepositBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetXShardDepositBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetXShardDepositBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetXShardDepositBlock(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_GetXShardDepositReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetXShardDepositReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetXShardDepositReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetXShardDepositReceipt(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_GetTxPoolContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetTxPoolContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetTxPoolContent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetTxPoolContent(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_GetTxPoolStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetTxPoolStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetTxPoolStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetTxPoolStatus(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_SubmitShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).SubmitShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/SubmitShare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).SubmitShare(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_GasPriceRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GasPriceRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GasPriceRate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GasPriceRate(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SlaveServerSideOp_AddXshardTxList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Request)
 	if err := dec(in); err != nil {
@@ -1599,6 +2185,42 @@ func _SlaveServerSideOp_HandleNewMinorBlock_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SlaveServerSideOp_GetTrieNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetTrieNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetTrieNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetTrieNodes(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlaveServerSideOp_GetCrossShardTxLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlaveServerSideOpServer).GetCrossShardTxLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.SlaveServerSideOp/GetCrossShardTxLists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlaveServerSideOpServer).GetCrossShardTxLists(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SlaveServerSideOp_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpc.SlaveServerSideOp",
 	HandlerType: (*SlaveServerSideOpServer)(nil),
@@ -1691,6 +2313,58 @@ var _SlaveServerSideOp_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetRootChainStakes",
 			Handler:    _SlaveServerSideOp_GetRootChainStakes_Handler,
 		},
+		{
+			MethodName: "TraceTransaction",
+			Handler:    _SlaveServerSideOp_TraceTransaction_Handler,
+		},
+		{
+			MethodName: "TraceCall",
+			Handler:    _SlaveServerSideOp_TraceCall_Handler,
+		},
+		{
+			MethodName: "TraceBlockByHash",
+			Handler:    _SlaveServerSideOp_TraceBlockByHash_Handler,
+		},
+		{
+			MethodName: "NewFilter",
+			Handler:    _SlaveServerSideOp_NewFilter_Handler,
+		},
+		{
+			MethodName: "NewBlockFilter",
+			Handler:    _SlaveServerSideOp_NewBlockFilter_Handler,
+		},
+		{
+			MethodName: "GetFilterChanges",
+			Handler:    _SlaveServerSideOp_GetFilterChanges_Handler,
+		},
+		{
+			MethodName: "UninstallFilter",
+			Handler:    _SlaveServerSideOp_UninstallFilter_Handler,
+		},
+		{
+			MethodName: "GetXShardDepositBlock",
+			Handler:    _SlaveServerSideOp_GetXShardDepositBlock_Handler,
+		},
+		{
+			MethodName: "GetXShardDepositReceipt",
+			Handler:    _SlaveServerSideOp_GetXShardDepositReceipt_Handler,
+		},
+		{
+			MethodName: "GetTxPoolContent",
+			Handler:    _SlaveServerSideOp_GetTxPoolContent_Handler,
+		},
+		{
+			MethodName: "GetTxPoolStatus",
+			Handler:    _SlaveServerSideOp_GetTxPoolStatus_Handler,
+		},
+		{
+			MethodName: "SubmitShare",
+			Handler:    _SlaveServerSideOp_SubmitShare_Handler,
+		},
+		{
+			MethodName: "GasPriceRate",
+			Handler:    _SlaveServerSideOp_GasPriceRate_Handler,
+		},
 		{
 			MethodName: "AddXshardTxList",
 			Handler:    _SlaveServerSideOp_AddXshardTxList_Handler,
@@ -1735,7 +2409,21 @@ var _SlaveServerSideOp_serviceDesc = grpc.ServiceDesc{
 			MethodName: "HandleNewMinorBlock",
 			Handler:    _SlaveServerSideOp_HandleNewMinorBlock_Handler,
 		},
+		{
+			MethodName: "GetTrieNodes",
+			Handler:    _SlaveServerSideOp_GetTrieNodes_Handler,
+		},
+		{
+			MethodName: "GetCrossShardTxLists",
+			Handler:    _SlaveServerSideOp_GetCrossShardTxLists_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _SlaveServerSideOp_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
 }
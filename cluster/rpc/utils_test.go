@@ -59,6 +59,16 @@ func (m *MasterServerSideOp) GetMinorBlockHeaderListWithSkip(ctx context.Context
 		RpcId: req.RpcId,
 	}, nil
 }
+func (m *MasterServerSideOp) GetTrieNodes(ctx context.Context, req *Request) (*Response, error) {
+	return &Response{
+		RpcId: req.RpcId,
+	}, nil
+}
+func (m *MasterServerSideOp) GetCrossShardTxLists(ctx context.Context, req *Request) (*Response, error) {
+	return &Response{
+		RpcId: req.RpcId,
+	}, nil
+}
 func (m *MasterServerSideOp) GetMinorBlockHeaderList(ctx context.Context, req *Request) (*Response, error) {
 	return &Response{
 		RpcId: req.RpcId,
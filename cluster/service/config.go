@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/QuarkChain/goquarkchain/p2p"
+	"github.com/QuarkChain/goquarkchain/qkcdb"
 	"github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -23,6 +24,7 @@ const (
 	datadirStaticNodes  = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase = "nodes"              // Path within the datadir to store the node infos
+	datadirKeyStore     = "keystore"           // Path within the datadir to the keystore
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -45,6 +47,15 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// KeyStoreDir is the file system folder that contains the private keys of
+	// the accounts of the master, in the keystore directory of the instance
+	// directory if empty.
+	KeyStoreDir string `toml:",omitempty"`
+
+	// DB selects the key-value store of the databases opened in DataDir and
+	// tunes it.
+	DB qkcdb.Config
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -80,6 +91,10 @@ type Config struct {
 
 	WSEndpoint string
 
+	// MetricsEndpoint is the host:port of the HTTP endpoint serving the metrics
+	// in the Prometheus format on /metrics. It is disabled if empty.
+	MetricsEndpoint string `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 
@@ -161,6 +176,15 @@ var isOldGethResource = map[string]bool{
 	"trusted-nodes.json": false, // own separate warning.
 }
 
+// KeyDirConfig determines the directory of the keystore, empty for ephemeral
+// nodes without a configured keystore directory.
+func (c *Config) KeyDirConfig() string {
+	if c.KeyStoreDir != "" {
+		return c.KeyStoreDir
+	}
+	return c.ResolvePath(datadirKeyStore)
+}
+
 // ResolvePath resolves path in the instance directory.
 func (c *Config) ResolvePath(path string) string {
 	if filepath.IsAbs(path) {
@@ -16,8 +16,8 @@ import (
 var DefaultConfig = Config{
 	DataDir:         DefaultDataDir(),
 	GRPCModules:     []string{"grpc"},
-	HTTPModules:     []string{"qkc", "eth"},
-	HTTPPrivModules: []string{"qkc"},
+	HTTPModules:     []string{"qkc", "eth", "txpool"},
+	HTTPPrivModules: []string{"qkc", "eth", "debug", "stratum", "personal"},
 	WSModules:       []string{"ws"},
 	WSOrigins:       []string{"*"},
 	IPCPath:         "",
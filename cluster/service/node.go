@@ -4,6 +4,7 @@ package service
 import (
 	"fmt"
 	qkcrpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
+	"github.com/QuarkChain/goquarkchain/metrics/prometheus"
 	"github.com/QuarkChain/goquarkchain/p2p"
 	"github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/ethereum/go-ethereum/event"
@@ -11,6 +12,7 @@ import (
 	"github.com/prometheus/prometheus/util/flock"
 	"google.golang.org/grpc"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -51,6 +53,8 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	metricsListener net.Listener // HTTP listener socket to serve the metrics
+
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
 
@@ -171,10 +175,14 @@ func (n *Node) Start() error {
 	for _, service := range services {
 		running.Protocols = append(running.Protocols, service.Protocols()...)
 	}
-	// Lastly start the configured RPC interfaces
+	// Lastly start the configured RPC interfaces and the metrics endpoint
 	if err := n.startRPC(services); err != nil {
 		return err
 	}
+	if err := n.startMetrics(); err != nil {
+		n.stopRPC()
+		return err
+	}
 	// Start each of the services
 	var started []reflect.Type
 	for kind, service := range services {
@@ -184,6 +192,7 @@ func (n *Node) Start() error {
 				services[kind].Stop()
 			}
 			n.stopRPC()
+			n.stopMetrics()
 			return err
 		}
 		// Mark the service started for potential cleanup
@@ -242,12 +251,11 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 			n.stopRPC()
 			return err
 		}
-	} else {
-		// start ws service
-		if err := n.startWS(apis, n.config.WSModules, n.config.WSOrigins); err != nil {
-			n.stopWS()
-			return err
-		}
+	}
+	// start ws service
+	if err := n.startWS(apis, n.config.WSModules, n.config.WSOrigins); err != nil {
+		n.stopRPC()
+		return err
 	}
 	// All API endpoints started successfully
 	n.rpcAPIs = apis
@@ -367,6 +375,35 @@ func (n *Node) stopWS() {
 	}
 }
 
+// startMetrics initializes and starts the HTTP endpoint serving the metrics.
+func (n *Node) startMetrics() error {
+	// Short circuit if the metrics endpoint isn't being exposed
+	if n.config.MetricsEndpoint == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", n.config.MetricsEndpoint)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheus.Handler(nil))
+	go http.Serve(listener, mux)
+	n.log.Info("Metrics endpoint opened", "url", fmt.Sprintf("http://%s/metrics", listener.Addr()))
+	n.metricsListener = listener
+
+	return nil
+}
+
+// stopMetrics terminates the metrics endpoint.
+func (n *Node) stopMetrics() {
+	if n.metricsListener != nil {
+		n.metricsListener.Close()
+		n.metricsListener = nil
+
+		n.log.Info("Metrics endpoint closed", "url", fmt.Sprintf("http://%s/metrics", n.config.MetricsEndpoint))
+	}
+}
+
 // startHTTP initializes and starts the HTTP RPC endpoint.
 func (n *Node) startHTTP(apis []rpc.API, modules []string, timeouts rpc.HTTPTimeouts) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
@@ -454,6 +491,7 @@ func (n *Node) Stop() error {
 
 	// Terminate the API, services and the p2p server.
 	n.stopRPC()
+	n.stopMetrics()
 	n.rpcAPIs = nil
 	failure := &StopError{
 		Services: make(map[reflect.Type]error),
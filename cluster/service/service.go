@@ -28,13 +28,14 @@ func (ctx *ServiceContext) WSIsAlive() bool {
 }
 
 // OpenDatabase opens an existing database with the given name (or creates one
-// if no previous can be found) from within the node's data directory. If the
-// node is an ephemeral one, a memory database is returned.
+// if no previous can be found) from within the node's data directory with the
+// configured backend. If the node is an ephemeral one or the memory backend is
+// configured, a memory database is returned.
 func (ctx *ServiceContext) OpenDatabase(name string, clean bool, isReadOnly bool) (ethdb.Database, error) {
-	if ctx.config == nil || ctx.config.DataDir == "" {
+	if ctx.config == nil || ctx.config.DataDir == "" || ctx.config.DB.Backend == qkcdb.MemoryBackend {
 		return NewQkcMemoryDB(isReadOnly), nil
 	}
-	db, err := qkcdb.NewRDBDatabase(ctx.config.ResolvePath(name), clean, isReadOnly)
+	db, err := qkcdb.Open(ctx.config.ResolvePath(name), &ctx.config.DB, clean, isReadOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +49,15 @@ func (ctx *ServiceContext) ResolvePath(path string) string {
 	return ctx.config.ResolvePath(path)
 }
 
+// KeyStoreDir returns the directory of the keystore, empty if the node keeps
+// no keys.
+func (ctx *ServiceContext) KeyStoreDir() string {
+	if ctx.config == nil {
+		return ""
+	}
+	return ctx.config.KeyDirConfig()
+}
+
 // Service retrieves a currently running service registered of a specific type.
 func (ctx *ServiceContext) Service(service interface{}) error {
 	element := reflect.ValueOf(service).Elem()
@@ -41,6 +41,10 @@ func (p *peer) GetMinorBlockList(hashes []common.Hash, branch uint32) ([]*types.
 	return p.cm.GetMinorBlocks(hashes, p.peerID, branch)
 }
 
+func (p *peer) GetTrieNodes(hashes []common.Hash, branch uint32) ([][]byte, error) {
+	return p.cm.GetTrieNodes(hashes, p.peerID, branch)
+}
+
 func (p *peer) PeerID() string {
 	return p.peerID
 }
@@ -179,6 +183,10 @@ func (s *ShardBackend) HandleNewTip(rBHeader *types.RootBlockHeader, mBHeader *t
 	if s.mBPool.getBlockInPool(mBHeader.Hash()) != nil {
 		return nil
 	}
+	if s.MinorBlockChain.StateSyncPivot() != nil {
+		log.Debug(s.logInfo, "state sync pending, no need to add task", mBHeader.Number)
+		return nil
+	}
 	peer := &peer{cm: s.conn, peerID: peerID}
 	err := s.synchronizer.AddTask(qsync.NewMinorChainTask(peer, mBHeader))
 	if err != nil {
@@ -228,6 +236,9 @@ func (s *ShardBackend) NewMinorBlock(peerId string, block *types.MinorBlock) (er
 		log.Debug("prarent block hash not be included", "parent hash: ", block.ParentHash().Hex())
 		return
 	}
+	if peerId != "" {
+		s.telemetry.BlockReceived(block.Header(), peerId)
+	}
 
 	//Sanity check on timestamp and block height
 	if block.Time() > uint64(time.Now().Unix())+uint64(AllowedFutureBlocksTimeBroadcast) {
@@ -275,6 +286,7 @@ func (s *ShardBackend) AddMinorBlock(block *types.MinorBlock) error {
 	_, xshardLst, err := s.MinorBlockChain.InsertChainForDeposits([]types.IBlock{block}, false)
 	if err != nil {
 		log.Error("Failed to add minor block", "err", err, "len", len(xshardLst))
+		s.telemetry.InsertError(block.Header(), err)
 		return err
 	}
 
@@ -391,6 +403,75 @@ func (s *ShardBackend) AddBlockListForSync(blockLst []*types.MinorBlock) error {
 	return nil
 }
 
+// AddBlockListWithoutState stores the blocks confirmed by root blocks far
+// behind the tip without executing them during state sync. Like
+// AddBlockListForSync it does not broadcast the blocks, but as no xshard lists
+// are produced the neighbor shards get none.
+func (s *ShardBackend) AddBlockListWithoutState(blockLst []*types.MinorBlock) error {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	uncommittedBlockHeaderList := make([]*types.MinorBlockHeader, 0, len(blockLst))
+	for _, block := range blockLst {
+		if block.Branch().Value != s.branch.Value || s.MinorBlockChain.HasBlock(block.Hash()) {
+			continue
+		}
+		if err := s.MinorBlockChain.InsertBlockWithoutState(block); err != nil {
+			log.Error("Failed to store minor block", "number", block.NumberU64(), "err", err)
+			return err
+		}
+		uncommittedBlockHeaderList = append(uncommittedBlockHeaderList, block.Header())
+	}
+	if len(uncommittedBlockHeaderList) == 0 {
+		return nil
+	}
+	req := &rpc.AddMinorBlockHeaderListRequest{
+		MinorBlockHeaderList: uncommittedBlockHeaderList,
+	}
+	return s.conn.SendMinorBlockHeaderListToMaster(req)
+}
+
+// SyncState downloads the state of the state sync pivot, the last block stored
+// without state, and the xshard tx lists the following blocks need from the
+// peer, then sets the pivot as the head so blocks are executed again. It does
+// nothing if no state sync is pending.
+func (s *ShardBackend) SyncState(peerID string) error {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	pivot := s.MinorBlockChain.StateSyncPivot()
+	if pivot == nil {
+		return nil
+	}
+	p := &peer{cm: s.conn, peerID: peerID}
+	if err := qsync.SyncMinorState(p, s.branch.Value, pivot.Root(), s.chainDb, nil); err != nil {
+		return err
+	}
+
+	hashes := s.MinorBlockChain.CrossShardTxListsToSync(pivot)
+	for len(hashes) > 0 {
+		batch := hashes
+		if len(batch) > qsync.CrossShardTxListLimit {
+			batch = batch[:qsync.CrossShardTxListLimit]
+		}
+		lists, err := s.conn.GetCrossShardTxLists(batch, peerID, s.branch.Value)
+		if err != nil {
+			return err
+		}
+		if len(lists) != len(batch) {
+			return fmt.Errorf("peer %s returned %d of %d xshard tx lists requested", peerID, len(lists), len(batch))
+		}
+		for i, list := range lists {
+			if list.MinorBlockHash != batch[i] {
+				return fmt.Errorf("peer %s returned xshard tx list of %x instead of %x", peerID, list.MinorBlockHash, batch[i])
+			}
+			s.MinorBlockChain.AddCrossShardTxListByMinorBlockHash(list.MinorBlockHash, list.TxList)
+		}
+		hashes = hashes[len(batch):]
+	}
+	return s.MinorBlockChain.ActivateStateSyncPivot()
+}
+
 // ######################## miner Methods ##############################
 func (s *ShardBackend) GetWork(coinbaseAddr *account.Address) (*consensus.MiningWork, error) {
 	return s.miner.GetWork(coinbaseAddr)
@@ -403,10 +484,23 @@ func (s *ShardBackend) SubmitWork(headerHash common.Hash, nonce uint64, mixHash
 	return errors.New("submit mined work failed")
 }
 
+func (s *ShardBackend) SubmitShare(headerHash common.Hash, nonce uint64, mixHash common.Hash, shareDiff *big.Int) (bool, error) {
+	return s.miner.SubmitShare(nonce, headerHash, mixHash, shareDiff)
+}
+
 func (s *ShardBackend) InsertMinedBlock(block types.IBlock) error {
 	s.wg.Add(1)
 	defer s.wg.Done()
-	return s.NewMinorBlock("", block.(*types.MinorBlock))
+	// NewMinorBlock returns no error for the blocks it does not add, such as the
+	// known ones or the ones of an unknown parent or root block
+	known := s.MinorBlockChain.HasBlock(block.Hash())
+	if err := s.NewMinorBlock("", block.(*types.MinorBlock)); err != nil {
+		return err
+	}
+	if !known && s.MinorBlockChain.HasBlock(block.Hash()) {
+		s.telemetry.BlockMined(block.IHeader())
+	}
+	return nil
 }
 
 func (s *ShardBackend) GetTip() uint64 {
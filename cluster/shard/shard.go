@@ -6,6 +6,7 @@ import (
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/consensus/simulate"
 	"math/big"
+	"os"
 	"sync"
 
 	"github.com/QuarkChain/goquarkchain/cluster/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/QuarkChain/goquarkchain/cluster/rpc"
 	"github.com/QuarkChain/goquarkchain/cluster/service"
 	synchronizer "github.com/QuarkChain/goquarkchain/cluster/sync"
+	"github.com/QuarkChain/goquarkchain/cluster/telemetry"
 	"github.com/QuarkChain/goquarkchain/consensus"
 	"github.com/QuarkChain/goquarkchain/consensus/doublesha256"
 	"github.com/QuarkChain/goquarkchain/consensus/ethash"
@@ -62,11 +64,16 @@ type ShardBackend struct {
 	synchronizer synchronizer.Synchronizer
 	logInfo      string
 
-	posw consensus.PoSWCalculator
+	posw      consensus.PoSWCalculator
+	telemetry *telemetry.Publisher
 }
 
+// txJournalName is the file in the directory of a shard journaling the local
+// txs of its tx pool.
+const txJournalName = "transactions.journal"
+
 func New(ctx *service.ServiceContext, rBlock *types.RootBlock, conn ConnManager,
-	cfg *config.ClusterConfig, fullshardId uint32) (*ShardBackend, error) {
+	cfg *config.ClusterConfig, fullshardId uint32, telemetry *telemetry.Publisher) (*ShardBackend, error) {
 
 	if cfg == nil {
 		return nil, errors.New("Failed to create shard, cluster config is nil ")
@@ -82,6 +89,7 @@ func New(ctx *service.ServiceContext, rBlock *types.RootBlock, conn ConnManager,
 			eventMux:          ctx.EventMux,
 			logInfo:           fmt.Sprintf("shard:%d", fullshardId),
 			running:           true,
+			telemetry:         telemetry,
 		}
 		err error
 	)
@@ -94,7 +102,7 @@ func New(ctx *service.ServiceContext, rBlock *types.RootBlock, conn ConnManager,
 
 	shard.txGenerator = NewTxGenerator(cfg.GenesisDir, shard.branch.Value, cfg.Quarkchain)
 
-	shard.engine, err = createConsensusEngine(cfg.Quarkchain.EnableQkcHashXHeight, shard.Config)
+	shard.engine, err = CreateConsensusEngine(cfg.Quarkchain.EnableQkcHashXHeight, shard.Config)
 	if err != nil {
 		shard.chainDb.Close()
 		return nil, err
@@ -114,6 +122,12 @@ func New(ctx *service.ServiceContext, rBlock *types.RootBlock, conn ConnManager,
 		return nil, err
 	}
 	shard.MinorBlockChain.SetBroadcastMinorBlockFunc(shard.AddMinorBlock)
+	if journal := ctx.ResolvePath(fmt.Sprintf("shard-%d/%s", fullshardId, txJournalName)); journal != "" {
+		if cfg.Clean {
+			os.Remove(journal)
+		}
+		shard.MinorBlockChain.StartTxJournal(journal)
+	}
 	shard.synchronizer = synchronizer.NewSynchronizer(shard.MinorBlockChain)
 	shard.posw = consensus.CreatePoSWCalculator(shard.MinorBlockChain, shard.Config.PoswConfig)
 
@@ -157,7 +171,8 @@ func createDB(ctx *service.ServiceContext, name string, clean bool, isReadOnly b
 	return db, nil
 }
 
-func createConsensusEngine(qkcHashXHeight uint64, cfg *config.ShardConfig) (consensus.Engine, error) {
+// CreateConsensusEngine creates the consensus engine of the shard of cfg.
+func CreateConsensusEngine(qkcHashXHeight uint64, cfg *config.ShardConfig) (consensus.Engine, error) {
 	difficulty := new(big.Int)
 	diffCalculator := consensus.EthDifficultyCalculator{
 		MinimumDifficulty: difficulty.SetUint64(cfg.Genesis.Difficulty),
@@ -39,18 +39,51 @@ type PublicFilterAPI struct {
 	quit      chan struct{}
 	events    *filters.EventSystem
 	filtersMu sync.Mutex
+	filters   map[rpc.ID]*filter
 }
 
 // NewPublicFilterAPI returns a new PublicFilterAPI instance.
 func NewPublicFilterAPI(backend filters.SlaveFilter) *PublicFilterAPI {
 	api := &PublicFilterAPI{
 		backend: backend,
+		quit:    make(chan struct{}),
 		events:  filters.NewEventSystem(backend),
+		filters: make(map[rpc.ID]*filter),
 	}
 
+	go api.timeoutLoop()
 	return api
 }
 
+// timeoutLoop runs every deadline and deletes filters that have not been polled
+// within it. It is stopped by stop.
+func (api *PublicFilterAPI) timeoutLoop() {
+	ticker := time.NewTicker(deadline)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-api.quit:
+			return
+		}
+		api.filtersMu.Lock()
+		for id, f := range api.filters {
+			select {
+			case <-f.deadline.C:
+				delete(api.filters, id)
+				f.s.Unsubscribe()
+			default:
+				continue
+			}
+		}
+		api.filtersMu.Unlock()
+	}
+}
+
+func (api *PublicFilterAPI) stop() {
+	close(api.quit)
+}
+
 // NewPendingTransactions creates a subscription that is triggered each time a transaction
 // enters the transaction pool and was signed from one of the transactions this nodes manages.
 func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, fullShardId hexutil.Uint) (*rpc.Subscription, error) {
@@ -200,3 +233,130 @@ func (api *PublicFilterAPI) Syncing(ctx context.Context, fullShardId hexutil.Uin
 
 	return rpcSub, nil
 }
+
+// NewBlockFilter creates a filter that fetches the hashes of the blocks appended to
+// the chain of the shard. It is polled with GetFilterChanges.
+func (api *PublicFilterAPI) NewBlockFilter(fullShardId hexutil.Uint) (rpc.ID, error) {
+	if _, err := api.backend.GetShardFilter(uint32(fullShardId)); err != nil {
+		return "", err
+	}
+
+	var (
+		headers   = make(chan *types.MinorBlockHeader, filters.ChainEvChanSize)
+		headerSub = api.events.SubscribeNewHeads(headers, uint32(fullShardId))
+		id        = rpc.ID(headerSub.ID)
+	)
+
+	api.filtersMu.Lock()
+	api.filters[id] = &filter{typ: filters.BlocksSubscription, deadline: time.NewTimer(deadline), hashes: make([]common.Hash, 0), s: headerSub}
+	api.filtersMu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case h := <-headers:
+				api.filtersMu.Lock()
+				if f, found := api.filters[id]; found {
+					f.hashes = append(f.hashes, h.Hash())
+				}
+				api.filtersMu.Unlock()
+			case <-headerSub.Err():
+				api.filtersMu.Lock()
+				delete(api.filters, id)
+				api.filtersMu.Unlock()
+				return
+			}
+		}
+	}()
+
+	return id, nil
+}
+
+// NewFilter creates a filter that fetches the new and the removed logs matching
+// crit in the shard. It is polled with GetFilterChanges.
+func (api *PublicFilterAPI) NewFilter(crit rpc.FilterQuery, fullShardId hexutil.Uint) (rpc.ID, error) {
+	crit.FullShardId = uint32(fullShardId)
+	matchedLogs := make(chan core.LoglistEvent, filters.LogsChanSize)
+	logsSub, err := api.events.SubscribeLogs(crit, matchedLogs)
+	if err != nil {
+		return "", err
+	}
+	id := rpc.ID(logsSub.ID)
+
+	api.filtersMu.Lock()
+	api.filters[id] = &filter{typ: filters.LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub}
+	api.filtersMu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case logs := <-matchedLogs:
+				api.filtersMu.Lock()
+				if f, found := api.filters[id]; found {
+					for _, loglist := range logs.Logs {
+						for _, log := range loglist {
+							// logs are shared with the chain and other filters
+							l := *log
+							l.Removed = logs.IsRemoved
+							f.logs = append(f.logs, &l)
+						}
+					}
+				}
+				api.filtersMu.Unlock()
+			case <-logsSub.Err():
+				api.filtersMu.Lock()
+				delete(api.filters, id)
+				api.filtersMu.Unlock()
+				return
+			}
+		}
+	}()
+
+	return id, nil
+}
+
+// GetFilterChanges returns the block hashes ([]common.Hash) or the logs
+// ([]*types.Log) of the filter since it was last polled, and resets its
+// deadline.
+func (api *PublicFilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
+	api.filtersMu.Lock()
+	defer api.filtersMu.Unlock()
+
+	f, found := api.filters[id]
+	if !found {
+		return nil, filters.ErrInvalidSubscriptionID
+	}
+	if !f.deadline.Stop() {
+		// timer expired but filter is not yet removed in timeout loop
+		// receive timer value and reset timer
+		<-f.deadline.C
+	}
+	f.deadline.Reset(deadline)
+
+	switch f.typ {
+	case filters.BlocksSubscription:
+		hashes := f.hashes
+		f.hashes = make([]common.Hash, 0)
+		return hashes, nil
+	case filters.LogsSubscription:
+		logs := f.logs
+		f.logs = make([]*types.Log, 0)
+		return logs, nil
+	}
+	return nil, filters.ErrInvalidSubscriptionID
+}
+
+// UninstallFilter removes the filter with the given id. It returns whether the
+// filter was found.
+func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
+	api.filtersMu.Lock()
+	f, found := api.filters[id]
+	if found {
+		delete(api.filters, id)
+	}
+	api.filtersMu.Unlock()
+	if found {
+		f.s.Unsubscribe()
+	}
+	return found
+}
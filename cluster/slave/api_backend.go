@@ -1,9 +1,14 @@
 package slave
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
 
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/cluster/rpc"
@@ -12,10 +17,15 @@ import (
 	"github.com/QuarkChain/goquarkchain/cluster/sync"
 	qcom "github.com/QuarkChain/goquarkchain/common"
 	"github.com/QuarkChain/goquarkchain/consensus"
+	"github.com/QuarkChain/goquarkchain/core"
 	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/QuarkChain/goquarkchain/core/vm/tracers"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
 	"github.com/QuarkChain/goquarkchain/p2p"
 	qrpc "github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"golang.org/x/sync/errgroup"
@@ -66,7 +76,7 @@ func (s *SlaveBackend) CreateShards(rootBlock *types.RootBlock, forceInit bool)
 		g.Go(func() error {
 			shardCfg := s.clstrCfg.Quarkchain.GetShardConfigByFullShardID(id)
 			if rootBlock.Number() >= shardCfg.Genesis.RootHeight {
-				shard, err := shard.New(s.ctx, rootBlock, s.connManager, s.clstrCfg, id)
+				shard, err := shard.New(s.ctx, rootBlock, s.connManager, s.clstrCfg, id, s.telemetry)
 				if err != nil {
 					log.Error("Failed to create shard", "slave id", s.config.ID, "shard id", shardCfg.ShardID, "err", err)
 					return err
@@ -90,11 +100,14 @@ func (s *SlaveBackend) CreateShards(rootBlock *types.RootBlock, forceInit bool)
 	return nil
 }
 
-func (s *SlaveBackend) AddBlockListForSync(mHashList []common.Hash, peerId string, branch uint32) (*rpc.ShardStatus, error) {
+func (s *SlaveBackend) AddBlockListForSync(mHashList []common.Hash, peerId string, branch uint32, withoutState bool) (*rpc.ShardStatus, error) {
 	shard, ok := s.shards[branch]
 	if !ok {
 		return nil, ErrMsg("AddBlockListForSync")
 	}
+	// blocks are only stored without state until the shard executed any
+	withoutState = withoutState && (shard.MinorBlockChain.CurrentBlock().NumberU64() == 0 ||
+		shard.MinorBlockChain.StateSyncPivot() != nil)
 
 	hashList := make([]common.Hash, 0, len(mHashList))
 	for _, hash := range mHashList {
@@ -123,7 +136,12 @@ func (s *SlaveBackend) AddBlockListForSync(mHashList []common.Hash, peerId strin
 		if len(bList) != hLen {
 			return nil, errors.New("Failed to add minor blocks for syncing root block: length of downloaded block list is incorrect")
 		}
-		if err := shard.AddBlockListForSync(bList); err != nil {
+		if withoutState {
+			err = shard.AddBlockListWithoutState(bList)
+		} else if err = shard.SyncState(peerId); err == nil {
+			err = shard.AddBlockListForSync(bList)
+		}
+		if err != nil {
 			return nil, err
 		}
 		hashList = hashList[hLen:]
@@ -265,6 +283,61 @@ func (s *SlaveBackend) GetTransactionReceipt(txHash common.Hash, branch uint32)
 	return nil, 0, nil, ErrMsg("GetTransactionReceipt")
 }
 
+// GetTxPoolContent returns the pending and the queued txs of the tx pool of the
+// shard branch, grouped by sender.
+func (s *SlaveBackend) GetTxPoolContent(branch uint32) ([]*rpc.TxPoolAccount, []*rpc.TxPoolAccount, error) {
+	if shard, ok := s.shards[branch]; ok {
+		pending, queued := shard.MinorBlockChain.GetTxPoolContent()
+		return txPoolAccounts(pending), txPoolAccounts(queued), nil
+	}
+	return nil, nil, ErrMsg("GetTxPoolContent")
+}
+
+// GetTxPoolStatus returns the number of pending and of queued txs of the tx
+// pool of the shard branch.
+func (s *SlaveBackend) GetTxPoolStatus(branch uint32) (uint32, uint32, error) {
+	if shard, ok := s.shards[branch]; ok {
+		pending, queued := shard.MinorBlockChain.GetTxPoolStats()
+		return uint32(pending), uint32(queued), nil
+	}
+	return 0, 0, ErrMsg("GetTxPoolStatus")
+}
+
+func txPoolAccounts(txs map[common.Address]types.Transactions) []*rpc.TxPoolAccount {
+	accounts := make([]*rpc.TxPoolAccount, 0, len(txs))
+	for sender, list := range txs {
+		accounts = append(accounts, &rpc.TxPoolAccount{Sender: sender, Txs: list})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Sender.Bytes(), accounts[j].Sender.Bytes()) < 0
+	})
+	return accounts
+}
+
+// GetXShardDepositReceipt returns the receipt of the xshard deposit of the tx
+// txHash in the shard branch, with the block applying it and its index.
+func (s *SlaveBackend) GetXShardDepositReceipt(txHash common.Hash, branch uint32) (*types.MinorBlock, uint32, *types.Receipt, error) {
+	if shard, ok := s.shards[branch]; ok {
+		block, index, receipt := shard.MinorBlockChain.GetXShardDepositReceipt(txHash)
+		return block, index, receipt, nil
+	}
+	return nil, 0, nil, ErrMsg("GetXShardDepositReceipt")
+}
+
+// GetXShardDepositBlock returns the header of the block of the shard branch which
+// applies the xshard deposit of the tx txHash of the minor block mHash confirmed
+// by the root block rHash, or nil if it is not applied yet.
+func (s *SlaveBackend) GetXShardDepositBlock(branch uint32, rHash, mHash, txHash common.Hash) (*types.MinorBlockHeader, error) {
+	if shard, ok := s.shards[branch]; ok {
+		block, err := shard.MinorBlockChain.GetXShardDepositBlock(rHash, mHash, txHash)
+		if err != nil || block == nil {
+			return nil, err
+		}
+		return block.Header(), nil
+	}
+	return nil, ErrMsg("GetXShardDepositBlock")
+}
+
 func (s *SlaveBackend) GetTransactionListByAddress(address *account.Address, transferTokenID *uint64, start []byte, limit uint32) ([]*rpc.TransactionDetail, []byte, error) {
 	branch, err := s.getBranch(address)
 	if err != nil {
@@ -290,6 +363,149 @@ func (s *SlaveBackend) GetLogs(args *qrpc.FilterQuery) ([]*types.Log, error) {
 	return nil, ErrMsg("GetLogs")
 }
 
+// NewFilter installs a filter of the logs matching args in the shard of
+// args.FullShardId, which expires if it is not polled within the deadline.
+func (s *SlaveBackend) NewFilter(args *qrpc.FilterQuery) (string, error) {
+	id, err := s.filterAPI.NewFilter(*args, hexutil.Uint(args.FullShardId))
+	return string(id), err
+}
+
+// NewBlockFilter installs a filter of the blocks appended to the shard, which
+// expires if it is not polled within the deadline.
+func (s *SlaveBackend) NewBlockFilter(branch uint32) (string, error) {
+	id, err := s.filterAPI.NewBlockFilter(hexutil.Uint(branch))
+	return string(id), err
+}
+
+// GetFilterChanges returns the block hashes of a block filter, or the logs of a
+// log filter with their Removed flags, since the filter was last polled.
+func (s *SlaveBackend) GetFilterChanges(id string) ([]common.Hash, []*types.Log, []bool, error) {
+	changes, err := s.filterAPI.GetFilterChanges(qrpc.ID(id))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	switch changes := changes.(type) {
+	case []common.Hash:
+		return changes, nil, nil, nil
+	case []*types.Log:
+		removed := make([]bool, len(changes))
+		for i, l := range changes {
+			removed[i] = l.Removed
+		}
+		return nil, changes, removed, nil
+	}
+	return nil, nil, nil, filters.ErrInvalidSubscriptionID
+}
+
+func (s *SlaveBackend) UninstallFilter(id string) (bool, error) {
+	return s.filterAPI.UninstallFilter(qrpc.ID(id)), nil
+}
+
+// SubscribeEvents sends the events of type typ of all the shards of the slave
+// with send, until ctx is done or send fails.
+func (s *SlaveBackend) SubscribeEvents(ctx context.Context, typ uint8, send func(*rpc.ShardEvent) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var (
+		events = make(chan *rpc.ShardEvent, filters.ChainEvChanSize)
+		subs   = make([]*filters.Subscription, 0, len(s.shards))
+		es     = s.filterAPI.events
+	)
+	defer func() {
+		cancel()
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}()
+	forward := func(ev *rpc.ShardEvent) bool {
+		select {
+		case events <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	s.lock.RLock()
+	fullShardIds := make([]uint32, 0, len(s.shards))
+	for id := range s.shards {
+		fullShardIds = append(fullShardIds, id)
+	}
+	s.lock.RUnlock()
+	for _, id := range fullShardIds {
+		id := id
+		switch typ {
+		case rpc.NewHeadsEvent:
+			headers := make(chan *types.MinorBlockHeader, filters.ChainEvChanSize)
+			subs = append(subs, es.SubscribeNewHeads(headers, id))
+			go func() {
+				for {
+					select {
+					case h := <-headers:
+						if !forward(&rpc.ShardEvent{Branch: id, Header: h}) {
+							return
+						}
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		case rpc.LogsEvent:
+			logs := make(chan core.LoglistEvent, filters.LogsChanSize)
+			// no bounds, so that the logs removed from the blocks below the
+			// current tip are sent too
+			crit := qrpc.FilterQuery{FullShardId: id}
+			crit.FromBlock, crit.ToBlock = new(big.Int), big.NewInt(math.MaxInt64)
+			sub, err := es.SubscribeLogs(crit, logs)
+			if err != nil {
+				return err
+			}
+			subs = append(subs, sub)
+			go func() {
+				for {
+					select {
+					case ev := <-logs:
+						for _, loglist := range ev.Logs {
+							if !forward(&rpc.ShardEvent{Branch: id, Logs: loglist, Removed: ev.IsRemoved}) {
+								return
+							}
+						}
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		case rpc.PendingTxsEvent:
+			txs := make(chan []*types.Transaction, filters.TxsChanSize)
+			subs = append(subs, es.SubscribePendingTxs(txs, id))
+			go func() {
+				for {
+					select {
+					case txList := <-txs:
+						if !forward(&rpc.ShardEvent{Branch: id, Txs: txList}) {
+							return
+						}
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		default:
+			return fmt.Errorf("unknown event type %d", typ)
+		}
+	}
+
+	for {
+		select {
+		case ev := <-events:
+			if err := send(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 func (s *SlaveBackend) EstimateGas(tx *types.Transaction, address *account.Address) (uint32, error) {
 	fullShardId, err := s.clstrCfg.Quarkchain.GetFullShardIdByFullShardKey(address.FullShardKey)
 	if err != nil {
@@ -342,6 +558,13 @@ func (s *SlaveBackend) GasPrice(branch uint32, tokenID uint64) (uint64, error) {
 	return 0, ErrMsg("GasPrice")
 }
 
+func (s *SlaveBackend) GasPriceRate(branch uint32, tokenID uint64) (uint64, error) {
+	if shard, ok := s.shards[branch]; ok {
+		return shard.MinorBlockChain.GasPriceRate(tokenID)
+	}
+	return 0, ErrMsg("GasPriceRate")
+}
+
 func (s *SlaveBackend) GetWork(branch uint32, coinbaseAddr *account.Address) (*consensus.MiningWork, error) {
 	if shard, ok := s.shards[branch]; ok {
 		return shard.GetWork(coinbaseAddr)
@@ -356,6 +579,13 @@ func (s *SlaveBackend) SubmitWork(headerHash common.Hash, nonce uint64, mixHash
 	return ErrMsg("SubmitWork")
 }
 
+func (s *SlaveBackend) SubmitShare(headerHash common.Hash, nonce uint64, mixHash common.Hash, shareDiff *big.Int, branch uint32) (bool, error) {
+	if shard, ok := s.shards[branch]; ok {
+		return shard.SubmitShare(headerHash, nonce, mixHash, shareDiff)
+	}
+	return false, ErrMsg("SubmitShare")
+}
+
 func (s *SlaveBackend) AddCrossShardTxListByMinorBlockHash(minorHash common.Hash,
 	txList []*types.CrossShardTransactionDeposit, branch uint32) error {
 	if shard, ok := s.shards[branch]; ok {
@@ -388,6 +618,38 @@ func (s *SlaveBackend) GetMinorBlockListByHashList(mHashList []common.Hash, bran
 	return minorList, nil
 }
 
+// GetTrieNodes returns the state trie nodes of the shard the peer asks for
+// state sync, skipping those not found.
+func (s *SlaveBackend) GetTrieNodes(hashes []common.Hash, branch uint32) ([]*p2p.TrieNode, error) {
+	shrd, ok := s.shards[branch]
+	if !ok {
+		return nil, ErrMsg("GetTrieNodes")
+	}
+	nodes := make([]*p2p.TrieNode, 0, len(hashes))
+	for _, hash := range hashes {
+		if data, err := shrd.MinorBlockChain.TrieNode(hash); err == nil {
+			nodes = append(nodes, &p2p.TrieNode{Data: data})
+		}
+	}
+	return nodes, nil
+}
+
+// GetCrossShardTxLists returns the xshard tx lists the shard keeps for the
+// neighbor minor blocks the peer asks for state sync, skipping those not found.
+func (s *SlaveBackend) GetCrossShardTxLists(mHashList []common.Hash, branch uint32) ([]*p2p.CrossShardTxList, error) {
+	shrd, ok := s.shards[branch]
+	if !ok {
+		return nil, ErrMsg("GetCrossShardTxLists")
+	}
+	lists := make([]*p2p.CrossShardTxList, 0, len(mHashList))
+	for _, hash := range mHashList {
+		if list := shrd.MinorBlockChain.ReadCrossShardTxList(hash); list != nil {
+			lists = append(lists, &p2p.CrossShardTxList{MinorBlockHash: hash, TxList: *list})
+		}
+	}
+	return lists, nil
+}
+
 func (s *SlaveBackend) getMinorBlockHeaders(req *p2p.GetMinorBlockHeaderListRequest) ([]*types.MinorBlockHeader, error) {
 	shard, ok := s.shards[req.Branch.Value]
 	if !ok {
@@ -540,3 +802,150 @@ func (s *SlaveBackend) GetRootChainStakes(address account.Address, lastMinor com
 	}
 	return nil, nil, errors.New("not chain 0 shard 0")
 }
+
+func (s *SlaveBackend) TraceTransaction(txHash common.Hash, branch uint32, config *rpc.TraceConfig) ([]byte, error) {
+	if shard, ok := s.shards[branch]; ok {
+		block, index := shard.MinorBlockChain.GetTransactionByHash(txHash)
+		if block == nil || int(index) >= len(block.Transactions()) {
+			return nil, fmt.Errorf("transaction %v not found", txHash.String())
+		}
+		tracer, err := newTracer(config, block.Transactions()[index].EvmTx.Gas())
+		if err != nil {
+			return nil, err
+		}
+		ret, gas, failed, err := shard.MinorBlockChain.TraceTransaction(txHash, tracer)
+		if err != nil {
+			return nil, err
+		}
+		return traceResult(tracer, ret, gas, failed)
+	}
+	return nil, ErrMsg("TraceTransaction")
+}
+
+func (s *SlaveBackend) TraceCall(tx *types.Transaction, address *account.Address, height *uint64, config *rpc.TraceConfig) ([]byte, error) {
+	fromShardSize, err := s.clstrCfg.Quarkchain.GetShardSizeByChainId(tx.EvmTx.FromChainID())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.EvmTx.SetFromShardSize(fromShardSize); err != nil {
+		return nil, err
+	}
+	if shard, ok := s.shards[tx.EvmTx.FromFullShardId()]; ok {
+		tracer, err := newTracer(config, tx.EvmTx.Gas())
+		if err != nil {
+			return nil, err
+		}
+		ret, gas, failed, err := shard.MinorBlockChain.TraceCall(tx, address, height, tracer)
+		if err != nil {
+			return nil, err
+		}
+		return traceResult(tracer, ret, gas, failed)
+	}
+	return nil, ErrMsg("TraceCall")
+}
+
+// TraceBlockByHash re-executes the minor block with the tracer of config attached
+// and returns the traces of its xshard deposits and txs, together with the gas used,
+// the xshard cursor and the state root of the replay next to the ones recorded in the block.
+func (s *SlaveBackend) TraceBlockByHash(blockHash common.Hash, branch uint32, config *rpc.TraceConfig) ([]byte, error) {
+	if shard, ok := s.shards[branch]; ok {
+		// check the tracer once as the tracer of each tx is created without error
+		if _, err := newTracer(config, 0); err != nil {
+			return nil, err
+		}
+		blockTracer := tracers.NewBlockTracer(func(ctx *tracers.Context) vm.Tracer {
+			tracer, _ := newTracer(config, ctx.GasLimit)
+			return tracer
+		})
+		block, evmState, err := shard.MinorBlockChain.TraceBlock(blockHash, blockTracer)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			deposits = make([]map[string]interface{}, 0)
+			txs      = make([]map[string]interface{}, 0)
+		)
+		for _, trace := range blockTracer.Traces() {
+			if trace.XShardDeposit {
+				deposits = append(deposits, txTraceResult(trace))
+			} else {
+				txs = append(txs, txTraceResult(trace))
+			}
+		}
+		return json.Marshal(map[string]interface{}{
+			"hash":   block.Hash(),
+			"height": hexutil.Uint64(block.NumberU64()),
+			"replayed": map[string]interface{}{
+				"gasUsed":              (*hexutil.Big)(evmState.GetGasUsed()),
+				"xShardReceiveGasUsed": (*hexutil.Big)(evmState.GetXShardReceiveGasUsed()),
+				"xShardTxCursorInfo":   evmState.GetTxCursorInfo(),
+				"stateRoot":            evmState.IntermediateRoot(true),
+			},
+			"recorded": map[string]interface{}{
+				"gasUsed":              (*hexutil.Big)(block.GasUsed()),
+				"xShardReceiveGasUsed": (*hexutil.Big)(block.CrossShardGasUsed()),
+				"xShardTxCursorInfo":   block.Meta().XShardTxCursorInfo,
+				"stateRoot":            block.Root(),
+			},
+			"xShardDeposits": deposits,
+			"transactions":   txs,
+		})
+	}
+	return nil, ErrMsg("TraceBlockByHash")
+}
+
+// txTraceResult encodes the trace of a single xshard deposit or tx of a traced block,
+// the receipt is missing for deposits applied before evm is enabled.
+func txTraceResult(trace *tracers.TxTrace) map[string]interface{} {
+	field := map[string]interface{}{
+		"txHash": trace.TxHash,
+	}
+	var (
+		gas    uint64
+		failed bool
+	)
+	if trace.Receipt != nil {
+		gas, failed = trace.Receipt.GasUsed, trace.Receipt.Status == types.ReceiptStatusFailed
+		field["gasUsed"] = hexutil.Uint64(gas)
+		field["status"] = hexutil.Uint64(trace.Receipt.Status)
+	}
+	var output []byte
+	if logger, ok := trace.Tracer.(*vm.StructLogger); ok {
+		output = logger.Output()
+	}
+	result, err := traceResult(trace.Tracer, output, gas, failed)
+	if err != nil {
+		field["error"] = err.Error()
+	} else {
+		field["result"] = json.RawMessage(result)
+	}
+	return field
+}
+
+// newTracer returns the named tracer of config for a tx with the given gas limit,
+// or a struct logger if no tracer is named.
+func newTracer(config *rpc.TraceConfig, gasLimit uint64) (vm.Tracer, error) {
+	if config == nil {
+		return vm.NewStructLogger(nil), nil
+	}
+	if config.Tracer != "" {
+		return tracers.New(config.Tracer, &tracers.Context{GasLimit: gasLimit})
+	}
+	return vm.NewStructLogger(&vm.LogConfig{
+		DisableStorage: config.DisableStorage,
+		DisableMemory:  config.DisableMemory,
+		DisableStack:   config.DisableStack,
+		Limit:          int(config.Limit),
+	}), nil
+}
+
+func traceResult(tracer vm.Tracer, ret []byte, gas uint64, failed bool) ([]byte, error) {
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		return json.Marshal(encoder.ExecutionResultEncoder(gas, failed, ret, tracer.StructLogs()))
+	case tracers.Tracer:
+		return tracer.GetResult()
+	default:
+		return nil, fmt.Errorf("unsupported tracer %T", tracer)
+	}
+}
@@ -1,14 +1,19 @@
 package slave
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"github.com/QuarkChain/goquarkchain/account"
+	qkcrpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
+	"github.com/QuarkChain/goquarkchain/cluster/shard"
 	"github.com/QuarkChain/goquarkchain/cluster/sync"
+	"github.com/QuarkChain/goquarkchain/core"
 	"github.com/QuarkChain/goquarkchain/core/types"
 	"github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"math/big"
@@ -181,6 +186,120 @@ func TestNewPendingTransactions(t *testing.T) {
 	}
 }
 
+func TestFilterChanges(t *testing.T) {
+	bak, err := newTestBackend()
+	assert.NoError(t, err)
+	defer bak.stop()
+	api := NewPublicFilterAPI(bak)
+	defer api.stop()
+
+	blockID, err := api.NewBlockFilter(hexutil.Uint(bak.curShardId))
+	assert.NoError(t, err)
+	logID, err := api.NewFilter(rpc.FilterQuery{}, hexutil.Uint(bak.curShardId))
+	assert.NoError(t, err)
+
+	time.Sleep(500 * time.Millisecond)
+	blocks, err := bak.cresteMinorBlocks(3)
+	assert.NoError(t, err)
+	log := &types.Log{BlockNumber: 1, Data: []byte("qkc")}
+	bak.logsFeed.Send(core.LoglistEvent{Logs: [][]*types.Log{{log}}, IsRemoved: true})
+
+	// events are forwarded to the filters periodically
+	var (
+		hashes []common.Hash
+		logs   []*types.Log
+	)
+	for i := 0; i < 100 && (len(hashes) < len(blocks) || len(logs) == 0); i++ {
+		time.Sleep(100 * time.Millisecond)
+		changes, err := api.GetFilterChanges(blockID)
+		assert.NoError(t, err)
+		hashes = append(hashes, changes.([]common.Hash)...)
+		changes, err = api.GetFilterChanges(logID)
+		assert.NoError(t, err)
+		logs = append(logs, changes.([]*types.Log)...)
+	}
+	assert.Equal(t, len(blocks), len(hashes))
+	for i, block := range blocks {
+		assert.Equal(t, block.Hash(), hashes[i])
+	}
+	changes, err := api.GetFilterChanges(blockID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(changes.([]common.Hash)))
+
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, log.Data, logs[0].Data)
+	assert.True(t, logs[0].Removed)
+	assert.False(t, log.Removed)
+
+	assert.True(t, api.UninstallFilter(logID))
+	assert.False(t, api.UninstallFilter(logID))
+	_, err = api.GetFilterChanges(logID)
+	assert.Error(t, err)
+}
+
+func TestFilterTimeout(t *testing.T) {
+	bak, err := newTestBackend()
+	assert.NoError(t, err)
+	defer bak.stop()
+
+	defer func(d time.Duration) { deadline = d }(deadline)
+	deadline = 100 * time.Millisecond
+	api := NewPublicFilterAPI(bak)
+	defer api.stop()
+
+	id, err := api.NewBlockFilter(hexutil.Uint(bak.curShardId))
+	assert.NoError(t, err)
+	_, err = api.GetFilterChanges(id)
+	assert.NoError(t, err)
+
+	time.Sleep(5 * deadline)
+	_, err = api.GetFilterChanges(id)
+	assert.Error(t, err)
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	bak, err := newTestBackend()
+	assert.NoError(t, err)
+	defer bak.stop()
+	api := NewPublicFilterAPI(bak)
+	defer api.stop()
+	slave := &SlaveBackend{
+		filterAPI: api,
+		shards:    map[uint32]*shard.ShardBackend{bak.curShardId: nil},
+	}
+
+	send := func(ev *qkcrpc.ShardEvent) error { return nil }
+	assert.Error(t, slave.SubscribeEvents(context.Background(), 0, send))
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		events      = make(chan *qkcrpc.ShardEvent, 10)
+		errc        = make(chan error)
+	)
+	go func() {
+		errc <- slave.SubscribeEvents(ctx, qkcrpc.LogsEvent, func(ev *qkcrpc.ShardEvent) error {
+			events <- ev
+			return nil
+		})
+	}()
+	time.Sleep(500 * time.Millisecond)
+	log := &types.Log{BlockNumber: 1, Data: []byte("qkc")}
+	bak.logsFeed.Send(core.LoglistEvent{Logs: [][]*types.Log{{log}}, IsRemoved: true})
+
+	// events are forwarded to the subscriptions periodically
+	select {
+	case ev := <-events:
+		assert.Equal(t, bak.curShardId, ev.Branch)
+		assert.Equal(t, 1, len(ev.Logs))
+		assert.Equal(t, log.Data, ev.Logs[0].Data)
+		assert.True(t, ev.Removed)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no event received")
+	}
+	cancel()
+	assert.NoError(t, <-errc)
+}
+
 func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 	var (
 		fromBlock rpc.BlockNumber = 0x123435
@@ -7,6 +7,7 @@ import (
 	"github.com/QuarkChain/goquarkchain/cluster/config"
 	"github.com/QuarkChain/goquarkchain/cluster/service"
 	"github.com/QuarkChain/goquarkchain/cluster/shard"
+	"github.com/QuarkChain/goquarkchain/cluster/telemetry"
 	"github.com/QuarkChain/goquarkchain/core/vm"
 	"github.com/QuarkChain/goquarkchain/p2p"
 	"github.com/QuarkChain/goquarkchain/params"
@@ -24,9 +25,11 @@ type SlaveBackend struct {
 	lock   sync.RWMutex
 	shards map[uint32]*shard.ShardBackend
 
-	ctx      *service.ServiceContext
-	eventMux *event.TypeMux
-	logInfo  string
+	ctx       *service.ServiceContext
+	eventMux  *event.TypeMux
+	filterAPI *PublicFilterAPI
+	telemetry *telemetry.Publisher
+	logInfo   string
 }
 
 func New(ctx *service.ServiceContext, clusterCfg *config.ClusterConfig, cfg *config.SlaveConfig) (*SlaveBackend, error) {
@@ -40,6 +43,11 @@ func New(ctx *service.ServiceContext, clusterCfg *config.ClusterConfig, cfg *con
 		logInfo:       "SlaveBackend",
 	}
 
+	var err error
+	if slave.telemetry, err = telemetry.New(clusterCfg.Monitoring); err != nil {
+		return nil, err
+	}
+
 	slave.clstrCfg.Quarkchain.SetAllowedToken()
 	fullShardIds := slave.clstrCfg.Quarkchain.GetGenesisShardIds()
 	for _, id := range fullShardIds {
@@ -50,6 +58,7 @@ func New(ctx *service.ServiceContext, clusterCfg *config.ClusterConfig, cfg *con
 	}
 
 	slave.connManager = NewToSlaveConnManager(slave.clstrCfg, slave)
+	slave.filterAPI = NewPublicFilterAPI(slave)
 	slave.setPrecompiledContractsEnableTime(clusterCfg.Quarkchain.EnableEvmTimeStamp)
 	return slave, nil
 }
@@ -113,7 +122,7 @@ func (s *SlaveBackend) APIs() []rpc.API {
 			rpc.API{
 				Namespace: "ws",
 				Version:   "3.0",
-				Service:   NewPublicFilterAPI(s), // Private slave api
+				Service:   s.filterAPI, // Private slave api
 				Public:    true,
 			})
 	}
@@ -127,6 +136,8 @@ func (s *SlaveBackend) Stop() error {
 		delete(s.shards, target)
 	}
 	s.connManager.Stop()
+	s.filterAPI.stop()
+	s.telemetry.Stop()
 	return nil
 }
 
@@ -120,6 +120,63 @@ func (s *ConnManager) GetMinorBlocks(mHeaderList []common.Hash, peerId string, b
 	return gRep.MinorBlockList, nil
 }
 
+func (s *ConnManager) GetTrieNodes(hashes []common.Hash, peerId string, branch uint32) ([][]byte, error) {
+	var (
+		gReq = rpc.P2PRedirectRequest{PeerID: peerId, Branch: branch}
+		gRep p2p.GetTrieNodesResponse
+		err  error
+	)
+	gReq.Data, err = serialize.SerializeToBytes(p2p.GetTrieNodesRequest{NodeHashList: hashes})
+	if err != nil {
+		return nil, err
+	}
+	data, err := serialize.SerializeToBytes(gReq)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.masterClient.client.Call(s.masterClient.target, &rpc.Request{Op: rpc.OpGetTrieNodes, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = serialize.DeserializeFromBytes(res.Data, &gRep); err != nil {
+		return nil, err
+	}
+
+	nodes := make([][]byte, 0, len(gRep.NodeList))
+	for _, node := range gRep.NodeList {
+		nodes = append(nodes, node.Data)
+	}
+	return nodes, nil
+}
+
+func (s *ConnManager) GetCrossShardTxLists(mHashList []common.Hash, peerId string, branch uint32) ([]*p2p.CrossShardTxList, error) {
+	var (
+		gReq = rpc.P2PRedirectRequest{PeerID: peerId, Branch: branch}
+		gRep p2p.GetCrossShardTxListsResponse
+		err  error
+	)
+	gReq.Data, err = serialize.SerializeToBytes(p2p.GetCrossShardTxListsRequest{MinorBlockHashList: mHashList})
+	if err != nil {
+		return nil, err
+	}
+	data, err := serialize.SerializeToBytes(gReq)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.masterClient.client.Call(s.masterClient.target, &rpc.Request{Op: rpc.OpGetCrossShardTxLists, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = serialize.DeserializeFromBytes(res.Data, &gRep); err != nil {
+		return nil, err
+	}
+	return gRep.CrossShardTxLists, nil
+}
+
 func (s *ConnManager) GetMinorBlockHeaderList(gReq *rpc.GetMinorBlockHeaderListWithSkipRequest) ([]*types.MinorBlockHeader, error) {
 	var (
 		gRep p2p.GetMinorBlockHeaderListResponse
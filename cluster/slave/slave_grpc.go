@@ -518,6 +518,267 @@ func (s *SlaveServerSideOp) GetRootChainStakes(ctx context.Context, req *rpc.Req
 	return response, nil
 }
 
+func (s *SlaveServerSideOp) TraceTransaction(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.TraceTransactionRequest
+		gRes     rpc.TraceResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Result, err = s.slave.TraceTransaction(gReq.TxHash, gReq.Branch, gReq.Config); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) TraceCall(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.TraceCallRequest
+		gRes     rpc.TraceResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Result, err = s.slave.TraceCall(gReq.Tx, gReq.FromAddress, gReq.BlockHeight, gReq.Config); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) TraceBlockByHash(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.TraceBlockByHashRequest
+		gRes     rpc.TraceResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Result, err = s.slave.TraceBlockByHash(gReq.BlockHash, gReq.Branch, gReq.Config); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) NewFilter(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     qrpc.FilterQuery
+		gRes     rpc.NewFilterResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.FilterID, err = s.slave.NewFilter(&gReq); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) NewBlockFilter(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.NewBlockFilterRequest
+		gRes     rpc.NewFilterResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.FilterID, err = s.slave.NewBlockFilter(gReq.Branch); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) GetFilterChanges(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.FilterRequest
+		gRes     rpc.GetFilterChangesResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Hashes, gRes.Logs, gRes.Removed, err = s.slave.GetFilterChanges(gReq.FilterID); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) UninstallFilter(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.FilterRequest
+		gRes     rpc.UninstallFilterResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Uninstalled, err = s.slave.UninstallFilter(gReq.FilterID); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) SubscribeEvents(req *rpc.Request, stream rpc.SlaveServerSideOp_SubscribeEventsServer) error {
+	var gReq rpc.SubscribeEventsRequest
+	if err := serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return err
+	}
+	return s.slave.SubscribeEvents(stream.Context(), gReq.Type, func(ev *rpc.ShardEvent) error {
+		data, err := serialize.SerializeToBytes(ev)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&rpc.Response{RpcId: req.RpcId, Data: data})
+	})
+}
+
+func (s *SlaveServerSideOp) GetXShardDepositBlock(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.GetXShardDepositBlockRequest
+		gRes     rpc.GetXShardDepositBlockResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Header, err = s.slave.GetXShardDepositBlock(gReq.Branch, gReq.RootBlockHash, gReq.MinorBlockHash, gReq.TxHash); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) GetXShardDepositReceipt(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.GetTransactionReceiptRequest
+		gRes     rpc.GetXShardDepositReceiptResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.MinorBlock, gRes.Index, gRes.Receipt, err = s.slave.GetXShardDepositReceipt(gReq.TxHash, gReq.Branch); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) GetTxPoolContent(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.TxPoolRequest
+		gRes     rpc.GetTxPoolContentResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Pending, gRes.Queued, err = s.slave.GetTxPoolContent(gReq.Branch); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) GetTxPoolStatus(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.TxPoolRequest
+		gRes     rpc.GetTxPoolStatusResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Pending, gRes.Queued, err = s.slave.GetTxPoolStatus(gReq.Branch); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) SubmitShare(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.SubmitShareRequest
+		gRes     rpc.SubmitShareResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Block, err = s.slave.SubmitShare(gReq.HeaderHash, gReq.Nonce, gReq.MixHash, gReq.ShareDifficulty, gReq.Branch); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) GasPriceRate(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.GasPriceRequest
+		gRes     rpc.GasPriceResponse
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if gRes.Result, err = s.slave.GasPriceRate(gReq.Branch, gReq.TokenID); err != nil {
+		return nil, err
+	}
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 // check if the blocks are vailed.
 func (s *SlaveServerSideOp) AddMinorBlockListForSync(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
 	var (
@@ -532,7 +793,7 @@ func (s *SlaveServerSideOp) AddMinorBlockListForSync(ctx context.Context, req *r
 	if len(gReq.MinorBlockHashList) == 0 {
 		return response, nil
 	}
-	if gRes.ShardStatus, err = s.slave.AddBlockListForSync(gReq.MinorBlockHashList, gReq.PeerId, gReq.Branch); err != nil {
+	if gRes.ShardStatus, err = s.slave.AddBlockListForSync(gReq.MinorBlockHashList, gReq.PeerId, gReq.Branch, gReq.WithoutState); err != nil {
 		return nil, err
 	}
 	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
@@ -571,6 +832,64 @@ func (s *SlaveServerSideOp) GetMinorBlockList(ctx context.Context, req *rpc.Requ
 	return response, nil
 }
 
+func (s *SlaveServerSideOp) GetTrieNodes(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.P2PRedirectRequest
+		gRes     p2p.GetTrieNodesResponse
+		nodeReq  p2p.GetTrieNodesRequest
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if err = serialize.DeserializeFromBytes(gReq.Data, &nodeReq); err != nil {
+		return nil, err
+	}
+	if len(nodeReq.NodeHashList) > 2*qsync.TrieNodeBatchSize {
+		return nil, fmt.Errorf("bad number of trie nodes requested. branch: %d; limit: %d; expected limit: %d",
+			gReq.Branch, len(nodeReq.NodeHashList), qsync.TrieNodeBatchSize)
+	}
+
+	if gRes.NodeList, err = s.slave.GetTrieNodes(nodeReq.NodeHashList, gReq.Branch); err != nil {
+		return nil, err
+	}
+
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SlaveServerSideOp) GetCrossShardTxLists(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+	var (
+		gReq     rpc.P2PRedirectRequest
+		gRes     p2p.GetCrossShardTxListsResponse
+		listReq  p2p.GetCrossShardTxListsRequest
+		response = &rpc.Response{RpcId: req.RpcId}
+		err      error
+	)
+	if err = serialize.DeserializeFromBytes(req.Data, &gReq); err != nil {
+		return nil, err
+	}
+	if err = serialize.DeserializeFromBytes(gReq.Data, &listReq); err != nil {
+		return nil, err
+	}
+	if len(listReq.MinorBlockHashList) > qsync.CrossShardTxListLimit {
+		return nil, fmt.Errorf("bad number of xshard tx lists requested. branch: %d; limit: %d; expected limit: %d",
+			gReq.Branch, len(listReq.MinorBlockHashList), qsync.CrossShardTxListLimit)
+	}
+
+	if gRes.CrossShardTxLists, err = s.slave.GetCrossShardTxLists(listReq.MinorBlockHashList, gReq.Branch); err != nil {
+		return nil, err
+	}
+
+	if response.Data, err = serialize.SerializeToBytes(gRes); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 func (s *SlaveServerSideOp) GetMinorBlockHeaderListWithSkip(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
 	var (
 		gReq     rpc.P2PRedirectRequest
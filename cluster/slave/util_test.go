@@ -150,7 +150,7 @@ func (b *testBackend) GetTransactionByHash(txHash common.Hash, branch uint32) (*
 }
 
 func (b *testBackend) GetHeaderByNumber(height rpc.BlockNumber) (*types.MinorBlockHeader, error) {
-	panic("not implemented")
+	return b.mGenesis.Header(), nil
 }
 
 func (b *testBackend) GetHeaderByHash(blockHash common.Hash) (*types.MinorBlockHeader, error) {
@@ -0,0 +1,239 @@
+// Package stratum implements a Stratum mining server on the master. Miners
+// subscribe to the root chain or to a shard, are pushed new work whenever the
+// tip of the chain changes and submit their solutions over the same
+// connection, instead of polling getWork and submitWork over JSON-RPC.
+//
+// If a share difficulty is configured for the chain, miners submit shares at a
+// difficulty retargeted for each connection, and the shares of each worker are
+// counted for a pool to pay its miners.
+package stratum
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// refreshInterval is how often the work of the miners is refreshed when no
+	// tip changes, so that it includes the latest txs.
+	refreshInterval = 10 * time.Second
+	// tipChanSize is the size of the channel of tip changes to push work for.
+	tipChanSize = 64
+	// rootChain is the key of the root chain among the full shard ids.
+	rootChain = math.MaxUint64
+)
+
+// Backend is the mining API of the master the work is fetched from and the
+// solutions are submitted to. The root chain is mined if fullShardId is nil.
+type Backend interface {
+	GetWork(fullShardId *uint32, addr *common.Address) (*consensus.MiningWork, error)
+	SubmitWork(fullShardId *uint32, headerHash common.Hash, nonce uint64, mixHash common.Hash, signature *[65]byte) (bool, error)
+	SubmitShare(fullShardId *uint32, headerHash common.Hash, nonce uint64, mixHash common.Hash, shareDiff *big.Int) (bool, error)
+}
+
+// Server accepts the Stratum connections of miners on a TCP endpoint.
+type Server struct {
+	backend  Backend
+	qkcCfg   *config.QuarkChainConfig
+	endpoint string
+	listener net.Listener
+
+	mu       sync.Mutex
+	sessions map[*session]struct{}
+	nextID   uint64
+
+	statsMu sync.Mutex
+	stats   map[workerKey]*workerStats
+	workers map[common.Address]int // workers of each coinbase
+
+	tipCh  chan uint64
+	exitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a Stratum server listening on endpoint once started.
+func New(backend Backend, qkcCfg *config.QuarkChainConfig, endpoint string) *Server {
+	return &Server{
+		backend:  backend,
+		qkcCfg:   qkcCfg,
+		endpoint: endpoint,
+		sessions: make(map[*session]struct{}),
+		stats:    make(map[workerKey]*workerStats),
+		workers:  make(map[common.Address]int),
+		tipCh:    make(chan uint64, tipChanSize),
+		exitCh:   make(chan struct{}),
+	}
+}
+
+// Start starts listening for miners.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.endpoint)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.wg.Add(2)
+	go s.acceptLoop()
+	go s.refreshLoop()
+	log.Info("Stratum endpoint opened", "url", fmt.Sprintf("stratum+tcp://%s", listener.Addr()))
+	return nil
+}
+
+// Stop closes the endpoint and the connections of all miners.
+func (s *Server) Stop() {
+	if s.listener == nil {
+		return
+	}
+	close(s.exitCh)
+	s.listener.Close()
+	s.mu.Lock()
+	for sess := range s.sessions {
+		sess.conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	log.Info("Stratum endpoint closed", "url", fmt.Sprintf("stratum+tcp://%s", s.listener.Addr()))
+}
+
+// NotifyNewTip pushes new work to the miners of the shard, or of all chains if
+// fullShardId is nil as the work of the shards is built on the root tip.
+func (s *Server) NotifyNewTip(fullShardId *uint32) {
+	select {
+	case s.tipCh <- chainKey(fullShardId):
+	default:
+		log.Warn("Stratum tip change dropped", "fullShardId", fullShardId)
+	}
+}
+
+// shareDifficulty returns the minimum difficulty of the shares of the chain, or
+// nil if it accepts only block solutions.
+func (s *Server) shareDifficulty(fullShardId *uint32) *big.Int {
+	powCfg := s.qkcCfg.Root.ConsensusConfig
+	if fullShardId != nil {
+		shardCfg := s.qkcCfg.GetShardConfigByFullShardID(*fullShardId)
+		if shardCfg == nil {
+			return nil
+		}
+		powCfg = shardCfg.ConsensusConfig
+	}
+	if powCfg == nil || powCfg.ShareDifficulty == 0 {
+		return nil
+	}
+	return new(big.Int).SetUint64(powCfg.ShareDifficulty)
+}
+
+func chainKey(fullShardId *uint32) uint64 {
+	if fullShardId == nil {
+		return rootChain
+	}
+	return uint64(*fullShardId)
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.exitCh:
+				return
+			default:
+			}
+			log.Warn("Stratum accept error", "err", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		s.mu.Lock()
+		s.nextID++
+		sess := newSession(s, conn, s.nextID)
+		s.sessions[sess] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			sess.serve()
+			s.mu.Lock()
+			delete(s.sessions, sess)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+func (s *Server) refreshLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(statsPruneInterval)
+	defer pruneTicker.Stop()
+	for {
+		select {
+		case key := <-s.tipCh:
+			if key == rootChain {
+				s.refresh(nil)
+			} else {
+				s.refresh(&key)
+			}
+		case <-ticker.C:
+			s.refresh(nil)
+		case now := <-pruneTicker.C:
+			s.pruneStats(now)
+		case <-s.exitCh:
+			return
+		}
+	}
+}
+
+// refresh fetches the work of the authorized miners of the chain, or of all
+// chains if key is nil, and pushes it to those whose work changed. The work
+// is fetched once for the miners of a chain sharing a coinbase.
+func (s *Server) refresh(key *uint64) {
+	type workKey struct {
+		chain    uint64
+		coinbase common.Address
+	}
+	var (
+		groups       = make(map[workKey][]*session)
+		fullShardIds = make(map[uint64]*uint32)
+	)
+	s.mu.Lock()
+	for sess := range s.sessions {
+		fullShardId, coinbase, ok := sess.miningOn()
+		chain := chainKey(fullShardId)
+		if !ok || (key != nil && chain != *key) {
+			continue
+		}
+		k := workKey{chain, coinbase}
+		groups[k] = append(groups[k], sess)
+		fullShardIds[chain] = fullShardId
+	}
+	s.mu.Unlock()
+
+	for k, sessions := range groups {
+		work, err := s.backend.GetWork(fullShardIds[k.chain], &k.coinbase)
+		if err != nil {
+			log.Debug("Stratum get work error", "chain", k.chain, "coinbase", k.coinbase.Hex(), "err", err)
+			continue
+		}
+		now := time.Now()
+		for _, sess := range sessions {
+			err := sess.pushWork(work)
+			if err == nil {
+				// lower the difficulty of miners not submitting shares
+				err = sess.retarget(now)
+			}
+			if err != nil {
+				sess.conn.Close()
+			}
+		}
+	}
+}
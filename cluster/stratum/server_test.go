@@ -0,0 +1,379 @@
+package stratum
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type submission struct {
+	fullShardId *uint32
+	headerHash  common.Hash
+	nonce       uint64
+	mixHash     common.Hash
+	shareDiff   *big.Int
+}
+
+type fakeBackend struct {
+	mu          sync.Mutex
+	works       map[uint64]*consensus.MiningWork
+	submissions []submission
+}
+
+func (b *fakeBackend) setWork(fullShardId *uint32, work *consensus.MiningWork) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.works[chainKey(fullShardId)] = work
+}
+
+func (b *fakeBackend) GetWork(fullShardId *uint32, addr *common.Address) (*consensus.MiningWork, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	work := b.works[chainKey(fullShardId)]
+	if work == nil {
+		return nil, consensus.ErrNoMiningWork
+	}
+	return work, nil
+}
+
+func (b *fakeBackend) SubmitWork(fullShardId *uint32, headerHash common.Hash, nonce uint64, mixHash common.Hash, signature *[65]byte) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.submissions = append(b.submissions, submission{fullShardId, headerHash, nonce, mixHash, nil})
+	return nonce == 7, nil
+}
+
+// SubmitShare accepts nonces below 1000 as shares, 7 sealing the block.
+func (b *fakeBackend) SubmitShare(fullShardId *uint32, headerHash common.Hash, nonce uint64, mixHash common.Hash, shareDiff *big.Int) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.submissions = append(b.submissions, submission{fullShardId, headerHash, nonce, mixHash, shareDiff})
+	if nonce >= 1000 {
+		return false, errors.New("invalid share")
+	}
+	return nonce == 7, nil
+}
+
+type testMiner struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	id   int
+}
+
+func dialMiner(t *testing.T, s *Server) *testMiner {
+	conn, err := net.Dial("tcp", s.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testMiner{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// read returns the next message pushed to or answering the miner.
+func (m *testMiner) read() map[string]interface{} {
+	m.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := m.r.ReadBytes('\n')
+	if err != nil {
+		m.t.Fatalf("failed to read: %v", err)
+	}
+	msg := make(map[string]interface{})
+	if err := json.Unmarshal(line, &msg); err != nil {
+		m.t.Fatalf("failed to decode %s: %v", line, err)
+	}
+	return msg
+}
+
+func (m *testMiner) call(method string, params ...interface{}) map[string]interface{} {
+	m.id++
+	data, _ := json.Marshal(map[string]interface{}{"id": m.id, "method": method, "params": params})
+	if _, err := m.conn.Write(append(data, '\n')); err != nil {
+		m.t.Fatal(err)
+	}
+	msg := m.read()
+	if msg["id"] != float64(m.id) {
+		m.t.Fatalf("%s: unexpected message %v", method, msg)
+	}
+	return msg
+}
+
+func (m *testMiner) expectNotification(method string) []interface{} {
+	msg := m.read()
+	if msg["id"] != nil || msg["method"] != method {
+		m.t.Fatalf("expected %s, got %v", method, msg)
+	}
+	return msg["params"].([]interface{})
+}
+
+func TestStratumMining(t *testing.T) {
+	var (
+		fullShardId = uint32(0x10001)
+		coinbase    = common.HexToAddress("0x0000000000000000000000000000000000001234")
+		work1       = &consensus.MiningWork{HeaderHash: common.HexToHash("0x01"), Number: 10, Difficulty: big.NewInt(1000)}
+		work2       = &consensus.MiningWork{HeaderHash: common.HexToHash("0x02"), Number: 11, Difficulty: big.NewInt(1000)}
+		rootWork    = &consensus.MiningWork{HeaderHash: common.HexToHash("0x03"), Number: 5, Difficulty: big.NewInt(5000)}
+	)
+	backend := &fakeBackend{works: make(map[uint64]*consensus.MiningWork)}
+	backend.setWork(&fullShardId, work1)
+	backend.setWork(nil, rootWork)
+
+	s := New(backend, config.NewQuarkChainConfig(), "127.0.0.1:0")
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	miner := dialMiner(t, s)
+	defer miner.conn.Close()
+	if msg := miner.call("mining.authorize", coinbase.Hex()+".rig1", "x"); msg["error"] == nil {
+		t.Fatalf("authorized before subscribing: %v", msg)
+	}
+	if msg := miner.call("mining.subscribe", "test/1.0", "0x10001"); msg["error"] != nil {
+		t.Fatalf("subscribe failed: %v", msg)
+	}
+	if msg := miner.call("mining.authorize", coinbase.Hex()+".rig1", "x"); msg["result"] != true {
+		t.Fatalf("authorize failed: %v", msg)
+	}
+	if params := miner.expectNotification("mining.set_difficulty"); params[0] != "0x3e8" {
+		t.Fatalf("unexpected difficulty %v", params)
+	}
+	if params := miner.expectNotification("mining.notify"); params[0] != work1.HeaderHash.Hex() || params[1] != "0xa" {
+		t.Fatalf("unexpected job %v", params)
+	}
+
+	// the tip of the shard changes, new work is pushed without the difficulty
+	backend.setWork(&fullShardId, work2)
+	s.NotifyNewTip(&fullShardId)
+	if params := miner.expectNotification("mining.notify"); params[0] != work2.HeaderHash.Hex() || params[1] != "0xb" {
+		t.Fatalf("unexpected job %v", params)
+	}
+
+	if msg := miner.call("mining.submit", "rig1", common.HexToHash("0x09").Hex(), "0x7", common.Hash{}.Hex()); msg["error"] == nil {
+		t.Fatalf("unknown job accepted: %v", msg)
+	}
+	if msg := miner.call("mining.submit", "rig1", work2.HeaderHash.Hex(), "0x8", common.Hash{}.Hex()); msg["error"] == nil {
+		t.Fatalf("invalid solution accepted: %v", msg)
+	}
+	if msg := miner.call("mining.submit", "rig1", work2.HeaderHash.Hex(), "0000000000000007", common.HexToHash("0xaa").Hex()); msg["result"] != true {
+		t.Fatalf("solution rejected: %v", msg)
+	}
+	backend.mu.Lock()
+	last := backend.submissions[len(backend.submissions)-1]
+	backend.mu.Unlock()
+	if *last.fullShardId != fullShardId || last.headerHash != work2.HeaderHash || last.nonce != 7 || last.mixHash != common.HexToHash("0xaa") {
+		t.Fatalf("unexpected submission %+v", last)
+	}
+
+	// a miner of the root chain gets its own difficulty and is not pushed
+	// the work of the shard
+	rootMiner := dialMiner(t, s)
+	defer rootMiner.conn.Close()
+	rootMiner.call("mining.subscribe", "test/1.0")
+	rootMiner.call("mining.authorize", coinbase.Hex(), "x")
+	if params := rootMiner.expectNotification("mining.set_difficulty"); params[0] != "0x1388" {
+		t.Fatalf("unexpected difficulty %v", params)
+	}
+	if params := rootMiner.expectNotification("mining.notify"); params[0] != rootWork.HeaderHash.Hex() {
+		t.Fatalf("unexpected job %v", params)
+	}
+	backend.setWork(&fullShardId, work1)
+	s.NotifyNewTip(&fullShardId)
+	if params := miner.expectNotification("mining.notify"); params[0] != work1.HeaderHash.Hex() {
+		t.Fatalf("unexpected job %v", params)
+	}
+	rootMiner.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if line, err := rootMiner.r.ReadBytes('\n'); err == nil {
+		t.Fatalf("root miner pushed shard work %s", line)
+	}
+}
+
+func TestStratumShares(t *testing.T) {
+	var (
+		fullShardId = uint32(0x10002)
+		coinbase    = common.HexToAddress("0x0000000000000000000000000000000000001234")
+		work        = &consensus.MiningWork{HeaderHash: common.HexToHash("0x01"), Number: 10, Difficulty: big.NewInt(1000)}
+		mixHash     = common.Hash{}.Hex()
+	)
+	qkcCfg := config.NewQuarkChainConfig()
+	qkcCfg.GetShardConfigByFullShardID(fullShardId).ConsensusConfig.ShareDifficulty = 10
+	backend := &fakeBackend{works: make(map[uint64]*consensus.MiningWork)}
+	backend.setWork(&fullShardId, work)
+
+	s := New(backend, qkcCfg, "127.0.0.1:0")
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	miner := dialMiner(t, s)
+	defer miner.conn.Close()
+	miner.call("mining.subscribe", "test/1.0", "0x10002")
+	miner.call("mining.authorize", coinbase.Hex()+".rig1", "x")
+	if params := miner.expectNotification("mining.set_difficulty"); params[0] != "0xa" {
+		t.Fatalf("unexpected difficulty %v", params)
+	}
+	miner.expectNotification("mining.notify")
+
+	// shares below the difficulty of the block, one sealing it
+	for nonce := uint64(1); nonce < uint64(retargetShares); nonce++ {
+		if msg := miner.call("mining.submit", "rig1", work.HeaderHash.Hex(), hexutil.EncodeUint64(nonce), mixHash); msg["result"] != true {
+			t.Fatalf("share rejected: %v", msg)
+		}
+	}
+	if msg := miner.call("mining.submit", "rig1", work.HeaderHash.Hex(), "0x1", mixHash); msg["error"] == nil {
+		t.Fatalf("duplicate share accepted: %v", msg)
+	}
+	if msg := miner.call("mining.submit", "rig1", work.HeaderHash.Hex(), "0x3e8", mixHash); msg["error"] == nil {
+		t.Fatalf("invalid share accepted: %v", msg)
+	}
+	if msg := miner.call("mining.submit", "rig1", common.HexToHash("0x09").Hex(), "0x3e9", mixHash); msg["error"] == nil {
+		t.Fatalf("stale share accepted: %v", msg)
+	}
+	backend.mu.Lock()
+	last := backend.submissions[len(backend.submissions)-1]
+	backend.mu.Unlock()
+	if last.shareDiff == nil || last.shareDiff.Uint64() != 10 {
+		t.Fatalf("unexpected share difficulty %v", last.shareDiff)
+	}
+
+	// submitting shares far too fast retargets the difficulty 4 times higher
+	// and notifies the job again
+	if msg := miner.call("mining.submit", "rig1", work.HeaderHash.Hex(), "0x100", mixHash); msg["result"] != true {
+		t.Fatalf("share rejected: %v", msg)
+	}
+	if params := miner.expectNotification("mining.set_difficulty"); params[0] != "0x28" {
+		t.Fatalf("unexpected difficulty %v", params)
+	}
+	if params := miner.expectNotification("mining.notify"); params[0] != work.HeaderHash.Hex() || params[2] != false {
+		t.Fatalf("unexpected job %v", params)
+	}
+	if msg := miner.call("mining.submit", "rig1", work.HeaderHash.Hex(), "0x101", mixHash); msg["result"] != true {
+		t.Fatalf("share rejected: %v", msg)
+	}
+
+	// the difficulty of a miner submitting no shares is lowered to the minimum
+	s.mu.Lock()
+	for sess := range s.sessions {
+		sess.mu.Lock()
+		sess.windowShares = 0
+		sess.mu.Unlock()
+		if err := sess.retarget(time.Now().Add(retargetInterval)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.mu.Unlock()
+	if params := miner.expectNotification("mining.set_difficulty"); params[0] != "0xa" {
+		t.Fatalf("unexpected difficulty %v", params)
+	}
+	miner.expectNotification("mining.notify")
+
+	stats := NewPrivateStratumAPI(s).GetWorkerStats(&coinbase)
+	if len(stats) != 1 {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+	want := WorkerStats{
+		Coinbase:       coinbase,
+		Worker:         "rig1",
+		AcceptedShares: hexutil.Uint64(retargetShares + 1),
+		InvalidShares:  2,
+		StaleShares:    1,
+		Blocks:         1,
+	}
+	got := stats[0]
+	if got.Coinbase != want.Coinbase || got.Worker != want.Worker || got.AcceptedShares != want.AcceptedShares ||
+		got.InvalidShares != want.InvalidShares || got.StaleShares != want.StaleShares || got.Blocks != want.Blocks {
+		t.Fatalf("unexpected stats %+v", got)
+	}
+	if uint32(*got.FullShardId) != fullShardId || got.Difficulty.ToInt().Uint64() != 10 {
+		t.Fatalf("unexpected stats %+v", got)
+	}
+	// the shares before the retarget are counted at their difficulty
+	if got.AcceptedDifficulty.ToInt().Uint64() != uint64(retargetShares*10+40) {
+		t.Fatalf("unexpected accepted difficulty %v", got.AcceptedDifficulty)
+	}
+	if len(NewPrivateStratumAPI(s).GetWorkerStats(nil)) != 1 {
+		t.Fatal("unexpected stats of all workers")
+	}
+}
+
+func TestStratumWorkerStatsLimits(t *testing.T) {
+	var (
+		coinbase = common.HexToAddress("0x0000000000000000000000000000000000001234")
+		work     = &consensus.MiningWork{HeaderHash: common.HexToHash("0x01"), Number: 10, Difficulty: big.NewInt(1000)}
+	)
+	backend := &fakeBackend{works: make(map[uint64]*consensus.MiningWork)}
+	backend.setWork(nil, work)
+	s := New(backend, config.NewQuarkChainConfig(), "127.0.0.1:0")
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	miner := dialMiner(t, s)
+	miner.call("mining.subscribe", "test/1.0")
+	long := make([]byte, maxWorkerNameLen+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if msg := miner.call("mining.authorize", coinbase.Hex()+"."+string(long), "x"); msg["error"] == nil {
+		t.Fatalf("long worker name authorized: %v", msg)
+	}
+
+	// the workers of a coinbase are capped, while the ones known reconnect
+	for i := 0; i < maxCoinbaseWorkers-1; i++ {
+		stats, err := s.workerStats(nil, coinbase, hexutil.EncodeUint64(uint64(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats.release()
+	}
+	if msg := miner.call("mining.authorize", coinbase.Hex()+".rig1", "x"); msg["result"] != true {
+		t.Fatalf("worker not authorized: %v", msg)
+	}
+	if _, err := s.workerStats(nil, coinbase, "rig2"); err != errTooManyWorkers {
+		t.Fatalf("expected %v, got %v", errTooManyWorkers, err)
+	}
+	if _, err := s.workerStats(nil, coinbase, "0x0"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the stats of a connected worker are kept, and the ones of the idle
+	// workers dropped after the retention period
+	s.pruneStats(time.Now().Add(statsRetention))
+	if stats := s.WorkerStats(&coinbase); len(stats) != 2 || stats[0].Worker != "0x0" || stats[1].Worker != "rig1" {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+	miner.conn.Close()
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		s.mu.Lock()
+		connected := len(s.sessions)
+		s.mu.Unlock()
+		if connected == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("session not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.pruneStats(time.Now())
+	if len(s.WorkerStats(&coinbase)) != 2 {
+		t.Fatal("stats dropped before the retention period")
+	}
+	s.pruneStats(time.Now().Add(statsRetention))
+	if stats := s.WorkerStats(&coinbase); len(stats) != 1 || stats[0].Worker != "0x0" {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+	if _, err := s.workerStats(nil, coinbase, "rig2"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,479 @@
+package stratum
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxJobs is how many of the latest jobs pushed to a miner it can submit
+	// solutions for.
+	maxJobs = 4
+	// maxLineSize is the maximum size of a message of a miner.
+	maxLineSize  = 16 * 1024
+	writeTimeout = 10 * time.Second
+
+	// shareTargetTime is how often the difficulty of the shares of a miner is
+	// retargeted for it to submit a share.
+	shareTargetTime = 10 * time.Second
+	// retargetInterval is how long the shares of a miner are counted before its
+	// difficulty is retargeted, and retargetShares how many shares retarget it
+	// earlier when the difficulty is far too low.
+	retargetInterval = 60 * time.Second
+	retargetShares   = 4 * int(retargetInterval/shareTargetTime)
+)
+
+var errInvalidSolution = errors.New("invalid or stale solution")
+
+// Stratum error codes.
+const (
+	errCodeOther        = 20
+	errCodeJobNotFound  = 21
+	errCodeDuplicate    = 22
+	errCodeInvalidShare = 23
+	errCodeUnauthorized = 24
+	errCodeUnsubscribed = 25
+)
+
+// request is a call of a miner. Calls of the server to a miner have no id.
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  []interface{}   `json:"error"`
+}
+
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// session is the connection of a miner, mining one chain for one coinbase.
+type session struct {
+	server *Server
+	conn   net.Conn
+	id     string
+
+	writeMu sync.Mutex
+	enc     *json.Encoder
+
+	// pushMu keeps the work and the difficulty pushed together in order
+	pushMu sync.Mutex
+
+	mu          sync.Mutex
+	subscribed  bool
+	fullShardId *uint32
+	coinbase    *common.Address
+	worker      string
+	stats       *workerStats
+	work        *consensus.MiningWork // latest work pushed
+	difficulty  *big.Int              // latest difficulty pushed
+	jobs        []*job                // latest last
+
+	// minShareDiff is the lowest difficulty of the shares, nil if only block
+	// solutions are accepted, and shareDiff the difficulty retargeted for the
+	// miner from the shares it submitted since windowStart.
+	minShareDiff *big.Int
+	shareDiff    *big.Int
+	windowStart  time.Time
+	windowShares int
+}
+
+// job is the work of a header hash at the difficulty the miner was notified.
+// The same header is notified again when the difficulty is retargeted.
+type job struct {
+	hash       common.Hash
+	difficulty *big.Int
+	workDiff   *big.Int
+	nonces     map[uint64]struct{} // nonces submitted
+}
+
+func newSession(server *Server, conn net.Conn, id uint64) *session {
+	return &session{
+		server: server,
+		conn:   conn,
+		id:     fmt.Sprintf("%016x", id),
+		enc:    json.NewEncoder(conn),
+	}
+}
+
+// serve handles the calls of the miner until the connection is closed.
+func (s *session) serve() {
+	defer s.conn.Close()
+	defer s.releaseStats()
+	log.Debug("Stratum miner connected", "session", s.id, "addr", s.conn.RemoteAddr())
+
+	scanner := bufio.NewScanner(s.conn)
+	scanner.Buffer(make([]byte, 0, 1024), maxLineSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			log.Debug("Stratum malformed message", "session", s.id, "err", err)
+			return
+		}
+		if err := s.handle(&req); err != nil {
+			log.Debug("Stratum write error", "session", s.id, "err", err)
+			return
+		}
+	}
+	log.Debug("Stratum miner disconnected", "session", s.id, "err", scanner.Err())
+}
+
+// releaseStats releases the statistics of the worker once the connection closes.
+func (s *session) releaseStats() {
+	s.mu.Lock()
+	stats := s.stats
+	s.mu.Unlock()
+	if stats != nil {
+		stats.release()
+	}
+}
+
+func (s *session) handle(req *request) error {
+	switch req.Method {
+	case "mining.subscribe":
+		return s.handleSubscribe(req)
+	case "mining.authorize":
+		return s.handleAuthorize(req)
+	case "mining.submit":
+		return s.handleSubmit(req)
+	}
+	return s.replyError(req, errCodeOther, "unknown method "+req.Method)
+}
+
+// handleSubscribe subscribes the miner to the root chain, or to the shard of
+// the full shard id in the second param.
+//
+//	{"id":1,"method":"mining.subscribe","params":["miner/1.0","0x10001"]}
+func (s *session) handleSubscribe(req *request) error {
+	var fullShardId *uint32
+	if len(req.Params) > 1 && string(req.Params[1]) != "null" {
+		var id hexutil.Uint
+		if err := json.Unmarshal(req.Params[1], &id); err != nil {
+			return s.replyError(req, errCodeOther, "invalid full shard id: "+err.Error())
+		}
+		fullShardId = new(uint32)
+		*fullShardId = uint32(id)
+	}
+	s.mu.Lock()
+	if s.subscribed {
+		s.mu.Unlock()
+		return s.replyError(req, errCodeOther, "already subscribed")
+	}
+	s.subscribed = true
+	s.fullShardId = fullShardId
+	s.mu.Unlock()
+	return s.reply(req, s.id)
+}
+
+// handleAuthorize sets the coinbase the miner mines for, followed by the name
+// of the worker in the first param, and pushes its work.
+//
+//	{"id":2,"method":"mining.authorize","params":["0x<coinbase>.rig1","x"]}
+func (s *session) handleAuthorize(req *request) error {
+	s.mu.Lock()
+	subscribed, fullShardId := s.subscribed, s.fullShardId
+	s.mu.Unlock()
+	if !subscribed {
+		return s.replyError(req, errCodeUnsubscribed, "not subscribed")
+	}
+	var user string
+	if len(req.Params) > 0 {
+		json.Unmarshal(req.Params[0], &user)
+	}
+	addr, worker := user, ""
+	if i := strings.Index(user, "."); i >= 0 {
+		addr, worker = user[:i], user[i+1:]
+	}
+	if !common.IsHexAddress(addr) {
+		return s.replyError(req, errCodeUnauthorized, "invalid coinbase address: "+addr)
+	}
+	coinbase := common.HexToAddress(addr)
+	work, err := s.server.backend.GetWork(fullShardId, &coinbase)
+	if err != nil {
+		return s.replyError(req, errCodeOther, err.Error())
+	}
+	stats, err := s.server.workerStats(fullShardId, coinbase, worker)
+	if err != nil {
+		return s.replyError(req, errCodeUnauthorized, err.Error())
+	}
+
+	minShareDiff := s.server.shareDifficulty(fullShardId)
+	s.mu.Lock()
+	oldStats := s.stats
+	s.coinbase, s.worker = &coinbase, worker
+	s.stats = stats
+	s.work, s.difficulty, s.jobs = nil, nil, nil
+	s.minShareDiff, s.shareDiff = minShareDiff, minShareDiff
+	s.windowStart, s.windowShares = time.Now(), 0
+	s.mu.Unlock()
+	if oldStats != nil {
+		oldStats.release()
+	}
+	log.Info("Stratum miner authorized", "session", s.id, "coinbase", coinbase.Hex(), "worker", worker, "fullShardId", fullShardId)
+	if err := s.reply(req, true); err != nil {
+		return err
+	}
+	return s.pushWork(work)
+}
+
+// handleSubmit submits the nonce and the mix hash in the third and the fourth
+// params found for the job in the second param.
+//
+//	{"id":3,"method":"mining.submit","params":["rig1","0x<job>","0x<nonce>","0x<mixhash>"]}
+func (s *session) handleSubmit(req *request) error {
+	var params [4]string
+	if len(req.Params) != len(params) {
+		return s.replyError(req, errCodeOther, "invalid params")
+	}
+	for i, param := range req.Params {
+		if err := json.Unmarshal(param, &params[i]); err != nil {
+			return s.replyError(req, errCodeOther, "invalid params")
+		}
+	}
+	s.mu.Lock()
+	authorized, fullShardId, stats := s.coinbase != nil, s.fullShardId, s.stats
+	s.mu.Unlock()
+	if !authorized {
+		return s.replyError(req, errCodeUnauthorized, "unauthorized worker")
+	}
+
+	// miners send the nonce as 8 bytes, with leading zeros
+	nonce, err := strconv.ParseUint(strip0x(params[2]), 16, 64)
+	if err != nil {
+		return s.replyError(req, errCodeOther, "invalid nonce")
+	}
+	mixHash, err := hex.DecodeString(strip0x(params[3]))
+	if err != nil || len(mixHash) != common.HashLength {
+		return s.replyError(req, errCodeOther, "invalid mix hash")
+	}
+	hash := common.HexToHash(params[1])
+	jobs, duplicate := s.findJobs(hash, nonce)
+	if len(jobs) == 0 {
+		stats.record(shareStale, nil, false)
+		return s.replyError(req, errCodeJobNotFound, "job not found")
+	}
+	if duplicate {
+		stats.record(shareInvalid, nil, false)
+		return s.replyError(req, errCodeDuplicate, "duplicate share")
+	}
+
+	// a share found before the difficulty was retargeted is checked at the
+	// difficulty it was found at if it does not meet the current one
+	var (
+		j     *job
+		block bool
+	)
+	for _, j = range jobs {
+		if j.difficulty.Cmp(j.workDiff) >= 0 {
+			if block, err = s.server.backend.SubmitWork(fullShardId, hash, nonce, common.BytesToHash(mixHash), nil); err == nil && !block {
+				err = errInvalidSolution
+			}
+		} else {
+			block, err = s.server.backend.SubmitShare(fullShardId, hash, nonce, common.BytesToHash(mixHash), j.difficulty)
+		}
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		stats.record(shareInvalid, nil, false)
+		return s.replyError(req, errCodeInvalidShare, err.Error())
+	}
+	stats.record(shareAccepted, j.difficulty, block)
+	if block {
+		log.Info("Stratum solution accepted", "session", s.id, "worker", params[0], "job", hash.Hex())
+	}
+	if err := s.reply(req, true); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.windowShares++
+	s.mu.Unlock()
+	return s.retarget(time.Now())
+}
+
+// findJobs returns the jobs of the header hash the miner was notified, latest
+// first and each with a lower difficulty than the ones before, and whether the
+// nonce was already submitted for them.
+func (s *session) findJobs(hash common.Hash, nonce uint64) ([]*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var jobs []*job
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		j := s.jobs[i]
+		if j.hash != hash || (len(jobs) > 0 && j.difficulty.Cmp(jobs[len(jobs)-1].difficulty) >= 0) {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	if len(jobs) == 0 {
+		return nil, false
+	}
+	// the jobs of a header share the nonces submitted
+	if _, ok := jobs[0].nonces[nonce]; ok {
+		return jobs, true
+	}
+	jobs[0].nonces[nonce] = struct{}{}
+	return jobs, false
+}
+
+func strip0x(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s[2:]
+	}
+	return s
+}
+
+// miningOn returns the full shard id of the chain and the coinbase of an
+// authorized miner.
+func (s *session) miningOn() (*uint32, common.Address, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.coinbase == nil {
+		return nil, common.Address{}, false
+	}
+	return s.fullShardId, *s.coinbase, true
+}
+
+// pushWork sends the work to the miner as a new job unless it is its latest
+// one, preceded by its difficulty if that changed.
+//
+//	{"id":null,"method":"mining.set_difficulty","params":["0x<difficulty>"]}
+//	{"id":null,"method":"mining.notify","params":["0x<job>","0x<height>",true]}
+//
+// The job is the hash of the header to seal. The difficulty is the one of the
+// shares of the miner if lower than the one of the work.
+func (s *session) pushWork(work *consensus.MiningWork) error {
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+	s.mu.Lock()
+	if s.work != nil && s.work.HeaderHash == work.HeaderHash {
+		s.mu.Unlock()
+		return nil
+	}
+	s.work = work
+	s.mu.Unlock()
+	return s.notifyJob(true)
+}
+
+// retarget adjusts the difficulty of the shares of the miner for it to submit
+// one every shareTargetTime, and pushes it with the current job if it changed.
+// The difficulty is changed at most 4 times at once and kept between the share
+// difficulty configured for the chain and the difficulty of the work.
+func (s *session) retarget(now time.Time) error {
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+	s.mu.Lock()
+	elapsed := now.Sub(s.windowStart)
+	if s.minShareDiff == nil || s.work == nil || (elapsed < retargetInterval && s.windowShares < retargetShares) {
+		s.mu.Unlock()
+		return nil
+	}
+	// the difficulty is multiplied by num/den, the shares submitted over the
+	// shares expected in the window
+	num := new(big.Int).Mul(big.NewInt(int64(s.windowShares)), big.NewInt(int64(shareTargetTime/time.Millisecond)))
+	den := big.NewInt(int64(elapsed/time.Millisecond) + 1)
+	if new(big.Int).Mul(num, big.NewInt(4)).Cmp(den) < 0 {
+		num, den = big.NewInt(1), big.NewInt(4)
+	} else if num.Cmp(new(big.Int).Mul(den, big.NewInt(4))) > 0 {
+		num, den = big.NewInt(4), big.NewInt(1)
+	}
+	diff := new(big.Int).Mul(s.shareDiff, num)
+	diff.Div(diff, den)
+	if diff.Cmp(s.minShareDiff) < 0 {
+		diff.Set(s.minShareDiff)
+	}
+	if diff.Cmp(s.work.Difficulty) > 0 {
+		diff.Set(s.work.Difficulty)
+	}
+	s.windowStart, s.windowShares = now, 0
+	changed := diff.Cmp(s.shareDiff) != 0
+	s.shareDiff = diff
+	s.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+	log.Debug("Stratum share difficulty retargeted", "session", s.id, "difficulty", diff)
+	return s.notifyJob(false)
+}
+
+// notifyJob notifies the latest work at the current difficulty, which the miner
+// should switch to at once if clean. It is called with pushMu held.
+func (s *session) notifyJob(clean bool) error {
+	s.mu.Lock()
+	work, diff := s.work, s.work.Difficulty
+	if s.shareDiff != nil && s.shareDiff.Cmp(diff) < 0 {
+		diff = s.shareDiff
+	}
+	diffChanged := s.difficulty == nil || s.difficulty.Cmp(diff) != 0
+	if !diffChanged && !clean {
+		s.mu.Unlock()
+		return nil
+	}
+	s.difficulty = diff
+	j := &job{hash: work.HeaderHash, difficulty: diff, workDiff: work.Difficulty, nonces: make(map[uint64]struct{})}
+	// a job notified again shares the nonces submitted for it
+	for _, old := range s.jobs {
+		if old.hash == j.hash {
+			j.nonces = old.nonces
+		}
+	}
+	if s.jobs = append(s.jobs, j); len(s.jobs) > maxJobs {
+		s.jobs = s.jobs[len(s.jobs)-maxJobs:]
+	}
+	stats := s.stats
+	s.mu.Unlock()
+	stats.setDifficulty(diff)
+
+	if diffChanged {
+		if err := s.notify("mining.set_difficulty", (*hexutil.Big)(diff)); err != nil {
+			return err
+		}
+	}
+	return s.notify("mining.notify", work.HeaderHash, hexutil.Uint64(work.Number), clean)
+}
+
+func (s *session) reply(req *request, result interface{}) error {
+	return s.write(&response{ID: req.ID, Result: result})
+}
+
+func (s *session) replyError(req *request, code int, message string) error {
+	return s.write(&response{ID: req.ID, Result: nil, Error: []interface{}{code, message, nil}})
+}
+
+func (s *session) notify(method string, params ...interface{}) error {
+	return s.write(&notification{Method: method, Params: params})
+}
+
+func (s *session) write(msg interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.enc.Encode(msg)
+}
@@ -0,0 +1,220 @@
+package stratum
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// maxWorkerNameLen is the longest name of a worker accepted.
+	maxWorkerNameLen = 64
+	// maxCoinbaseWorkers is how many workers of a coinbase the statistics are
+	// kept of.
+	maxCoinbaseWorkers = 1024
+	// statsRetention is how long the statistics of a worker are kept once it
+	// has no connection and submits no share, checked every statsPruneInterval.
+	statsRetention     = 24 * time.Hour
+	statsPruneInterval = time.Hour
+)
+
+var (
+	errWorkerNameTooLong = errors.New("worker name too long")
+	errTooManyWorkers    = errors.New("too many workers of the coinbase")
+)
+
+type shareResult int
+
+const (
+	shareAccepted shareResult = iota
+	shareInvalid
+	shareStale
+)
+
+// workerKey identifies a worker of a coinbase mining a chain, the connections
+// of which share their statistics.
+type workerKey struct {
+	chain    uint64
+	coinbase common.Address
+	worker   string
+}
+
+// workerStats counts the shares of a worker since its first connection, until
+// it is idle for statsRetention.
+type workerStats struct {
+	mu          sync.Mutex
+	fullShardId *uint32
+	accepted    uint64
+	invalid     uint64
+	stale       uint64
+	blocks      uint64
+	acceptedSum *big.Int // difficulty of the accepted shares
+	difficulty  *big.Int // latest difficulty pushed
+	lastShare   time.Time
+	sessions    int       // connections of the worker
+	lastActive  time.Time // latest share or disconnection
+}
+
+// WorkerStats is the share statistics of a worker returned over RPC. The root
+// chain is mined if FullShardId is nil.
+type WorkerStats struct {
+	FullShardId        *hexutil.Uint  `json:"fullShardId"`
+	Coinbase           common.Address `json:"coinbase"`
+	Worker             string         `json:"worker"`
+	Difficulty         *hexutil.Big   `json:"difficulty"`
+	AcceptedShares     hexutil.Uint64 `json:"acceptedShares"`
+	InvalidShares      hexutil.Uint64 `json:"invalidShares"`
+	StaleShares        hexutil.Uint64 `json:"staleShares"`
+	AcceptedDifficulty *hexutil.Big   `json:"acceptedDifficulty"`
+	Blocks             hexutil.Uint64 `json:"blocks"`
+	LastShareTime      hexutil.Uint64 `json:"lastShareTime"`
+}
+
+func (w *workerStats) record(result shareResult, diff *big.Int, block bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastActive = time.Now()
+	switch result {
+	case shareAccepted:
+		w.accepted++
+		w.acceptedSum.Add(w.acceptedSum, diff)
+		w.lastShare = time.Now()
+	case shareInvalid:
+		w.invalid++
+	case shareStale:
+		w.stale++
+	}
+	if block {
+		w.blocks++
+	}
+}
+
+func (w *workerStats) setDifficulty(diff *big.Int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.difficulty = diff
+}
+
+// release is called when a connection of the worker closes or authorizes
+// another worker.
+func (w *workerStats) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sessions--
+	w.lastActive = time.Now()
+}
+
+// workerStats returns the statistics of the worker for a connection of it,
+// created on its first connection, which is released with release.
+func (s *Server) workerStats(fullShardId *uint32, coinbase common.Address, worker string) (*workerStats, error) {
+	if len(worker) > maxWorkerNameLen {
+		return nil, errWorkerNameTooLong
+	}
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	key := workerKey{chainKey(fullShardId), coinbase, worker}
+	stats, ok := s.stats[key]
+	if !ok {
+		if s.workers[coinbase] >= maxCoinbaseWorkers {
+			return nil, errTooManyWorkers
+		}
+		stats = &workerStats{fullShardId: fullShardId, acceptedSum: new(big.Int)}
+		s.stats[key] = stats
+		s.workers[coinbase]++
+	}
+	stats.mu.Lock()
+	stats.sessions++
+	stats.mu.Unlock()
+	return stats, nil
+}
+
+// pruneStats drops the statistics of the workers with no connection and no
+// share for statsRetention.
+func (s *Server) pruneStats(now time.Time) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	for key, stats := range s.stats {
+		stats.mu.Lock()
+		idle := stats.sessions == 0 && now.Sub(stats.lastActive) >= statsRetention
+		stats.mu.Unlock()
+		if !idle {
+			continue
+		}
+		delete(s.stats, key)
+		if s.workers[key.coinbase]--; s.workers[key.coinbase] == 0 {
+			delete(s.workers, key.coinbase)
+		}
+	}
+}
+
+// WorkerStats returns the statistics of the workers of the coinbase, or of all
+// workers if coinbase is nil, ordered by chain, coinbase and worker name.
+func (s *Server) WorkerStats(coinbase *common.Address) []*WorkerStats {
+	s.statsMu.Lock()
+	keys := make([]workerKey, 0, len(s.stats))
+	for key := range s.stats {
+		if coinbase == nil || key.coinbase == *coinbase {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].chain != keys[j].chain {
+			return keys[i].chain < keys[j].chain
+		}
+		if keys[i].coinbase != keys[j].coinbase {
+			return keys[i].coinbase.Hex() < keys[j].coinbase.Hex()
+		}
+		return keys[i].worker < keys[j].worker
+	})
+	all := make([]*workerStats, len(keys))
+	for i, key := range keys {
+		all[i] = s.stats[key]
+	}
+	s.statsMu.Unlock()
+
+	result := make([]*WorkerStats, len(keys))
+	for i, w := range all {
+		w.mu.Lock()
+		result[i] = &WorkerStats{
+			Coinbase:           keys[i].coinbase,
+			Worker:             keys[i].worker,
+			Difficulty:         (*hexutil.Big)(w.difficulty),
+			AcceptedShares:     hexutil.Uint64(w.accepted),
+			InvalidShares:      hexutil.Uint64(w.invalid),
+			StaleShares:        hexutil.Uint64(w.stale),
+			AcceptedDifficulty: (*hexutil.Big)(new(big.Int).Set(w.acceptedSum)),
+			Blocks:             hexutil.Uint64(w.blocks),
+		}
+		if w.fullShardId != nil {
+			id := hexutil.Uint(*w.fullShardId)
+			result[i].FullShardId = &id
+		}
+		if !w.lastShare.IsZero() {
+			result[i].LastShareTime = hexutil.Uint64(w.lastShare.Unix())
+		}
+		w.mu.Unlock()
+	}
+	return result
+}
+
+// PrivateStratumAPI provides the share statistics of the Stratum workers on the
+// private endpoint, for a pool to pay its miners.
+type PrivateStratumAPI struct {
+	s *Server
+}
+
+// NewPrivateStratumAPI creates the API of the Stratum server.
+func NewPrivateStratumAPI(s *Server) *PrivateStratumAPI {
+	return &PrivateStratumAPI{s}
+}
+
+// GetWorkerStats returns the share statistics of the workers of the coinbase,
+// or of all workers if it is omitted.
+func (api *PrivateStratumAPI) GetWorkerStats(coinbase *common.Address) []*WorkerStats {
+	return api.s.WorkerStats(coinbase)
+}
@@ -0,0 +1,77 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// StateSyncPivotDistance is how many root blocks behind the tip of the
+	// peer the pivot of state sync is. Minor blocks confirmed by root blocks
+	// before it are stored without being executed.
+	StateSyncPivotDistance = 64
+	TrieNodeBatchSize      = 384
+	CrossShardTxListLimit  = 500
+)
+
+type minorStateSyncerPeer interface {
+	GetTrieNodes(hashes []common.Hash, branch uint32) ([][]byte, error)
+	PeerID() string
+}
+
+// SyncMinorState downloads the state trie of a shard with the given root from
+// the peer into db, and returns once the state is complete.
+func SyncMinorState(p minorStateSyncerPeer, branch uint32, root common.Hash, db ethdb.Database, stats *BlockSychronizerStats) error {
+	var (
+		sched  = state.NewStateSync(root, db)
+		logger = log.New("statesync", fmt.Sprintf("shard-%d", branch), "root", root.String())
+		nodes  uint64
+	)
+	logger.Info("Downloading state", "peer", p.PeerID())
+	for sched.Pending() > 0 {
+		hashes := sched.Missing(TrieNodeBatchSize)
+		data, err := p.GetTrieNodes(hashes, branch)
+		if err != nil {
+			return err
+		}
+
+		processed := 0
+		for _, blob := range data {
+			// Nodes shared by several tries are only requested once.
+			_, _, err := sched.Process([]trie.SyncResult{{Hash: crypto.Keccak256Hash(blob), Data: blob}})
+			if err == trie.ErrNotRequested || err == trie.ErrAlreadyProcessed {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			processed++
+		}
+		if processed == 0 {
+			return fmt.Errorf("peer %s returned none of %d state entries requested", p.PeerID(), len(hashes))
+		}
+
+		batch := db.NewBatch()
+		if _, err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		nodes += uint64(processed)
+		if stats != nil {
+			stats.StatesDownloaded += uint64(processed)
+		}
+		if nodes/10000 != (nodes-uint64(processed))/10000 {
+			logger.Info("Downloading state", "entries", nodes, "pending", sched.Pending())
+		}
+	}
+	logger.Info("Downloaded state", "entries", nodes)
+	return nil
+}
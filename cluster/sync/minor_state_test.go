@@ -0,0 +1,77 @@
+package sync
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/stretchr/testify/assert"
+)
+
+type stateSyncPeer struct {
+	db    state.Database
+	empty bool
+}
+
+func (p *stateSyncPeer) GetTrieNodes(hashes []common.Hash, branch uint32) ([][]byte, error) {
+	data := make([][]byte, 0, len(hashes))
+	if p.empty {
+		return data, nil
+	}
+	for _, hash := range hashes {
+		if blob, err := p.db.TrieDB().Node(hash); err == nil {
+			data = append(data, blob)
+		}
+	}
+	return data, nil
+}
+
+func (p *stateSyncPeer) PeerID() string {
+	return "state"
+}
+
+func makeTestState(t *testing.T) (state.Database, common.Hash) {
+	db := state.NewDatabase(ethdb.NewMemDatabase())
+	statedb, err := state.New(common.Hash{}, db)
+	assert.NoError(t, err)
+	for i := byte(0); i < 100; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		statedb.AddBalance(addr, big.NewInt(int64(i)+1), 0)
+		statedb.SetNonce(addr, uint64(i))
+		if i%10 == 0 {
+			statedb.SetCode(addr, []byte{i, i})
+			statedb.SetState(addr, common.BytesToHash([]byte{i}), common.BytesToHash([]byte{i + 1}))
+		}
+	}
+	root, err := statedb.Commit(false)
+	assert.NoError(t, err)
+	assert.NoError(t, db.TrieDB().Commit(root, false))
+	return db, root
+}
+
+func TestSyncMinorState(t *testing.T) {
+	srcDb, root := makeTestState(t)
+	dstDb := ethdb.NewMemDatabase()
+	stats := new(BlockSychronizerStats)
+	assert.NoError(t, SyncMinorState(&stateSyncPeer{db: srcDb}, 1, root, dstDb, stats))
+	assert.NotZero(t, stats.StatesDownloaded)
+
+	statedb, err := state.New(root, state.NewDatabase(dstDb))
+	assert.NoError(t, err)
+	for i := byte(0); i < 100; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		assert.Equal(t, uint64(i), statedb.GetNonce(addr))
+		if i%10 == 0 {
+			assert.Equal(t, []byte{i, i}, statedb.GetCode(addr))
+			assert.Equal(t, common.BytesToHash([]byte{i + 1}), statedb.GetState(addr, common.BytesToHash([]byte{i})))
+		}
+	}
+}
+
+func TestSyncMinorStateEmptyResponse(t *testing.T) {
+	srcDb, root := makeTestState(t)
+	err := SyncMinorState(&stateSyncPeer{db: srcDb, empty: true}, 1, root, ethdb.NewMemDatabase(), nil)
+	assert.Error(t, err)
+}
@@ -36,8 +36,10 @@ func NewMinorChainTask(
 		stats:  &BlockSychronizerStats{},
 		peer:   p,
 	}
+	name := fmt.Sprintf("chain-%d/shard-%d", header.Branch.GetChainID(), header.Branch.GetShardID())
 	mTask.task = task{
-		name:             fmt.Sprintf("shard-%d", header.Branch.GetShardID()),
+		name:             name,
+		gauges:           getShardSyncGauges(header.Branch.Value, name),
 		header:           header,
 		maxSyncStaleness: 22500 * 6, // TODO: derive from root chain?
 		batchSize:        MinorBlockHeaderListLimit,
@@ -28,6 +28,7 @@ type rootChainTask struct {
 	stats      *BlockSychronizerStats
 	statusChan chan *rpc.ShardStatus
 	slaveConns rpc.ConnManager
+	stateSync  bool
 }
 
 // NewRootChainTask returns a sync task for root chain.
@@ -47,6 +48,7 @@ func NewRootChainTask(
 	}
 	rTask.task = task{
 		name:             "root",
+		gauges:           rootSyncGauges,
 		header:           header,
 		maxSyncStaleness: 22500,
 		batchSize:        RootBlockBatchSize,
@@ -128,6 +130,23 @@ func NewRootChainTask(
 	return rTask
 }
 
+// NewRootChainStateTask returns a sync task for root chain which has slaves
+// store the minor blocks confirmed by root blocks more than
+// StateSyncPivotDistance blocks behind the tip of the peer without executing
+// them. A shard downloads the state at its pivot from the peer once the first
+// block after the pivot is to be executed.
+func NewRootChainStateTask(
+	p rootSyncerPeer,
+	header *types.RootBlockHeader,
+	stats *BlockSychronizerStats,
+	statusChan chan *rpc.ShardStatus,
+	slaveConns rpc.ConnManager,
+) Task {
+	rTask := NewRootChainTask(p, header, stats, statusChan, slaveConns).(*rootChainTask)
+	rTask.stateSync = true
+	return rTask
+}
+
 func (r *rootChainTask) Priority() *big.Int {
 	return r.header.GetTotalDifficulty()
 }
@@ -240,6 +259,7 @@ func (r *rootChainTask) syncMinorBlocks(
 		downloadMap[header.Branch.Value] = append(downloadMap[header.Branch.Value], hash)
 	}
 
+	withoutState := r.stateSync && rootBlock.Number()+StateSyncPivotDistance <= r.header.Number
 	var g errgroup.Group
 	for branch, hashes := range downloadMap {
 		b, hashList := branch, hashes
@@ -249,7 +269,7 @@ func (r *rootChainTask) syncMinorBlocks(
 		}
 		// TODO Support to multiple connections
 		g.Go(func() error {
-			status, err := conns[0].AddBlockListForSync(&rpc.AddBlockListForSyncRequest{Branch: b, PeerId: r.PeerID(), MinorBlockHashList: hashList})
+			status, err := conns[0].AddBlockListForSync(&rpc.AddBlockListForSyncRequest{Branch: b, PeerId: r.PeerID(), MinorBlockHashList: hashList, WithoutState: withoutState})
 			if err == nil {
 				r.statusChan <- status
 			}
@@ -6,6 +6,7 @@ type BlockSychronizerStats struct {
 	BlocksAdded            uint64 `json:"blocks_added" gencodec:"required"`
 	AncestorNotFoundCount  uint64 `json:"ancestor_not_found_count" gencodec:"required"`
 	AncestorLookupRequests uint64 `json:"ancestor_lookup_requests" gencodec:"required"`
+	StatesDownloaded       uint64 `json:"states_downloaded" gencodec:"required"`
 }
 
 type Progress struct {
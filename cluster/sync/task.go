@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 
 	qkcom "github.com/QuarkChain/goquarkchain/common"
 	"github.com/QuarkChain/goquarkchain/core/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 )
 
 const (
@@ -19,6 +21,34 @@ const (
 	MinorBlockBatchSize       = 50
 )
 
+var (
+	rootSyncGauges  = newSyncGauges("root")
+	shardSyncGauges sync.Map // branch value -> *syncGauges
+)
+
+// syncGauges report the progress of the syncs of a chain.
+type syncGauges struct {
+	current metrics.Gauge
+	highest metrics.Gauge
+}
+
+func newSyncGauges(name string) *syncGauges {
+	return &syncGauges{
+		current: metrics.NewRegisteredGauge("sync/"+name+"/current", nil),
+		highest: metrics.NewRegisteredGauge("sync/"+name+"/highest", nil),
+	}
+}
+
+// getShardSyncGauges returns the gauges of the syncs of the shard, which are only
+// registered by the first sync of the shard.
+func getShardSyncGauges(branch uint32, name string) *syncGauges {
+	if gauges, ok := shardSyncGauges.Load(branch); ok {
+		return gauges.(*syncGauges)
+	}
+	gauges, _ := shardSyncGauges.LoadOrStore(branch, newSyncGauges(name))
+	return gauges.(*syncGauges)
+}
+
 // Task represents a synchronization task for the synchronizer.
 type Task interface {
 	SetSendFunc(func(value interface{}) int)
@@ -29,6 +59,7 @@ type Task interface {
 
 type task struct {
 	name             string
+	gauges           *syncGauges
 	maxSyncStaleness uint64
 	batchSize        int
 
@@ -145,6 +176,8 @@ func (t *task) SetSendFunc(send func(value interface{}) (nsent int)) {
 }
 
 func (t *task) sendSync(syncing bool, curr, best uint64) {
+	t.gauges.current.Update(int64(curr))
+	t.gauges.highest.Update(int64(best))
 	if t.send != nil {
 		t.send(&SyncingResult{
 			Syncing: syncing,
@@ -0,0 +1,88 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const kafkaPostTimeout = 5 * time.Second
+
+// Sink writes batches of samples of a topic.
+type Sink interface {
+	Write(topic string, samples []Sample) error
+}
+
+// kafkaRESTSink posts samples to the topics of a Kafka REST proxy.
+type kafkaRESTSink struct {
+	address string
+	client  *http.Client
+}
+
+// NewKafkaRESTSink returns a Sink posting to the Kafka REST proxy at address,
+// in IP[:PORT] format.
+func NewKafkaRESTSink(address string) Sink {
+	return &kafkaRESTSink{
+		address: address,
+		client:  &http.Client{Timeout: kafkaPostTimeout},
+	}
+}
+
+type kafkaRecord struct {
+	Value Sample `json:"value"`
+}
+
+func (s *kafkaRESTSink) Write(topic string, samples []Sample) error {
+	records := make([]kafkaRecord, len(samples))
+	for i, sample := range samples {
+		records[i] = kafkaRecord{Value: sample}
+	}
+	body, err := json.Marshal(struct {
+		Records []kafkaRecord `json:"records"`
+	}{records})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("http://%s/topics/%s", s.address, topic)
+	resp, err := s.client.Post(url, "application/vnd.kafka.json.v2+json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("kafka rest proxy returned %s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+// writerSink writes each sample as a JSON line with its topic, which is meant
+// for local testing.
+type writerSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterSink returns a Sink writing the samples to w.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{enc: json.NewEncoder(w)}
+}
+
+func (s *writerSink) Write(topic string, samples []Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sample := range samples {
+		if err := s.enc.Encode(struct {
+			Topic string `json:"topic"`
+			Value Sample `json:"value"`
+		}{topic, sample}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
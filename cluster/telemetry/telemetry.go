@@ -0,0 +1,207 @@
+// Package telemetry publishes JSON samples about mined and propagated blocks
+// and block insertion errors to the topics of MonitoringConfig, so that the
+// Go cluster feeds the same dashboards as the Python one.
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	queueSize     = 1024 // samples queued before new ones are dropped
+	batchSize     = 64   // samples of a topic written at once
+	flushInterval = time.Second
+)
+
+var droppedCounter = metrics.NewRegisteredCounter("telemetry/dropped", nil)
+
+// Sample is a JSON event published to a topic.
+type Sample map[string]interface{}
+
+type record struct {
+	topic  string
+	sample Sample
+}
+
+// Publisher batches the samples published to it and writes them to its sink
+// in the background. Samples are dropped if the queue is full so that a slow
+// sink never blocks block processing. All the methods of a nil Publisher are
+// no-ops, which is what New returns when telemetry is not configured.
+type Publisher struct {
+	cfg    *config.MonitoringConfig
+	sink   Sink
+	closer io.Closer // closed on Stop, the sample file if any
+	queue  chan record
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New returns a Publisher writing to the sample file of cfg if it is set, or
+// posting to its Kafka REST proxy otherwise. It returns nil if neither is set.
+func New(cfg *config.MonitoringConfig) (*Publisher, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	var sink Sink
+	switch {
+	case cfg.SampleFile == "stdout":
+		sink = NewWriterSink(os.Stdout)
+	case cfg.SampleFile != "":
+		f, err := os.OpenFile(cfg.SampleFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		p := NewWithSink(cfg, NewWriterSink(f))
+		p.closer = f
+		return p, nil
+	case cfg.KafkaRestAddress != "":
+		sink = NewKafkaRESTSink(cfg.KafkaRestAddress)
+	default:
+		return nil, nil
+	}
+	return NewWithSink(cfg, sink), nil
+}
+
+// NewWithSink returns a Publisher writing to sink.
+func NewWithSink(cfg *config.MonitoringConfig, sink Sink) *Publisher {
+	p := &Publisher{
+		cfg:   cfg,
+		sink:  sink,
+		queue: make(chan record, queueSize),
+		quit:  make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.loop()
+	return p
+}
+
+// Stop writes the queued samples, stops the publisher and closes the sample
+// file it writes to.
+func (p *Publisher) Stop() {
+	if p == nil {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+	if p.closer != nil {
+		if err := p.closer.Close(); err != nil {
+			log.Warn("Failed to close telemetry sample file", "err", err)
+		}
+	}
+}
+
+// Publish queues sample to be written to topic, dropping it if the queue is
+// full.
+func (p *Publisher) Publish(topic string, sample Sample) {
+	if p == nil || topic == "" {
+		return
+	}
+	select {
+	case p.queue <- record{topic: topic, sample: sample}:
+	default:
+		droppedCounter.Inc(1)
+		log.Debug("Telemetry queue full, sample dropped", "topic", topic)
+	}
+}
+
+// BlockMined publishes a sample to the miner topic about the block of header
+// mined locally.
+func (p *Publisher) BlockMined(header types.IHeader) {
+	if p == nil {
+		return
+	}
+	p.Publish(p.cfg.MinerTopic, p.headerSample(header))
+}
+
+// BlockReceived publishes a sample to the propagation topic about the block
+// of header received from peer, with the latency since it was created.
+func (p *Publisher) BlockReceived(header types.IHeader, peer string) {
+	if p == nil {
+		return
+	}
+	sample := p.headerSample(header)
+	sample["peer"] = peer
+	sample["latency_ms"] = time.Now().UnixNano()/int64(time.Millisecond) - int64(header.GetTime())*1000
+	p.Publish(p.cfg.PropagationTopic, sample)
+}
+
+// InsertError publishes a sample to the errors topic about the block of
+// header failing to be inserted into its chain.
+func (p *Publisher) InsertError(header types.IHeader, err error) {
+	if p == nil {
+		return
+	}
+	sample := p.headerSample(header)
+	sample["type"] = "insert"
+	sample["error"] = err.Error()
+	p.Publish(p.cfg.Errors, sample)
+}
+
+func (p *Publisher) headerSample(header types.IHeader) Sample {
+	shard := "R"
+	if mHeader, ok := header.(*types.MinorBlockHeader); ok {
+		shard = fmt.Sprintf("%d", mHeader.Branch.Value)
+	}
+	return Sample{
+		"time":       time.Now().Unix(),
+		"network":    p.cfg.NetworkName,
+		"cluster":    p.cfg.ClusterID,
+		"shard":      shard,
+		"hash":       header.Hash().Hex(),
+		"height":     header.NumberU64(),
+		"block_time": header.GetTime(),
+		"coinbase":   header.GetCoinbase().ToHex(),
+		"difficulty": header.GetDifficulty().String(),
+	}
+}
+
+func (p *Publisher) loop() {
+	defer p.wg.Done()
+
+	var (
+		ticker  = time.NewTicker(flushInterval)
+		batches = make(map[string][]Sample)
+	)
+	defer ticker.Stop()
+
+	flush := func(topic string) {
+		if err := p.sink.Write(topic, batches[topic]); err != nil {
+			log.Warn("Failed to publish telemetry", "topic", topic, "samples", len(batches[topic]), "err", err)
+		}
+		delete(batches, topic)
+	}
+	for {
+		select {
+		case r := <-p.queue:
+			batches[r.topic] = append(batches[r.topic], r.sample)
+			if len(batches[r.topic]) >= batchSize {
+				flush(r.topic)
+			}
+		case <-ticker.C:
+			for topic := range batches {
+				flush(topic)
+			}
+		case <-p.quit:
+			for {
+				select {
+				case r := <-p.queue:
+					batches[r.topic] = append(batches[r.topic], r.sample)
+				default:
+					for topic := range batches {
+						flush(topic)
+					}
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testHeader() *types.MinorBlockHeader {
+	return &types.MinorBlockHeader{
+		Number:     7,
+		Branch:     account.Branch{Value: 3},
+		Time:       1000,
+		Difficulty: big.NewInt(100),
+		Coinbase:   account.CreatEmptyAddress(0),
+	}
+}
+
+type blockingSink chan struct{}
+
+func (s blockingSink) Write(topic string, samples []Sample) error {
+	<-s
+	return nil
+}
+
+func TestWriterSink(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewWithSink(config.NewMonitoringConfig(), NewWriterSink(buf))
+	header := testHeader()
+	p.BlockMined(header)
+	p.BlockReceived(header, "peer1")
+	p.InsertError(header, errors.New("bad block"))
+	p.Stop()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	topics := make(map[string]map[string]interface{})
+	for _, line := range lines {
+		var rec struct {
+			Topic string                 `json:"topic"`
+			Value map[string]interface{} `json:"value"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(line), &rec))
+		assert.Equal(t, header.Hash().Hex(), rec.Value["hash"])
+		assert.Equal(t, "3", rec.Value["shard"])
+		topics[rec.Topic] = rec.Value
+	}
+	assert.Equal(t, float64(7), topics["qkc_miner"]["height"])
+	assert.Equal(t, "peer1", topics["block_propagation"]["peer"])
+	assert.NotNil(t, topics["block_propagation"]["latency_ms"])
+	assert.Equal(t, "bad block", topics["error"]["error"])
+}
+
+func TestSampleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telemetry")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.NewMonitoringConfig()
+	cfg.SampleFile = filepath.Join(dir, "samples.json")
+	p, err := New(cfg)
+	assert.NoError(t, err)
+	p.BlockMined(testHeader())
+	p.Stop()
+
+	data, err := ioutil.ReadFile(cfg.SampleFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(strings.Split(strings.TrimSpace(string(data)), "\n")))
+	// the file is closed by Stop
+	assert.Equal(t, os.ErrClosed, p.closer.Close().(*os.PathError).Err)
+}
+
+func TestKafkaRESTSink(t *testing.T) {
+	var (
+		path, contentType string
+		body              struct {
+			Records []struct {
+				Value map[string]interface{} `json:"value"`
+			} `json:"records"`
+		}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+	}))
+	defer server.Close()
+
+	cfg := config.NewMonitoringConfig()
+	cfg.KafkaRestAddress = strings.TrimPrefix(server.URL, "http://")
+	p, err := New(cfg)
+	assert.NoError(t, err)
+	p.BlockMined(testHeader())
+	p.BlockMined(testHeader())
+	p.Stop()
+
+	assert.Equal(t, "/topics/qkc_miner", path)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", contentType)
+	assert.Equal(t, 2, len(body.Records))
+}
+
+func TestPublishDropsOnOverflow(t *testing.T) {
+	sink := make(blockingSink)
+	p := NewWithSink(config.NewMonitoringConfig(), sink)
+	// the loop blocks on the first full batch, the rest fill the queue and
+	// the samples after are dropped instead of blocking
+	for i := 0; i < batchSize+queueSize*2; i++ {
+		p.BlockMined(testHeader())
+	}
+	close(sink)
+	p.Stop()
+}
+
+func TestNilPublisher(t *testing.T) {
+	p, err := New(config.NewMonitoringConfig())
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+	p.BlockMined(testHeader())
+	p.Stop()
+}
@@ -0,0 +1,410 @@
+// Modified from go-ethereum under GNU Lesser General Public License
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/cluster/master"
+	"github.com/QuarkChain/goquarkchain/cluster/shard"
+	"github.com/QuarkChain/goquarkchain/cmd/utils"
+	"github.com/QuarkChain/goquarkchain/core"
+	"github.com/QuarkChain/goquarkchain/core/rawdb"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/QuarkChain/goquarkchain/qkcdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	chainFlags = []cli.Flag{
+		ClusterConfigFlag,
+		utils.DataDirFlag,
+		utils.DbPathRootFlag,
+		utils.DbBackendFlag,
+	}
+
+	importCommand = cli.Command{
+		Action:    utils.MigrateFlags(importChain),
+		Name:      "import",
+		Usage:     "Import a cluster archive file",
+		ArgsUsage: "<filename>",
+		Flags:     chainFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The import command inserts the root and minor blocks of an archive written by
+the export command into the databases of master and slaves under the data
+directory, validating every block. If the file name ends with .gz the archive
+is gunzipped. Known blocks are skipped, so an interrupted import can be resumed
+with the same archive.`,
+	}
+	exportCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportChain),
+		Name:      "export",
+		Usage:     "Export the root and minor chains into a cluster archive file",
+		ArgsUsage: "<filename> [<rootBlockNumFirst> <rootBlockNumLast>]",
+		Flags:     chainFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+Optional first and last arguments determine the root blocks to export, each
+preceded by the minor blocks it confirms. When exporting up to the root tip,
+the minor blocks not confirmed yet are exported after it. If the file name
+ends with .gz the archive is gzipped.`,
+	}
+	snapshotCommand = cli.Command{
+		Action:    utils.MigrateFlags(snapshotState),
+		Name:      "snapshot",
+		Usage:     "Export the state of all the shards at a root block into a snapshot file",
+		ArgsUsage: "<filename> [<rootBlockNum>]",
+		Flags:     chainFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The snapshot command writes the account and storage state of every shard at
+the minor block confirmed by the given root block, the root tip by default,
+together with the recent blocks needed to go on from there. If the file name
+ends with .gz the snapshot is gzipped.`,
+	}
+	bootstrapCommand = cli.Command{
+		Action:    utils.MigrateFlags(bootstrapState),
+		Name:      "bootstrap",
+		Usage:     "Bootstrap empty master and slave databases from a snapshot file",
+		ArgsUsage: "<filename>",
+		Flags:     chainFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The bootstrap command imports a snapshot written by the snapshot command into
+the empty databases of master and slaves under the data directory, verifying
+the state of every shard against its state root. The cluster then starts at
+the root block of the snapshot without replaying history. If the file name
+ends with .gz the snapshot is gunzipped.`,
+	}
+	pruneCommand = cli.Command{
+		Action:    utils.MigrateFlags(pruneState),
+		Name:      "prune",
+		Usage:     "Remove the state of old minor blocks from the shard databases",
+		ArgsUsage: " ",
+		Flags:     append(chainFlags, utils.StateRetentionFlag),
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The prune command removes from the database of every shard the state trie
+nodes and contract code no longer reachable from the state of the last
+STATE_RETENTION minor blocks, or of the minor blocks confirmed by the last
+STATE_RETENTION root blocks. The cluster must be stopped. Blocks, receipts
+and transactions are kept, but the state of pruned blocks can no longer be
+queried.`,
+	}
+)
+
+// clusterChains holds the root chain and the chains of all the shards of the
+// cluster, opened from the databases master and slaves keep under the data
+// directory: <datadir>/master/db for the root chain and
+// <datadir>/<slave id>/shard-<full shard id>/db for the shards.
+type clusterChains struct {
+	cfg       qkcConfig
+	rootChain *core.RootBlockChain
+	shards    map[uint32]*core.MinorBlockChain
+	dbs       []ethdb.Database
+}
+
+func newClusterChains(ctx *cli.Context) *clusterChains {
+	_, cfg := makeConfigNode(ctx)
+	if err := config.UpdateGenesisAlloc(&cfg.Cluster); err != nil {
+		utils.Fatalf("Update genesis alloc err: %v", err)
+	}
+	return &clusterChains{cfg: cfg}
+}
+
+// openClusterChains opens the root chain and the chains of the shards, closing
+// whatever it opened if it fails.
+func openClusterChains(ctx *cli.Context) (*clusterChains, error) {
+	chains := newClusterChains(ctx)
+	if err := chains.open(); err != nil {
+		chains.close()
+		return nil, err
+	}
+	return chains, nil
+}
+
+func (c *clusterChains) open() error {
+	qkcCfg := c.cfg.Cluster.Quarkchain
+	db, err := c.openMasterDatabase()
+	if err != nil {
+		return fmt.Errorf("Failed to open database: %v", err)
+	}
+	if _, genesisHash, genesisErr := core.SetupGenesisRootBlock(db, core.NewGenesis(qkcCfg)); genesisErr != nil {
+		rawdb.WriteChainConfig(db, genesisHash, qkcCfg)
+	}
+	engine, err := master.CreateConsensusEngine(qkcCfg.Root, qkcCfg.GuardianPublicKey, qkcCfg.EnableQkcHashXHeight)
+	if err != nil {
+		return err
+	}
+	if c.rootChain, err = core.NewRootBlockChain(db, qkcCfg, engine); err != nil {
+		return fmt.Errorf("Failed to create root chain: %v", err)
+	}
+	if c.shards, err = core.OpenClusterShards(c.rootChain, c.newShard); err != nil {
+		return fmt.Errorf("Failed to create shard chains: %v", err)
+	}
+	return nil
+}
+
+// openMasterDatabase opens the database of the root chain master keeps.
+func (c *clusterChains) openMasterDatabase() (ethdb.Database, error) {
+	return c.openDatabase(filepath.Join(clientIdentifier, "db"))
+}
+
+// openShardDatabase opens the database of a shard the slave covering it keeps.
+func (c *clusterChains) openShardDatabase(fullShardID uint32) (ethdb.Database, error) {
+	dir, err := c.shardDir(fullShardID)
+	if err != nil {
+		return nil, err
+	}
+	return c.openDatabase(filepath.Join(dir, "db"))
+}
+
+// shardDir returns the directory of a shard under the instance directory of
+// the slave covering it, relative to the data directory.
+func (c *clusterChains) shardDir(fullShardID uint32) (string, error) {
+	for _, slv := range c.cfg.Cluster.SlaveList {
+		for _, mask := range slv.ChainMaskList {
+			if mask.ContainFullShardId(fullShardID) {
+				return filepath.Join(slv.ID, fmt.Sprintf("shard-%d", fullShardID)), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no slave covers shard %d", fullShardID)
+}
+
+// openDatabase opens the database at path under the data directory, whatever
+// service the command runs as.
+func (c *clusterChains) openDatabase(path string) (ethdb.Database, error) {
+	if c.cfg.Service.DataDir == "" {
+		return nil, errors.New("data directory not set")
+	}
+	db, err := qkcdb.Open(filepath.Join(c.cfg.Service.DataDir, path), &c.cfg.Service.DB, false, false)
+	if err != nil {
+		return nil, err
+	}
+	c.dbs = append(c.dbs, db)
+	return db, nil
+}
+
+// newShard creates the chain of a shard the way slaves do.
+func (c *clusterChains) newShard(fullShardID uint32, rBlock *types.RootBlock) (*core.MinorBlockChain, error) {
+	qkcCfg := c.cfg.Cluster.Quarkchain
+	db, err := c.openShardDatabase(fullShardID)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := shard.CreateConsensusEngine(qkcCfg.EnableQkcHashXHeight, qkcCfg.GetShardConfigByFullShardID(fullShardID))
+	if err != nil {
+		return nil, err
+	}
+	if _, genesisHash, genesisErr := core.SetupGenesisMinorBlock(db, core.NewGenesis(qkcCfg), rBlock, fullShardID); genesisErr != nil {
+		rawdb.WriteChainConfig(db, genesisHash, qkcCfg)
+	}
+	return core.NewMinorBlockChain(db, nil, &params.ChainConfig{}, &c.cfg.Cluster, engine, vm.Config{}, nil, fullShardID)
+}
+
+func (c *clusterChains) close() {
+	for _, shard := range c.shards {
+		shard.Stop()
+	}
+	if c.rootChain != nil {
+		c.rootChain.Stop()
+	}
+	for _, db := range c.dbs {
+		db.Close()
+	}
+}
+
+func importChain(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	chains, err := openClusterChains(ctx)
+	if err != nil {
+		return err
+	}
+	defer chains.close()
+
+	fh, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("Import error: %v", err)
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(ctx.Args().First(), ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return fmt.Errorf("Import error: %v", err)
+		}
+	}
+
+	start := time.Now()
+	log.Info("Importing cluster", "file", ctx.Args().First())
+	if err := core.ImportCluster(reader, chains.rootChain, chains.shards, chains.newShard); err != nil {
+		return fmt.Errorf("Import error: %v", err)
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+func exportChain(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 && len(ctx.Args()) != 3 {
+		utils.Fatalf("This command requires one or three arguments.")
+	}
+	chains, err := openClusterChains(ctx)
+	if err != nil {
+		return err
+	}
+	defer chains.close()
+
+	first, last := uint64(0), chains.rootChain.CurrentBlock().NumberU64()
+	if len(ctx.Args()) == 3 {
+		var err1, err2 error
+		first, err1 = strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		last, err2 = strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+		if err1 != nil || err2 != nil {
+			return errors.New("Export error in parsing parameters: block number not an integer")
+		}
+	}
+
+	fh, err := os.OpenFile(ctx.Args().First(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Export error: %v", err)
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(ctx.Args().First(), ".gz") {
+		gz := gzip.NewWriter(writer)
+		defer gz.Close()
+		writer = gz
+	}
+
+	start := time.Now()
+	log.Info("Exporting cluster", "file", ctx.Args().First())
+	if err := core.ExportCluster(writer, chains.rootChain, chains.shards, first, last); err != nil {
+		return fmt.Errorf("Export error: %v", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func snapshotState(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 && len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires one or two arguments.")
+	}
+	chains, err := openClusterChains(ctx)
+	if err != nil {
+		return err
+	}
+	defer chains.close()
+
+	number := chains.rootChain.CurrentBlock().NumberU64()
+	if len(ctx.Args()) == 2 {
+		if number, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return errors.New("Snapshot error in parsing parameters: block number not an integer")
+		}
+	}
+
+	fh, err := os.OpenFile(ctx.Args().First(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Snapshot error: %v", err)
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(ctx.Args().First(), ".gz") {
+		gz := gzip.NewWriter(writer)
+		defer gz.Close()
+		writer = gz
+	}
+
+	start := time.Now()
+	log.Info("Writing snapshot", "file", ctx.Args().First(), "root block", number)
+	if err := core.ExportSnapshot(writer, chains.rootChain, chains.shards, number); err != nil {
+		return fmt.Errorf("Snapshot error: %v", err)
+	}
+	fmt.Printf("Snapshot done in %v\n", time.Since(start))
+	return nil
+}
+
+func bootstrapState(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	chains := newClusterChains(ctx)
+	defer chains.close()
+
+	fh, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("Bootstrap error: %v", err)
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(ctx.Args().First(), ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return fmt.Errorf("Bootstrap error: %v", err)
+		}
+	}
+
+	db, err := chains.openMasterDatabase()
+	if err != nil {
+		return fmt.Errorf("Failed to open database: %v", err)
+	}
+
+	start := time.Now()
+	log.Info("Bootstrapping from snapshot", "file", ctx.Args().First())
+	rBlock, err := core.ImportSnapshot(reader, db, chains.openShardDatabase)
+	if err != nil {
+		return fmt.Errorf("Bootstrap error: %v", err)
+	}
+	fmt.Printf("Bootstrap at root block #%d done in %v\n", rBlock.NumberU64(), time.Since(start))
+	return nil
+}
+
+func pruneState(ctx *cli.Context) error {
+	chains, err := openClusterChains(ctx)
+	if err != nil {
+		return err
+	}
+	defer chains.close()
+
+	ids := make([]uint32, 0, len(chains.shards))
+	for id := range chains.shards {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	retention := chains.cfg.Cluster.StateRetention
+	start := time.Now()
+	removed := 0
+	for _, id := range ids {
+		log.Info("Pruning state", "shard", id, "retention", retention)
+		dir, err := chains.shardDir(id)
+		if err != nil {
+			return fmt.Errorf("Prune error: %v", err)
+		}
+		n, err := core.PruneState(chains.shards[id], retention, filepath.Join(chains.cfg.Service.DataDir, dir, "prune"))
+		if err != nil {
+			return fmt.Errorf("Prune error: %v", err)
+		}
+		removed += n
+	}
+	fmt.Printf("Prune removed %d state entries in %v\n", removed, time.Since(start))
+	return nil
+}
@@ -101,10 +101,31 @@ func makeConfigNode(ctx *cli.Context) (*service.Node, qkcConfig) {
 			cfg.Service.WSEndpoint = fmt.Sprintf("%s:%d", ip, port)
 		}
 
+		// set metrics endpoint
+		if ctx.GlobalBool(utils.MetricsEnabledFlag.Name) {
+			sufPort, _ := strconv.Atoi(slv.ID[1:])
+			cfg.Service.MetricsEndpoint = metricsEndpoint(ctx, slv.IP, slv.MetricsPort+uint16(sufPort))
+		}
+
 		// load genesis accounts
 		if err := config.UpdateGenesisAlloc(&cfg.Cluster); err != nil {
 			utils.Fatalf("Update genesis alloc err: %v", err)
 		}
+	} else {
+		if ctx.GlobalBool(utils.WSEnableFlag.Name) {
+			// set the websocket endpoint of the subscriptions across all shards
+			ip, port := cfg.Cluster.JSONRPCHOST, cfg.Cluster.WSPort
+			if ctx.GlobalIsSet(utils.WSRPCHostFlag.Name) {
+				ip = ctx.GlobalString(utils.WSRPCHostFlag.Name)
+			}
+			if ctx.GlobalIsSet(utils.WSRPCPortFlag.Name) {
+				port = uint16(ctx.GlobalInt(utils.WSRPCPortFlag.Name))
+			}
+			cfg.Service.WSEndpoint = fmt.Sprintf("%s:%d", ip, port)
+		}
+		if ctx.GlobalBool(utils.MetricsEnabledFlag.Name) {
+			cfg.Service.MetricsEndpoint = metricsEndpoint(ctx, cfg.Cluster.JSONRPCHOST, cfg.Cluster.MetricsPort)
+		}
 	}
 	// Load default cluster config.
 	utils.SetNodeConfig(ctx, &cfg.Service, &cfg.Cluster)
@@ -117,6 +138,18 @@ func makeConfigNode(ctx *cli.Context) (*service.Node, qkcConfig) {
 	return stack, cfg
 }
 
+// metricsEndpoint returns the metrics endpoint at ip:port unless overridden by
+// the metrics host and port flags.
+func metricsEndpoint(ctx *cli.Context, ip string, port uint16) string {
+	if ctx.GlobalIsSet(utils.MetricsHostFlag.Name) {
+		ip = ctx.GlobalString(utils.MetricsHostFlag.Name)
+	}
+	if ctx.GlobalIsSet(utils.MetricsPortFlag.Name) {
+		port = uint16(ctx.GlobalInt(utils.MetricsPortFlag.Name))
+	}
+	return fmt.Sprintf("%s:%d", ip, port)
+}
+
 func makeFullNode(ctx *cli.Context) *service.Node {
 	stack, cfg := makeConfigNode(ctx)
 
@@ -30,6 +30,7 @@ var (
 		ClusterConfigFlag,
 		utils.ServiceFlag,
 		utils.DataDirFlag,
+		utils.KeyStoreDirFlag,
 		utils.LogLevelFlag,
 		utils.CleanFlag,
 		utils.CacheFlag,
@@ -37,6 +38,7 @@ var (
 		utils.GenesisDirFlag,
 		utils.NetworkIdFlag,
 		utils.DbPathRootFlag,
+		utils.DbBackendFlag,
 		utils.P2pFlag,
 		utils.P2pPortFlag,
 		utils.CheckDBFlag,
@@ -45,6 +47,9 @@ var (
 		utils.CheckDBRBlockBatchFlag,
 
 		utils.EnableTransactionHistoryFlag,
+		utils.SyncModeFlag,
+		utils.GCModeFlag,
+		utils.StateRetentionFlag,
 		utils.MaxPeersFlag,
 		utils.BootnodesFlag,
 		utils.UpnpFlag,
@@ -57,6 +62,8 @@ var (
 		utils.RPCPortFlag,
 		utils.PrivateRPCListenAddrFlag,
 		utils.PrivateRPCPortFlag,
+		utils.StratumHostFlag,
+		utils.StratumPortFlag,
 		utils.IPCEnableFlag,
 		utils.IPCPathFlag,
 		utils.GRPCAddrFlag,
@@ -64,6 +71,9 @@ var (
 		utils.WSEnableFlag,
 		utils.WSRPCHostFlag,
 		utils.WSRPCPortFlag,
+		utils.MetricsEnabledFlag,
+		utils.MetricsHostFlag,
+		utils.MetricsPortFlag,
 	}
 )
 
@@ -71,7 +81,13 @@ func init() {
 	// Initialize the CLI app and start Geth
 	app.Action = cluster
 	app.HideVersion = true // we have a command to print the version
-	app.Commands = []cli.Command{}
+	app.Commands = []cli.Command{
+		importCommand,
+		exportCommand,
+		snapshotCommand,
+		bootstrapCommand,
+		pruneCommand,
+	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	app.Flags = append(app.Flags, debug.Flags...)
@@ -125,6 +141,7 @@ func cluster(ctx *cli.Context) error {
 		return fmt.Errorf("invalid command: %q", args[0])
 	}
 	node := makeFullNode(ctx)
+	utils.SetupMetrics(ctx)
 	startService(ctx, node)
 	node.Wait()
 	return nil
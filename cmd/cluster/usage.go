@@ -48,6 +48,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.ServiceFlag,
 			utils.DataDirFlag,
+			utils.KeyStoreDirFlag,
 			ClusterConfigFlag,
 			utils.LogLevelFlag,
 			utils.CleanFlag,
@@ -59,6 +60,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.GRPCAddrFlag,
 			utils.GRPCPortFlag,
 			utils.EnableTransactionHistoryFlag,
+			utils.SyncModeFlag,
+			utils.GCModeFlag,
+			utils.StateRetentionFlag,
 			utils.CheckDBFlag,
 			utils.CheckDBRBlockFromFlag,
 			utils.CheckDBRBlockToFlag,
@@ -91,6 +95,13 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.PrivateRPCPortFlag,
 		},
 	},
+	{
+		Name: "STRATUM",
+		Flags: []cli.Flag{
+			utils.StratumHostFlag,
+			utils.StratumPortFlag,
+		},
+	},
 	{
 		Name: "WEBSOCKET API",
 		Flags: []cli.Flag{
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/QuarkChain/goquarkchain/core"
+	"github.com/QuarkChain/goquarkchain/core/rawdb"
+	"github.com/QuarkChain/goquarkchain/qkcdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	ethlog "github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// Flags
+	dbPath    = flag.String("db", "", "database directory of the master or of a shard, e.g. ./data/master/db or ./data/S0/shard-1/db")
+	dbBackend = flag.String("db_backend", qkcdb.DefaultBackend, "key-value store of the database: rocksdb or leveldb")
+	limit     = flag.Int("limit", 100, "maximum number of problems verify reports")
+	logLvl    = flag.String("loglvl", "warn", "log level")
+)
+
+const usage = `Usage: dbtool --db <dir> [flags] <command> [arguments]
+
+Commands:
+  inspect                 print the number and size of the entries by kind
+  get <hash|number>       print the header, block, receipts and lookup entry of a hash,
+                          or of the canonical block with the number
+  verify [first [last]]   verify the canonical index between two block numbers
+  sethead <number>        rewind the head of a shard to the canonical block with the number
+
+All commands but sethead open the database read-only. The cluster must be stopped.
+
+Flags:
+`
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Fatal: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func openDatabase(readOnly bool) ethdb.Database {
+	if *dbPath == "" {
+		fatalf("--db is required")
+	}
+	// opening creates missing databases
+	if _, err := os.Stat(*dbPath); err != nil {
+		fatalf("%v", err)
+	}
+	var (
+		db  ethdb.Database
+		err error
+	)
+	if readOnly {
+		db, err = qkcdb.OpenReadOnly(*dbPath, &qkcdb.Config{Backend: *dbBackend})
+	} else {
+		db, err = qkcdb.Open(*dbPath, &qkcdb.Config{Backend: *dbBackend}, false, false)
+	}
+	if err != nil {
+		fatalf("Failed to open database: %v", err)
+	}
+	return db
+}
+
+// chainTypeOf tells the database of a shard from the database of the master
+// by the canonical index it holds.
+func chainTypeOf(db ethdb.Database) (rawdb.ChainType, error) {
+	if rawdb.ReadCanonicalHash(db, rawdb.ChainTypeMinor, 0) != (common.Hash{}) {
+		return rawdb.ChainTypeMinor, nil
+	}
+	if rawdb.ReadCanonicalHash(db, rawdb.ChainTypeRoot, 0) != (common.Hash{}) {
+		return rawdb.ChainTypeRoot, nil
+	}
+	return 0, errors.New("neither a master nor a shard database")
+}
+
+func chainName(chainType rawdb.ChainType) string {
+	if chainType == rawdb.ChainTypeRoot {
+		return "root"
+	}
+	return "minor"
+}
+
+func printJSON(title string, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fatalf("%s: %v", title, err)
+	}
+	fmt.Printf("%s: %s\n", title, data)
+}
+
+func inspect(db ethdb.Database) {
+	stats, err := rawdb.InspectDatabase(db)
+	if err != nil {
+		fatalf("Inspect error: %v", err)
+	}
+	var (
+		count int
+		size  common.StorageSize
+	)
+	fmt.Printf("%-26s %12s %12s\n", "KIND", "ENTRIES", "SIZE")
+	for _, s := range stats {
+		fmt.Printf("%-26s %12d %12s\n", s.Kind, s.Count, s.Size.String())
+		count += s.Count
+		size += s.Size
+	}
+	fmt.Printf("%-26s %12d %12s\n", "total", count, size.String())
+}
+
+func get(db ethdb.Database, chainType rawdb.ChainType, arg string) {
+	var hash common.Hash
+	if number, err := strconv.ParseUint(arg, 10, 64); err == nil {
+		if hash = rawdb.ReadCanonicalHash(db, chainType, number); hash == (common.Hash{}) {
+			fatalf("No canonical %s block #%d", chainName(chainType), number)
+		}
+	} else {
+		data, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+		if err != nil || len(data) != common.HashLength {
+			fatalf("Argument is neither a block number nor a hash: %s", arg)
+		}
+		hash = common.BytesToHash(data)
+	}
+
+	found := false
+	if chainType == rawdb.ChainTypeRoot {
+		if block := rawdb.ReadRootBlock(db, hash); block != nil {
+			found = true
+			canonical := rawdb.ReadCanonicalHash(db, chainType, block.NumberU64()) == hash
+			fmt.Printf("number: %d, canonical: %v\n", block.NumberU64(), canonical)
+			printJSON("root block header", block.Header())
+			hashes := make([]common.Hash, 0, len(block.MinorBlockHeaders()))
+			for _, header := range block.MinorBlockHeaders() {
+				hashes = append(hashes, header.Hash())
+			}
+			printJSON("confirmed minor blocks", hashes)
+		}
+		if header, rHash, index := rawdb.ReadMinorHeaderFromRootBlock(db, hash); header != nil {
+			found = true
+			fmt.Printf("minor block header %d of root block %x\n", index, rHash)
+			printJSON("minor block header", header)
+		}
+	} else {
+		if block := rawdb.ReadMinorBlock(db, hash); block != nil {
+			found = true
+			canonical := rawdb.ReadCanonicalHash(db, chainType, block.NumberU64()) == hash
+			fmt.Printf("number: %d, canonical: %v\n", block.NumberU64(), canonical)
+			printJSON("minor block header", block.Header())
+			printJSON("minor block meta", block.Meta())
+			hashes := make([]common.Hash, 0, len(block.Transactions()))
+			for _, tx := range block.Transactions() {
+				hashes = append(hashes, tx.Hash())
+			}
+			printJSON("transactions", hashes)
+		}
+		if rawdb.HasReceipts(db, hash) {
+			found = true
+			printJSON("receipts", rawdb.ReadReceipts(db, hash))
+		}
+		if tx, bHash, index := rawdb.ReadTransaction(db, hash); tx != nil {
+			found = true
+			fmt.Printf("transaction %d of minor block %x\n", index, bHash)
+		}
+		if list := rawdb.ReadCrossShardTxList(db, hash); list != nil {
+			found = true
+			printJSON("xshard tx list", list)
+		}
+		if mHash := rawdb.ReadLastConfirmedMinorBlockHeaderAtRootBlock(db, hash); mHash != (common.Hash{}) {
+			found = true
+			fmt.Printf("last minor block confirmed by root block: %x\n", mHash)
+		}
+	}
+	if !found {
+		fatalf("Nothing found for %x", hash)
+	}
+}
+
+func verify(db ethdb.Database, chainType rawdb.ChainType, args []string) {
+	first, last := uint64(0), ^uint64(0)
+	var err error
+	if len(args) > 0 {
+		if first, err = strconv.ParseUint(args[0], 10, 64); err != nil {
+			fatalf("Block number not an integer: %s", args[0])
+		}
+	}
+	if len(args) > 1 {
+		if last, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+			fatalf("Block number not an integer: %s", args[1])
+		}
+	}
+	problems := core.VerifyCanonicalChain(db, chainType, first, last, *limit)
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fatalf("%d problems found in the %s chain", len(problems), chainName(chainType))
+	}
+	fmt.Printf("The canonical %s chain is consistent\n", chainName(chainType))
+}
+
+func setHead(db ethdb.Database, chainType rawdb.ChainType, args []string) {
+	if chainType != rawdb.ChainTypeMinor {
+		fatalf("sethead only rewinds shard databases")
+	}
+	if len(args) != 1 {
+		fatalf("sethead requires a block number")
+	}
+	number, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		fatalf("Block number not an integer: %s", args[0])
+	}
+	if err := core.RewindMinorChain(db, number); err != nil {
+		fatalf("Rewind error: %v", err)
+	}
+	fmt.Printf("Head rewound to #%d\n", number)
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	lvl, err := ethlog.LvlFromString(*logLvl)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	ethlog.Root().SetHandler(ethlog.LvlFilterHandler(lvl, ethlog.StreamHandler(os.Stderr, ethlog.TerminalFormat(false))))
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command, args := args[0], args[1:]
+	switch command {
+	case "inspect", "get", "verify", "sethead":
+	default:
+		fatalf("Unknown command %s", command)
+	}
+
+	db := openDatabase(command != "sethead")
+	defer db.Close()
+	if command == "inspect" {
+		inspect(db)
+		return
+	}
+	chainType, err := chainTypeOf(db)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	switch command {
+	case "get":
+		if len(args) != 1 {
+			fatalf("get requires a hash or a block number")
+		}
+		get(db, chainType, args[0])
+	case "verify":
+		verify(db, chainType, args)
+	case "sethead":
+		setHead(db, chainType, args)
+	}
+}
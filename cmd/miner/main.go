@@ -9,6 +9,8 @@ import (
 	"io/ioutil"
 	"log"
 	"math/big"
+	"os/user"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -41,8 +43,20 @@ var (
 	rpcTimeout      = flag.Int("timeout", 10, "timeout in seconds for RPC calls")
 	gethlogLvl      = flag.String("gethloglvl", "info", "log level of geth")
 	coinbaseAddress = flag.String("coinbase", "", "coinbase for miner")
+	dagDir          = flag.String("dagdir", defaultDagDir(), "directory to store the ethash DAGs and caches in")
+	dagsOnDisk      = flag.Int("dagsondisk", 2, "number of recent ethash DAGs to keep on disk")
+
+	// ethash miner shared by the workers, mining with the same DAG
+	ethashMiner *ethash.QEthash
 )
 
+func defaultDagDir() string {
+	if usr, err := user.Current(); err == nil {
+		return filepath.Join(usr.HomeDir, ".ethash")
+	}
+	return ".ethash"
+}
+
 // Wrap mining result, because the global receiver need to differentiate between workers
 type result struct {
 	worker *worker
@@ -274,7 +288,19 @@ func createMiner(consensusType string, diffCalculator *consensus.EthDifficultyCa
 	pubKey := []byte{}
 	switch consensusType {
 	case config.PoWEthash:
-		return ethash.New(ethash.Config{CachesInMem: 3, CachesOnDisk: 10, CacheDir: "", PowMode: ethash.ModeNormal}, diffCalculator, false, pubKey)
+		// mine with the full DAG, generated on the first work of an epoch
+		if ethashMiner == nil {
+			ethashMiner = ethash.New(ethash.Config{
+				CacheDir:       *dagDir,
+				CachesInMem:    2,
+				CachesOnDisk:   3,
+				DatasetDir:     *dagDir,
+				DatasetsInMem:  1,
+				DatasetsOnDisk: *dagsOnDisk,
+				PowMode:        ethash.ModeNormal,
+			}, diffCalculator, false, pubKey)
+		}
+		return ethashMiner
 	case config.PoWQkchash:
 		return qkchash.New(true, diffCalculator, false, pubKey, qkcHashXHeight)
 	case config.PoWDoubleSha256:
@@ -7,13 +7,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/QuarkChain/goquarkchain/cluster/config"
 	"github.com/QuarkChain/goquarkchain/cluster/master"
 	"github.com/QuarkChain/goquarkchain/cluster/service"
 	"github.com/QuarkChain/goquarkchain/p2p"
 	"github.com/QuarkChain/goquarkchain/params"
+	"github.com/QuarkChain/goquarkchain/qkcdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"gopkg.in/urfave/cli.v1"
@@ -78,6 +81,10 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{service.DefaultDataDir()},
 	}
+	KeyStoreDirFlag = DirectoryFlag{
+		Name:  "keystore",
+		Usage: "Directory for the keystore (default = inside the datadir)",
+	}
 	LogLevelFlag = cli.StringFlag{
 		Name:  "log_level",
 		Usage: "log level",
@@ -102,6 +109,10 @@ var (
 		Name:  "db_path_root",
 		Usage: "Data directory for the databases and keystore",
 	}
+	DbBackendFlag = cli.StringFlag{
+		Name:  "db_backend",
+		Usage: "Key-value store of the databases: rocksdb, leveldb or memory; leveldb by default in builds with the norocksdb tag",
+	}
 	P2pFlag = cli.BoolFlag{
 		Name:  "p2p",
 		Usage: "enables new p2p module",
@@ -110,6 +121,18 @@ var (
 		Name:  "enable_transaction_history",
 		Usage: "enable transaction history function",
 	}
+	SyncModeFlag = cli.StringFlag{
+		Name:  "sync_mode",
+		Usage: `Shard sync mode: "full" executes every block, "state" downloads the state at a recent block from peers`,
+	}
+	GCModeFlag = cli.StringFlag{
+		Name:  "gc_mode",
+		Usage: `Shard state garbage collection mode: "archive" keeps the state of every block, "pruned" only of recent and root confirmed blocks`,
+	}
+	StateRetentionFlag = cli.Uint64Flag{
+		Name:  "state_retention",
+		Usage: "number of recent minor blocks whose state is kept in pruned mode",
+	}
 	MaxPeersFlag = cli.Uint64Flag{
 		Name:  "max_peers",
 		Usage: "max peer for new p2p module",
@@ -180,6 +203,14 @@ var (
 		Name:  "json_rpc_private_port",
 		Usage: "public HTTP-RPC server listening port",
 	}
+	StratumHostFlag = cli.StringFlag{
+		Name:  "stratum_host",
+		Usage: "Stratum mining server listening interface",
+	}
+	StratumPortFlag = cli.IntFlag{
+		Name:  "stratum_port",
+		Usage: "Stratum mining server listening port of the master, disabled if 0",
+	}
 
 	GRPCAddrFlag = cli.StringFlag{
 		Name:  "grpc_host",
@@ -227,7 +258,7 @@ var (
 	}
 	WSRPCHostFlag = cli.StringFlag{
 		Name:  "ws_host",
-		Usage: "websocket rpc host work for slave service",
+		Usage: "websocket rpc host",
 		Value: config.DefaultHost,
 	}
 	WSRPCPortFlag = cli.IntFlag{
@@ -235,6 +266,20 @@ var (
 		Usage: "websocket rpc port",
 		Value: int(config.DefaultWSPort),
 	}
+	MetricsEnabledFlag = cli.BoolFlag{
+		Name:  metrics.MetricsEnabledFlag,
+		Usage: "enable metrics collection and the /metrics endpoint",
+	}
+	MetricsHostFlag = cli.StringFlag{
+		Name:  "metrics_host",
+		Usage: "metrics endpoint host",
+		Value: config.DefaultHost,
+	}
+	MetricsPortFlag = cli.IntFlag{
+		Name:  "metrics_port",
+		Usage: "metrics endpoint port",
+		Value: int(config.DefaultMetricsPort),
+	}
 )
 
 // setBootstrapNodes creates a list of bootstrap nodes from the command line
@@ -408,12 +453,35 @@ func SetClusterConfig(ctx *cli.Context, cfg *config.ClusterConfig) {
 		cfg.PrivateJSONRPCPort = uint16(ctx.GlobalInt(PrivateRPCPortFlag.Name))
 	}
 
+	if ctx.GlobalIsSet(StratumHostFlag.Name) {
+		cfg.StratumHost = ctx.GlobalString(StratumHostFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(StratumPortFlag.Name) {
+		cfg.StratumPort = uint16(ctx.GlobalInt(StratumPortFlag.Name))
+	}
+
 	if ctx.GlobalBool(StartSimulatedMiningFlag.Name) {
 		cfg.StartSimulatedMining = true
 	}
 	if ctx.GlobalBool(EnableTransactionHistoryFlag.Name) {
 		cfg.EnableTransactionHistory = true
 	}
+	if ctx.GlobalIsSet(SyncModeFlag.Name) {
+		cfg.SyncMode = ctx.GlobalString(SyncModeFlag.Name)
+	}
+	if cfg.SyncMode != config.SyncModeFull && cfg.SyncMode != config.SyncModeState {
+		Fatalf("Unknown sync mode %q", cfg.SyncMode)
+	}
+	if ctx.GlobalIsSet(GCModeFlag.Name) {
+		cfg.GCMode = ctx.GlobalString(GCModeFlag.Name)
+	}
+	if cfg.GCMode != config.GCModeArchive && cfg.GCMode != config.GCModePruned {
+		Fatalf("Unknown gc mode %q", cfg.GCMode)
+	}
+	if ctx.GlobalIsSet(StateRetentionFlag.Name) {
+		cfg.StateRetention = ctx.GlobalUint64(StateRetentionFlag.Name)
+	}
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.Quarkchain.NetworkID = uint32(ctx.GlobalInt(NetworkIdFlag.Name))
 	}
@@ -443,6 +511,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *service.Config, clstrCfg *config.Clust
 	setHTTP(ctx, cfg, clstrCfg)
 	setGRPC(ctx, cfg, clstrCfg)
 	setDataDir(ctx, cfg, clstrCfg)
+	setDatabase(ctx, cfg, clstrCfg)
 	setCheckDBConfig(ctx, clstrCfg)
 }
 
@@ -464,6 +533,29 @@ func setDataDir(ctx *cli.Context, cfg *service.Config, clstrCfg *config.ClusterC
 	if ctx.GlobalIsSet(DataDirFlag.Name) {
 		cfg.DataDir = ctx.GlobalString(DataDirFlag.Name)
 	}
+	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
+		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
+	}
+}
+
+func setDatabase(ctx *cli.Context, cfg *service.Config, clstrCfg *config.ClusterConfig) {
+	if ctx.GlobalIsSet(DbBackendFlag.Name) {
+		clstrCfg.DbBackend = ctx.GlobalString(DbBackendFlag.Name)
+	}
+	cfg.DB = qkcdb.Config{
+		Backend:     clstrCfg.DbBackend,
+		Cache:       clstrCfg.DbCacheSize,
+		Compression: clstrCfg.DbCompression,
+	}
+}
+
+// SetupMetrics starts collecting the metrics of the process if metrics are
+// enabled.
+func SetupMetrics(ctx *cli.Context) {
+	if metrics.Enabled {
+		log.Info("Enabling metrics collection")
+		go metrics.CollectProcessMetrics(3 * time.Second)
+	}
 }
 
 // checkExclusive verifies that only a single instance of the provided flags was
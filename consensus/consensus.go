@@ -409,24 +409,34 @@ func (c *CommonEngine) RefreshWork(tip uint64) {
 	}
 }
 func (c *CommonEngine) SubmitWork(nonce uint64, hash, digest common.Hash, signature *[65]byte) bool {
+	block, err := c.submit(&mineResult{nonce: nonce, mixDigest: digest, hash: hash, signature: signature})
+	return block && err == nil
+}
+
+// SubmitShare verifies a solution of remote mining against the difficulty of
+// the share, which can be lower than the difficulty of the block, and seals the
+// block if the solution meets that as well. It returns whether the block was
+// sealed, and an error if the share is invalid.
+func (c *CommonEngine) SubmitShare(nonce uint64, hash, digest common.Hash, shareDiff *big.Int) (bool, error) {
+	if shareDiff == nil {
+		return false, errInvalidShareDiff
+	}
+	return c.submit(&mineResult{nonce: nonce, mixDigest: digest, hash: hash, shareDiff: shareDiff})
+}
+
+func (c *CommonEngine) submit(result *mineResult) (bool, error) {
 	if !c.isRemote {
-		return false
+		return false, ErrNotRemote
 	}
-	var errc = make(chan error, 1)
+	result.res = make(chan submitResult, 1)
 
 	select {
-	case c.submitWorkCh <- &mineResult{
-		nonce:     nonce,
-		mixDigest: digest,
-		hash:      hash,
-		signature: signature,
-		errc:      errc,
-	}:
+	case c.submitWorkCh <- result:
 	case <-c.exitCh:
-		return false
+		return false, errors.New(fmt.Sprintf("%s hash stoped", c.Name()))
 	}
-	err := <-errc
-	return err == nil
+	res := <-result.res
+	return res.block, res.err
 }
 
 func (c *CommonEngine) SetThreads(threads int) {
@@ -4,8 +4,10 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/consensus"
 	"github.com/QuarkChain/goquarkchain/core/types"
 )
@@ -34,3 +36,47 @@ func TestVerifySeal(t *testing.T) {
 	err = d.VerifySeal(nil, header, big.NewInt(0))
 	assert.NoError(err, "should pass with 0 diff")
 }
+
+func TestSubmitShare(t *testing.T) {
+	assert := assert.New(t)
+	diffCalculator := consensus.EthDifficultyCalculator{AdjustmentCutoff: 7, AdjustmentFactor: 512, MinimumDifficulty: big.NewInt(100000)}
+
+	header := &types.RootBlockHeader{Number: 1, Difficulty: big.NewInt(1 << 12)}
+	d := New(&diffCalculator, true, []byte{})
+	defer d.Close()
+	resultsCh := make(chan types.IBlock, 1)
+	assert.NoError(d.Seal(nil, types.NewRootBlockWithHeader(header), nil, 1, resultsCh, nil))
+	work, err := d.GetWork(account.Address{})
+	assert.NoError(err)
+
+	// find the nonces of an invalid share, a share and a block
+	shareDiff := big.NewInt(16)
+	var invalid, share, block uint64
+	for nonce := uint64(1); block == 0; nonce++ {
+		header.Nonce = nonce
+		switch {
+		case verifySeal(nil, header, shareDiff) != nil:
+			invalid = nonce
+		case verifySeal(nil, header, header.Difficulty) != nil:
+			share = nonce
+		default:
+			block = nonce
+		}
+	}
+
+	_, err = d.SubmitShare(invalid, work.HeaderHash, common.Hash{}, shareDiff)
+	assert.Error(err, "should reject an invalid share")
+	_, err = d.SubmitShare(share, work.HeaderHash, common.Hash{}, nil)
+	assert.Error(err, "should reject a share without difficulty")
+	_, err = d.SubmitShare(share, common.Hash{}, common.Hash{}, shareDiff)
+	assert.Error(err, "should reject a share of unknown work")
+	sealed, err := d.SubmitShare(share, work.HeaderHash, common.Hash{}, shareDiff)
+	assert.NoError(err, "should accept a share")
+	assert.False(sealed, "should not seal the block with a share")
+	assert.False(d.SubmitWork(share, work.HeaderHash, common.Hash{}, nil), "should not accept a share as a block")
+
+	sealed, err = d.SubmitShare(block, work.HeaderHash, common.Hash{}, shareDiff)
+	assert.NoError(err, "should accept a share")
+	assert.True(sealed, "should seal the block with a share meeting its difficulty")
+	assert.Equal(block, (<-resultsCh).IHeader().GetNonce())
+}
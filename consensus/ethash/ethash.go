@@ -335,7 +335,7 @@ func (d *dataset) generate(dir string, limit int, test bool) {
 		if err != nil {
 			logger.Error("Failed to generate mapped ethash dataset", "err", err)
 
-			d.dataset = make([]uint32, dsize/2)
+			d.dataset = make([]uint32, dsize/4)
 			generateDataset(d.dataset, d.epoch, cache)
 		}
 		// Iterate over all previous instances and delete old ones
@@ -386,7 +386,9 @@ const (
 	ModeFullFake
 )
 
-// Config are the configuration parameters of the ethash.
+// Config are the configuration parameters of the ethash. Blocks are mined with
+// the full dataset (DAG) if DatasetsInMem is positive, with the verification
+// cache otherwise.
 type Config struct {
 	CacheDir       string
 	CachesInMem    int
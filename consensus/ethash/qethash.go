@@ -5,6 +5,7 @@ import (
 	"github.com/QuarkChain/goquarkchain/consensus"
 	"math/big"
 	"runtime"
+	"sync/atomic"
 
 	"github.com/QuarkChain/goquarkchain/core/state"
 	"github.com/QuarkChain/goquarkchain/core/types"
@@ -14,9 +15,20 @@ import (
 type QEthash struct {
 	*Ethash
 	*consensus.CommonEngine
+
+	mining atomic.Value // *dataset of the epoch mined last
 }
 
+// hashAlgo hashes with the full dataset if datasets are kept in memory, which
+// is much faster than with the verification cache for mining.
 func (q *QEthash) hashAlgo(shareCache *consensus.ShareCache) (err error) {
+	if q.config.DatasetsInMem > 0 {
+		dataset := q.miningDataset(shareCache.Height)
+		shareCache.Digest, shareCache.Result = hashimotoFull(dataset.dataset, shareCache.Hash, shareCache.Nonce)
+		// Datasets are unmapped in a finalizer, keep it alive while being used.
+		runtime.KeepAlive(dataset)
+		return nil
+	}
 	cache := q.cache(shareCache.Height)
 	size := datasetSize(shareCache.Height)
 	if q.config.PowMode == ModeTest {
@@ -26,6 +38,17 @@ func (q *QEthash) hashAlgo(shareCache *consensus.ShareCache) (err error) {
 	return nil
 }
 
+// miningDataset returns the dataset to mine the block of the height with, which
+// is generated, or loaded from disk, by the first thread mining its epoch.
+func (q *QEthash) miningDataset(height uint64) *dataset {
+	if d, ok := q.mining.Load().(*dataset); ok && d.epoch == height/epochLength {
+		return d
+	}
+	d := q.dataset(height, false)
+	q.mining.Store(d)
+	return d
+}
+
 // verifySeal implements consensus.Engine, checking whether the given block satisfies
 // the PoW difficulty requirements.
 func (q *QEthash) verifySeal(chain consensus.ChainReader, header types.IHeader, adjustedDiff *big.Int) error {
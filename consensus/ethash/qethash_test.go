@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -75,3 +76,36 @@ func TestVerifySeal(t *testing.T) {
 	err = e.VerifySeal(nil, header, big.NewInt(0))
 	assert.Error(err, "should have error because of the wrong nonce")
 }
+
+func TestMineWithDataset(t *testing.T) {
+	assert := assert.New(t)
+	tmpdir, err := ioutil.TempDir("", "ethash-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	diffCalculator := qkconsensus.EthDifficultyCalculator{AdjustmentCutoff: 7, AdjustmentFactor: 512, MinimumDifficulty: big.NewInt(100000)}
+	e := New(Config{CachesInMem: 1, DatasetDir: tmpdir, DatasetsInMem: 1, DatasetsOnDisk: 1, PowMode: ModeTest}, &diffCalculator, false, []byte{})
+
+	header := &types.RootBlockHeader{Number: 1, Difficulty: big.NewInt(100)}
+	work := qkconsensus.MiningWork{HeaderHash: header.SealHash(), Number: 1, Difficulty: header.Difficulty}
+	resultsCh := make(chan qkconsensus.MiningResult)
+	assert.NoError(e.FindNonce(work, resultsCh, nil), "should have no problem finding the nonce")
+	result := <-resultsCh
+
+	// the solution found with the dataset verifies with the cache
+	header.Nonce = result.Nonce
+	header.MixDigest = result.Digest
+	assert.NoError(e.VerifySeal(nil, header, nil), "should have correct nonce")
+
+	// hashing with the dataset and with the cache are the same
+	dataset := e.dataset(1, false)
+	for nonce := uint64(0); nonce < 16; nonce++ {
+		digest, res := hashimotoFull(dataset.dataset, work.HeaderHash.Bytes(), nonce)
+		lightDigest, lightRes := hashimotoLight(32*1024, e.cache(1).cache, work.HeaderHash.Bytes(), nonce)
+		assert.Equal(lightDigest, digest)
+		assert.Equal(lightRes, res)
+	}
+	matches, _ := filepath.Glob(filepath.Join(tmpdir, "full-R*"))
+	assert.NotEmpty(matches, "should store the dataset on disk")
+}
@@ -103,6 +103,10 @@ func (e *FakeEngine) SubmitWork(nonce uint64, hash, digest common.Hash, signatur
 	return false
 }
 
+func (e *FakeEngine) SubmitShare(nonce uint64, hash, digest common.Hash, shareDiff *big.Int) (bool, error) {
+	return false, ErrNotRemote
+}
+
 func (e *FakeEngine) SetThreads(threads int) {}
 func (e *FakeEngine) RefreshWork(tip uint64) {
 
@@ -82,6 +82,8 @@ type Engine interface {
 
 	SubmitWork(nonce uint64, hash, digest common.Hash, signature *[65]byte) bool
 
+	SubmitShare(nonce uint64, hash, digest common.Hash, shareDiff *big.Int) (bool, error)
+
 	SetThreads(threads int)
 
 	RefreshWork(tip uint64)
@@ -23,6 +23,7 @@ const (
 var (
 	ErrNoMiningWork      = errors.New("no mining work available yet")
 	errInvalidSealResult = errors.New("invalid or stale proof-of-work solution")
+	errInvalidShareDiff  = errors.New("invalid share difficulty")
 )
 
 type sealTask struct {
@@ -37,7 +38,13 @@ type mineResult struct {
 	mixDigest common.Hash
 	hash      common.Hash
 	signature *[65]byte
-	errc      chan error
+	shareDiff *big.Int // difficulty of the share if submitted as one
+	res       chan submitResult
+}
+
+type submitResult struct {
+	block bool // whether the solution sealed a block
+	err   error
 }
 
 type sealWork struct {
@@ -70,10 +77,14 @@ func (c *CommonEngine) remote() {
 		currentHeight = block.NumberU64()
 	}
 
-	submitWork := func(nonce uint64, mixDigest common.Hash, sealhash common.Hash, signature *[65]byte) bool {
+	// submitWork seals the block of the work with the solution. A share, whose
+	// difficulty shareDiff is lower than the difficulty of the block, is
+	// verified against shareDiff and only seals the block if it meets the
+	// difficulty of the block as well.
+	submitWork := func(nonce uint64, mixDigest common.Hash, sealhash common.Hash, signature *[65]byte, shareDiff *big.Int) (bool, error) {
 		if c.currentWorks.len() == 0 {
 			log.Error("Pending work without block", "sealhash", sealhash)
-			return false
+			return false, errInvalidSealResult
 		}
 		var block types.IBlock
 		value, ok := works.Get(sealhash)
@@ -82,18 +93,18 @@ func (c *CommonEngine) remote() {
 		}
 		if block == nil {
 			log.Warn("Work submitted but none pending", "sealhash", sealhash)
-			return false
+			return false, errInvalidSealResult
 		}
 
 		work, err := c.currentWorks.getWorkBySealHash(sealhash)
 		if err != nil {
 			log.Info("already be delete", "height", block.NumberU64())
-			return false
+			return false, errInvalidSealResult
 		}
 
 		if results == nil {
 			log.Warn("Qkc cash result channel is empty, submitted mining result is rejected")
-			return false
+			return false, errInvalidSealResult
 		}
 
 		solution := block.WithMingResult(nonce, mixDigest, signature)
@@ -109,23 +120,39 @@ func (c *CommonEngine) remote() {
 		}
 
 		start := time.Now()
-		if err := c.spec.VerifySeal(nil, solution.IHeader(), adjustedDiff); err != nil {
+		if shareDiff != nil && shareDiff.Cmp(adjustedDiff) < 0 {
+			if shareDiff.Sign() <= 0 {
+				return false, errInvalidShareDiff
+			}
+			if err := c.spec.VerifySeal(nil, solution.IHeader(), shareDiff); err != nil {
+				log.Debug("Invalid share submitted", "sealhash", sealhash.Hex(), "elapsed", time.Since(start), "err", err)
+				return false, errInvalidSealResult
+			}
+			if err := c.spec.VerifySeal(nil, solution.IHeader(), adjustedDiff); err != nil {
+				return false, nil
+			}
+		} else if err := c.spec.VerifySeal(nil, solution.IHeader(), adjustedDiff); err != nil {
 			log.Warn("Invalid proof-of-work submitted", "sealhash", sealhash.Hex(), "elapsed", time.Since(start), "err", err)
-			return false
+			return false, errInvalidSealResult
+		}
+		// A share sealing a block stays a valid share if the block is rejected.
+		rejected := errInvalidSealResult
+		if shareDiff != nil {
+			rejected = nil
 		}
 		if solution.NumberU64()+staleThreshold > currentHeight {
 			select {
 			case results <- solution:
 				log.Debug("Work submitted is acceptable", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
-				return true
+				return true, nil
 			default:
 				log.Warn("Sealing result is not read by miner", "mode", "remote", "sealhash", sealhash)
-				return false
+				return false, rejected
 			}
 		}
 		// The submitted block is too old to accept, drop it.
 		log.Warn("Work submitted is too old", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
-		return false
+		return false, rejected
 	}
 
 	for {
@@ -143,11 +170,8 @@ func (c *CommonEngine) remote() {
 			}
 
 		case result := <-c.submitWorkCh:
-			if submitWork(result.nonce, result.mixDigest, result.hash, result.signature) {
-				result.errc <- nil
-			} else {
-				result.errc <- errInvalidSealResult
-			}
+			block, err := submitWork(result.nonce, result.mixDigest, result.hash, result.signature, result.shareDiff)
+			result.res <- submitResult{block, err}
 
 		case errc := <-c.exitCh:
 			errc <- nil
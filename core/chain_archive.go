@@ -0,0 +1,362 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/serialize"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// A cluster archive is a stream of records, each made of a one byte kind, the
+// four bytes big endian length of the block and the serialized block. Each
+// root block is preceded by the minor blocks it confirms, so that importing
+// the records in order replays the cluster the way master and slaves do.
+const (
+	archiveRootBlock  byte = 0
+	archiveMinorBlock byte = 1
+
+	maxArchiveRecordSize = 64 * 1024 * 1024 // rejected before allocating the record
+)
+
+// NewShardChainFunc creates the chain of shard fullShardID when it is
+// initialized at root block rBlock.
+type NewShardChainFunc func(fullShardID uint32, rBlock *types.RootBlock) (*MinorBlockChain, error)
+
+// ExportCluster writes the root blocks from first to last of the active root
+// chain to w, each preceded by the minor blocks it confirms. If last is the
+// root tip, the minor blocks of shards not confirmed yet are written after it.
+func ExportCluster(w io.Writer, rootChain *RootBlockChain, shards map[uint32]*MinorBlockChain, first, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	log.Info("Exporting cluster", "root blocks", last-first+1, "shards", len(shards))
+
+	start, reported := time.Now(), time.Now()
+	for nr := first; nr <= last; nr++ {
+		block, ok := rootChain.GetBlockByNumber(nr).(*types.RootBlock)
+		if !ok {
+			return fmt.Errorf("export failed on root block #%d: not found", nr)
+		}
+		for _, header := range block.MinorBlockHeaders() {
+			shard, ok := shards[header.Branch.Value]
+			if !ok {
+				return fmt.Errorf("export failed on root block #%d: shard %d is not open", nr, header.Branch.Value)
+			}
+			mBlock := shard.GetMinorBlock(header.Hash())
+			if mBlock == nil {
+				return fmt.Errorf("export failed on minor block %d #%d: not found", header.Branch.Value, header.Number)
+			}
+			if err := writeArchiveRecord(w, archiveMinorBlock, mBlock); err != nil {
+				return err
+			}
+		}
+		if err := writeArchiveRecord(w, archiveRootBlock, block); err != nil {
+			return err
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Exporting cluster", "exported", nr-first, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+
+	rootTip := rootChain.CurrentBlock()
+	if last != rootTip.NumberU64() {
+		return nil
+	}
+	for _, id := range sortedShardIDs(shards) {
+		shard := shards[id]
+		from := uint64(0)
+		if confirmed := shard.getLastConfirmedMinorBlockHeaderAtRootBlock(rootTip.Hash()); confirmed != nil {
+			from = confirmed.NumberU64() + 1
+		}
+		for nr := from; nr <= shard.CurrentBlock().NumberU64(); nr++ {
+			mBlock, ok := shard.GetBlockByNumber(nr).(*types.MinorBlock)
+			if !ok {
+				return fmt.Errorf("export failed on minor block %d #%d: not found", id, nr)
+			}
+			if err := writeArchiveRecord(w, archiveMinorBlock, mBlock); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// OpenClusterShards creates with newShard the chains of the shards initialized
+// at the root tip of rootChain, the way slaves do when they start.
+func OpenClusterShards(rootChain *RootBlockChain, newShard NewShardChainFunc) (map[uint32]*MinorBlockChain, error) {
+	imp := &clusterImporter{
+		rootChain: rootChain,
+		shards:    make(map[uint32]*MinorBlockChain),
+		newShard:  newShard,
+	}
+	err := imp.createShards(rootChain.CurrentBlock())
+	return imp.shards, err
+}
+
+// ImportCluster inserts the blocks of a cluster archive read from r into the
+// root chain and the shard chains, with full validation. Shards are created
+// with newShard and added to shards once the root chain reaches their genesis
+// root height, and cross-shard deposits are handed to neighbor shards as
+// slaves do. Blocks already known are skipped, so an interrupted import can
+// be resumed from the same archive.
+func ImportCluster(r io.Reader, rootChain *RootBlockChain, shards map[uint32]*MinorBlockChain, newShard NewShardChainFunc) error {
+	imp := &clusterImporter{
+		rootChain: rootChain,
+		shards:    shards,
+		newShard:  newShard,
+	}
+	rootChain.SetRootChainStakesFunc(imp.getRootChainStakes)
+	if err := imp.createShards(rootChain.CurrentBlock()); err != nil {
+		return err
+	}
+
+	var (
+		start, reported = time.Now(), time.Now()
+		count           int
+	)
+	for {
+		block, err := readArchiveRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("import failed after %d blocks: %v", count, err)
+		}
+		switch b := block.(type) {
+		case *types.RootBlock:
+			if err := imp.addRootBlock(b); err != nil {
+				return fmt.Errorf("import failed on root block #%d [%x]: %v", b.NumberU64(), b.Hash().Bytes()[:4], err)
+			}
+		case *types.MinorBlock:
+			if err := imp.addMinorBlock(b); err != nil {
+				return fmt.Errorf("import failed on minor block %d #%d [%x]: %v", b.Branch().Value, b.NumberU64(), b.Hash().Bytes()[:4], err)
+			}
+		}
+		count++
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing cluster", "imported", count, "root", rootChain.CurrentBlock().NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Imported cluster", "blocks", count, "root", rootChain.CurrentBlock().NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+type clusterImporter struct {
+	rootChain *RootBlockChain
+	shards    map[uint32]*MinorBlockChain
+	newShard  NewShardChainFunc
+}
+
+// createShards creates the shards which are initialized at or before rBlock
+// and are not created yet.
+func (imp *clusterImporter) createShards(rBlock *types.RootBlock) error {
+	cfg := imp.rootChain.Config()
+	ids := cfg.GetGenesisShardIds()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		genesisRootHeight := cfg.GetGenesisRootHeight(id)
+		if _, ok := imp.shards[id]; ok || rBlock.Number() < genesisRootHeight {
+			continue
+		}
+		shard, err := imp.newShard(id, rBlock)
+		if err != nil {
+			return err
+		}
+		imp.shards[id] = shard
+		if rBlock.Number() > genesisRootHeight {
+			if err := shard.InitFromRootBlock(rBlock); err != nil {
+				return err
+			}
+			continue
+		}
+		gBlock, err := shard.InitGenesisState(rBlock)
+		if err != nil {
+			return err
+		}
+		if err := imp.broadcastXShardTxList(gBlock, nil, rBlock.Number()); err != nil {
+			return err
+		}
+		imp.rootChain.AddValidatedMinorBlockHeader(gBlock.Hash(), gBlock.CoinbaseAmount())
+	}
+	return nil
+}
+
+func (imp *clusterImporter) addRootBlock(block *types.RootBlock) error {
+	if !imp.rootChain.HasBlock(block.Hash()) {
+		if _, err := imp.rootChain.InsertChain([]types.IBlock{block}); err != nil {
+			return err
+		}
+	}
+	cfg := imp.rootChain.Config()
+	for id, shard := range imp.shards {
+		if block.Number() <= cfg.GetGenesisRootHeight(id) {
+			continue
+		}
+		if _, err := shard.AddRootBlock(block); err != nil {
+			return err
+		}
+	}
+	return imp.createShards(block)
+}
+
+func (imp *clusterImporter) addMinorBlock(block *types.MinorBlock) error {
+	shard, ok := imp.shards[block.Branch().Value]
+	if !ok {
+		return fmt.Errorf("shard %d is not initialized", block.Branch().Value)
+	}
+	if !shard.HasBlock(block.Hash()) {
+		_, xShardLists, err := shard.InsertChainForDeposits([]types.IBlock{block}, false)
+		if err != nil {
+			return err
+		}
+		if len(xShardLists) == 1 && xShardLists[0] != nil {
+			if err := imp.commitMinorBlock(shard, block, xShardLists[0]); err != nil {
+				return err
+			}
+		}
+	}
+	imp.rootChain.AddValidatedMinorBlockHeader(block.Hash(), block.CoinbaseAmount())
+	return nil
+}
+
+// commitMinorBlock hands the cross-shard deposits of block to the neighbor
+// shards and marks the block as committed in its shard.
+func (imp *clusterImporter) commitMinorBlock(shard *MinorBlockChain, block *types.MinorBlock,
+	deposits []*types.CrossShardTransactionDeposit) error {
+
+	prevRootBlock := shard.GetRootBlockByHash(block.PrevRootBlockHash())
+	if prevRootBlock == nil {
+		return fmt.Errorf("prev root block %x not found", block.PrevRootBlockHash())
+	}
+	if err := imp.broadcastXShardTxList(block, deposits, prevRootBlock.Number()); err != nil {
+		return err
+	}
+	shard.CommitMinorBlockByHash(block.Hash())
+	return nil
+}
+
+func (imp *clusterImporter) broadcastXShardTxList(block *types.MinorBlock,
+	deposits []*types.CrossShardTransactionDeposit, prevRootHeight uint32) error {
+
+	cfg := imp.rootChain.Config()
+	ids := cfg.GetInitializedShardIdsBeforeRootHeight(prevRootHeight)
+	lists := make(map[uint32][]*types.CrossShardTransactionDeposit, len(ids))
+	for _, id := range ids {
+		lists[id] = make([]*types.CrossShardTransactionDeposit, 0)
+	}
+	for _, deposit := range deposits {
+		id, err := cfg.GetFullShardIdByFullShardKey(deposit.To.FullShardKey)
+		if err != nil {
+			return err
+		}
+		if _, ok := lists[id]; !ok {
+			return fmt.Errorf("xshard deposit %x to uninitialized shard %d", deposit.TxHash, id)
+		}
+		lists[id] = append(lists[id], deposit)
+	}
+	for id, list := range lists {
+		branch := account.Branch{Value: id}
+		if branch == block.Branch() || !account.IsNeighbor(block.Branch(), branch, uint32(len(ids))) {
+			if len(list) != 0 {
+				return fmt.Errorf("there shouldn't be xshard list for non-neighbor shard (%d -> %d)", block.Branch().Value, id)
+			}
+			continue
+		}
+		if shard, ok := imp.shards[id]; ok {
+			shard.AddCrossShardTxListByMinorBlockHash(block.Hash(), types.CrossShardTransactionDepositList{TXList: list})
+		}
+	}
+	return nil
+}
+
+// getRootChainStakes is the root chain stakes function backed by chain 0
+// shard 0, used to validate root blocks with PoSW.
+func (imp *clusterImporter) getRootChainStakes(address account.Address, lastMinor common.Hash) (*big.Int,
+	*account.Recipient, error) {
+
+	for _, shard := range imp.shards {
+		if shard.branch.GetChainID() == 0 && shard.branch.GetShardID() == 0 {
+			return shard.GetRootChainStakes(address.Recipient, lastMinor)
+		}
+	}
+	return nil, nil, errors.New("not chain 0 shard 0")
+}
+
+// writeArchiveRecord writes v serialized as a record of the given kind.
+func writeArchiveRecord(w io.Writer, kind byte, v interface{}) error {
+	data, err := serialize.SerializeToBytes(v)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, 5)
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// readRecord reads the kind and the payload of the next record of a stream,
+// returning io.EOF at its end.
+func readRecord(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxArchiveRecordSize {
+		return 0, nil, fmt.Errorf("archive record of %d bytes exceeds the maximum of %d", size, maxArchiveRecordSize)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return header[0], data, nil
+}
+
+// readArchiveRecord reads the next block of an archive, returning io.EOF at
+// its end.
+func readArchiveRecord(r io.Reader) (types.IBlock, error) {
+	kind, data, err := readRecord(r)
+	if err != nil {
+		return nil, err
+	}
+	var block types.IBlock
+	switch kind {
+	case archiveRootBlock:
+		block = new(types.RootBlock)
+	case archiveMinorBlock:
+		block = new(types.MinorBlock)
+	default:
+		return nil, fmt.Errorf("unknown archive record kind %d", kind)
+	}
+	if err := serialize.DeserializeFromBytes(data, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func sortedShardIDs(shards map[uint32]*MinorBlockChain) []uint32 {
+	ids := make([]uint32, 0, len(shards))
+	for id := range shards {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
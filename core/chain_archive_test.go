@@ -0,0 +1,114 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/consensus"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCluster returns an importer over a fresh root chain with every shard
+// in its own database, as master and slaves keep them.
+func newTestCluster(t *testing.T, cfg *config.ClusterConfig) *clusterImporter {
+	rootDb := ethdb.NewMemDatabase()
+	NewGenesis(cfg.Quarkchain).MustCommitRootBlock(rootDb)
+	rootChain, err := NewRootBlockChain(rootDb, cfg.Quarkchain, new(consensus.FakeEngine))
+	assert.NoError(t, err)
+	imp := &clusterImporter{
+		rootChain: rootChain,
+		shards:    make(map[uint32]*MinorBlockChain),
+		newShard: func(fullShardID uint32, rBlock *types.RootBlock) (*MinorBlockChain, error) {
+			db := ethdb.NewMemDatabase()
+			NewGenesis(cfg.Quarkchain).MustCommitMinorBlock(db, rBlock, fullShardID)
+			return NewMinorBlockChain(db, nil, params.TestChainConfig, cfg, new(consensus.FakeEngine), vm.Config{}, nil, fullShardID)
+		},
+	}
+	rootChain.SetRootChainStakesFunc(imp.getRootChainStakes)
+	assert.NoError(t, imp.createShards(rootChain.CurrentBlock()))
+	return imp
+}
+
+// mineTestCluster adds a minor block to every shard and, if confirm is set, a
+// root block confirming all the minor blocks not confirmed yet.
+func mineTestCluster(t *testing.T, imp *clusterImporter, pending map[uint32][]*types.MinorBlockHeader, confirm bool) {
+	for _, id := range sortedShardIDs(imp.shards) {
+		shard := imp.shards[id]
+		block, err := shard.CreateBlockToMine(nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		block, _, err = shard.FinalizeAndAddBlock(block)
+		assert.NoError(t, err)
+		assert.NoError(t, imp.commitMinorBlock(shard, block, nil))
+		imp.rootChain.AddValidatedMinorBlockHeader(block.Hash(), block.CoinbaseAmount())
+		pending[id] = append(pending[id], block.Header())
+	}
+	if !confirm {
+		return
+	}
+
+	var (
+		headers    []*types.MinorBlockHeader
+		createTime = imp.rootChain.CurrentBlock().Time() + 1
+	)
+	for _, id := range sortedShardIDs(imp.shards) {
+		for _, header := range pending[id] {
+			if header.Time > createTime {
+				createTime = header.Time
+			}
+		}
+		headers = append(headers, pending[id]...)
+		delete(pending, id)
+	}
+	rBlock, err := imp.rootChain.CreateBlockToMine(headers, nil, &createTime)
+	assert.NoError(t, err)
+	assert.NoError(t, imp.addRootBlock(rBlock))
+}
+
+func TestExportImportCluster(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	src := newTestCluster(t, env.clusterConfig)
+	assert.Equal(t, 4, len(src.shards))
+
+	pending := make(map[uint32][]*types.MinorBlockHeader)
+	for id, shard := range src.shards {
+		pending[id] = []*types.MinorBlockHeader{shard.CurrentBlock().Header()}
+	}
+	mineTestCluster(t, src, pending, true)
+	mineTestCluster(t, src, pending, true)
+	mineTestCluster(t, src, pending, false)
+	assert.Equal(t, uint64(2), src.rootChain.CurrentBlock().NumberU64())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, ExportCluster(buf, src.rootChain, src.shards, 0, src.rootChain.CurrentBlock().NumberU64()))
+	archive := buf.Bytes()
+
+	dst := newTestCluster(t, env.clusterConfig)
+	assert.NoError(t, ImportCluster(bytes.NewReader(archive), dst.rootChain, dst.shards, dst.newShard))
+	assert.Equal(t, src.rootChain.CurrentBlock().Hash(), dst.rootChain.CurrentBlock().Hash())
+	assert.Equal(t, len(src.shards), len(dst.shards))
+	for id, shard := range src.shards {
+		assert.Equal(t, uint64(3), shard.CurrentBlock().NumberU64())
+		assert.Equal(t, shard.CurrentBlock().Hash(), dst.shards[id].CurrentBlock().Hash())
+		assert.Equal(t, shard.CurrentBlock().GetMetaData().Root, dst.shards[id].CurrentBlock().GetMetaData().Root)
+	}
+
+	// importing again skips the known blocks
+	assert.NoError(t, ImportCluster(bytes.NewReader(archive), dst.rootChain, dst.shards, dst.newShard))
+
+	// a truncated archive fails
+	fresh := newTestCluster(t, env.clusterConfig)
+	err := ImportCluster(bytes.NewReader(archive[:len(archive)-1]), fresh.rootChain, fresh.shards, fresh.newShard)
+	assert.Contains(t, err.Error(), io.ErrUnexpectedEOF.Error())
+}
+
+func TestReadOversizedArchiveRecord(t *testing.T) {
+	// a corrupted length fails without allocating the record
+	_, err := readArchiveRecord(bytes.NewReader([]byte{archiveMinorBlock, 0xff, 0xff, 0xff, 0xff}))
+	assert.Contains(t, err.Error(), "exceeds the maximum")
+}
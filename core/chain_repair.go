@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/QuarkChain/goquarkchain/core/rawdb"
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// readChainHeader reads the header of the root or minor block with the hash
+// from db. Headers are stored with their blocks.
+func readChainHeader(db ethdb.Database, chainType rawdb.ChainType, hash common.Hash) types.IHeader {
+	if chainType == rawdb.ChainTypeRoot {
+		if block := rawdb.ReadRootBlock(db, hash); block != nil {
+			return block.Header()
+		}
+		return nil
+	}
+	if block := rawdb.ReadMinorBlock(db, hash); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+
+// VerifyCanonicalChain checks the canonical index of the root chain or of the
+// shard stored in db between block numbers first and last, and the head block
+// it leads to. Every canonical block must be stored with the right number and
+// link to the previous one. At most limit problems are returned.
+func VerifyCanonicalChain(db ethdb.Database, chainType rawdb.ChainType, first, last uint64, limit int) []error {
+	var problems []error
+	report := func(format string, args ...interface{}) bool {
+		problems = append(problems, fmt.Errorf(format, args...))
+		return len(problems) < limit
+	}
+
+	headHash := rawdb.ReadHeadBlockHash(db)
+	head := readChainHeader(db, chainType, headHash)
+	if head == nil {
+		report("head block %x missing", headHash)
+		return problems
+	}
+	if hash := rawdb.ReadCanonicalHash(db, chainType, head.NumberU64()); hash != headHash {
+		if !report("head block #%d %x is not canonical, %x is", head.NumberU64(), headHash, hash) {
+			return problems
+		}
+	}
+	if hash := rawdb.ReadCanonicalHash(db, chainType, head.NumberU64()+1); hash != (common.Hash{}) {
+		if !report("canonical block #%d %x above the head", head.NumberU64()+1, hash) {
+			return problems
+		}
+	}
+	if last > head.NumberU64() {
+		last = head.NumberU64()
+	}
+
+	var parent common.Hash
+	if first > 0 {
+		parent = rawdb.ReadCanonicalHash(db, chainType, first-1)
+	}
+	for n := first; n <= last; n++ {
+		hash := rawdb.ReadCanonicalHash(db, chainType, n)
+		header := readChainHeader(db, chainType, hash)
+		switch {
+		case hash == (common.Hash{}):
+			if !report("canonical hash of block #%d missing", n) {
+				return problems
+			}
+		case header == nil:
+			if !report("canonical block #%d %x missing", n, hash) {
+				return problems
+			}
+		default:
+			if header.NumberU64() != n {
+				if !report("canonical block #%d %x has number %d", n, hash, header.NumberU64()) {
+					return problems
+				}
+			}
+			if n > 0 && parent != (common.Hash{}) && header.GetParentHash() != parent {
+				if !report("canonical block #%d %x has parent %x instead of %x", n, hash, header.GetParentHash(), parent) {
+					return problems
+				}
+			}
+		}
+		parent = hash
+	}
+	return problems
+}
+
+// RewindMinorChain rewinds the head of the shard stored in db to its canonical
+// block number like MinorBlockChain.SetHead does, deleting the blocks above it.
+// It refuses to go below a minor block confirmed by a root block, which the
+// shard is reset to on start, or to a block whose state is missing. The
+// cluster must be stopped.
+func RewindMinorChain(db ethdb.Database, number uint64) error {
+	head := rawdb.ReadMinorBlock(db, rawdb.ReadHeadBlockHash(db))
+	if head == nil {
+		return fmt.Errorf("head block missing")
+	}
+	if number >= head.NumberU64() {
+		return fmt.Errorf("head block #%d is not above #%d", head.NumberU64(), number)
+	}
+	target := rawdb.ReadMinorBlock(db, rawdb.ReadCanonicalHash(db, rawdb.ChainTypeMinor, number))
+	if target == nil {
+		return fmt.Errorf("canonical block #%d missing", number)
+	}
+	if _, err := state.New(target.Root(), state.NewDatabase(db)); err != nil {
+		return fmt.Errorf("state of block #%d missing: %v", number, err)
+	}
+	confirmed, err := rawdb.ReadLastConfirmedMinorBlockHashes(db)
+	if err != nil {
+		return err
+	}
+	for _, hash := range confirmed {
+		block := rawdb.ReadMinorBlock(db, hash)
+		if block == nil || block.NumberU64() <= number {
+			continue
+		}
+		if rawdb.ReadCanonicalHash(db, rawdb.ChainTypeMinor, block.NumberU64()) == hash {
+			return fmt.Errorf("block #%d %x is confirmed by a root block", block.NumberU64(), hash)
+		}
+	}
+
+	batch := db.NewBatch()
+	for n := head.NumberU64(); n > number; n-- {
+		if hash := rawdb.ReadCanonicalHash(db, rawdb.ChainTypeMinor, n); hash != (common.Hash{}) {
+			rawdb.DeleteMinorBlock(batch, hash)
+		}
+		rawdb.DeleteCanonicalHash(batch, rawdb.ChainTypeMinor, n)
+	}
+	rawdb.WriteHeadBlockHash(batch, target.Hash())
+	return batch.Write()
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/core/rawdb"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyCanonicalChain(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	imp, shard := newPruneTestCluster(t, env.clusterConfig, 3)
+	db := imp.rootChain.db
+	assert.Empty(t, VerifyCanonicalChain(db, rawdb.ChainTypeRoot, 0, math.MaxUint64, 10))
+	assert.Empty(t, VerifyCanonicalChain(shard.db, rawdb.ChainTypeMinor, 0, math.MaxUint64, 10))
+
+	rawdb.DeleteCanonicalHash(db, rawdb.ChainTypeRoot, 1)
+	rawdb.DeleteRootBlock(db, rawdb.ReadCanonicalHash(db, rawdb.ChainTypeRoot, 2))
+	problems := VerifyCanonicalChain(db, rawdb.ChainTypeRoot, 0, math.MaxUint64, 10)
+	assert.Len(t, problems, 2)
+	assert.Len(t, VerifyCanonicalChain(db, rawdb.ChainTypeRoot, 0, math.MaxUint64, 1), 1)
+	assert.Empty(t, VerifyCanonicalChain(db, rawdb.ChainTypeRoot, 3, math.MaxUint64, 10))
+
+	rawdb.WriteCanonicalHash(db, rawdb.ChainTypeRoot, common.Hash{1}, imp.rootChain.CurrentBlock().NumberU64()+1)
+	assert.Len(t, VerifyCanonicalChain(db, rawdb.ChainTypeRoot, 3, math.MaxUint64, 10), 1)
+}
+
+func TestRewindMinorChain(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	imp, shard := newPruneTestCluster(t, env.clusterConfig, 2)
+	for i := 0; i < 3; i++ {
+		mineTestCluster(t, imp, make(map[uint32][]*types.MinorBlockHeader), false)
+	}
+	db := shard.db
+	assert.Equal(t, uint64(5), shard.CurrentBlock().NumberU64())
+
+	assert.Error(t, RewindMinorChain(db, 5))
+	// block 2 is confirmed by the root tip
+	assert.Error(t, RewindMinorChain(db, 1))
+	assert.NoError(t, RewindMinorChain(db, 3))
+
+	head := rawdb.ReadCanonicalHash(db, rawdb.ChainTypeMinor, 3)
+	assert.Equal(t, head, rawdb.ReadHeadBlockHash(db))
+	assert.Equal(t, common.Hash{}, rawdb.ReadCanonicalHash(db, rawdb.ChainTypeMinor, 4))
+	assert.Empty(t, VerifyCanonicalChain(db, rawdb.ChainTypeMinor, 0, math.MaxUint64, 10))
+}
@@ -59,4 +59,5 @@ var (
 	ErrNotNeighbor               = errors.New("is not a neighbor")
 	ErrNotSameRootChain          = errors.New("is not same root chain")
 	ErrPoswOnRootChainIsNotFound = errors.New("PoSW-on-root-chain contract is not found")
+	ErrEthTxNotEnabled           = errors.New("eth tx is not enabled at the block height")
 )
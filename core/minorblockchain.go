@@ -47,6 +47,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -145,6 +146,8 @@ type MinorBlockChain struct {
 	posw                     consensus.PoSWCalculator
 	gasLimit                 *big.Int
 	xShardGasLimit           *big.Int
+	headGauge                metrics.Gauge
+	blockInsertTimer         metrics.Timer
 }
 
 // NewMinorBlockChain returns a fully initialised block chain using information
@@ -169,7 +172,8 @@ func NewMinorBlockChain(
 			TrieCleanLimit: 128,
 			TrieDirtyLimit: 128,
 			TrieTimeLimit:  5 * time.Minute,
-			Disabled:       true,
+			Disabled:       clusterConfig.GCMode != config.GCModePruned,
+			StateRetention: clusterConfig.StateRetention,
 		}
 	}
 	receiptsCache, _ := lru.New(receiptsCacheLimit)
@@ -208,7 +212,9 @@ func NewMinorBlockChain(
 			CheckBlocks: 5,
 			Percentile:  50,
 		},
-		logInfo: fmt.Sprintf("shard:%d", fullShardID),
+		logInfo:          fmt.Sprintf("shard:%d", fullShardID),
+		headGauge:        metrics.GetOrRegisterGauge(fmt.Sprintf("shard/%d/chain/head", fullShardID), nil),
+		blockInsertTimer: metrics.GetOrRegisterTimer(fmt.Sprintf("shard/%d/chain/inserts", fullShardID), nil),
 	}
 	var err error
 	bc.gasLimit, err = bc.clusterConfig.Quarkchain.GasLimit(bc.branch.Value)
@@ -288,6 +294,7 @@ func (m *MinorBlockChain) loadLastState() error {
 	}
 	// Everything seems to be fine, set as the head block
 	m.currentBlock.Store(currentBlock)
+	m.headGauge.Update(int64(currentBlock.NumberU64()))
 
 	return nil
 }
@@ -539,6 +546,7 @@ func (m *MinorBlockChain) insert(block *types.MinorBlock) {
 	rawdb.WriteHeadBlockHash(m.db, block.Hash())
 
 	m.currentBlock.Store(block)
+	m.headGauge.Update(int64(block.NumberU64()))
 }
 
 // Genesis retrieves the chain's genesis block.
@@ -642,6 +650,31 @@ func (m *MinorBlockChain) TrieNode(hash common.Hash) ([]byte, error) {
 	return m.stateCache.TrieDB().Node(hash)
 }
 
+// stateRetention returns the number of recent blocks whose state is kept when
+// the trie write cache is enabled.
+func (m *MinorBlockChain) stateRetention() uint64 {
+	if m.cacheConfig.StateRetention == 0 {
+		return triesInMemory
+	}
+	return m.cacheConfig.StateRetention
+}
+
+// commitCheckpointState writes the state of a minor block confirmed by a root
+// block to disk, so that a pruned node can go back to it after a crash or a
+// reorg. States already written or garbage collected are skipped.
+func (m *MinorBlockChain) commitCheckpointState(header *types.MinorBlockHeader) {
+	if m.cacheConfig.Disabled || header == nil {
+		return
+	}
+	block := m.GetMinorBlock(header.Hash())
+	if block == nil {
+		return
+	}
+	if err := m.stateCache.TrieDB().Commit(block.Root(), false); err != nil {
+		log.Error(m.logInfo, "failed to commit checkpoint state", err, "height", header.Number, "root", block.Root().String())
+	}
+}
+
 func (m *MinorBlockChain) getNeedStoreHeight(rootHash common.Hash, heightDiff []uint64) []uint64 {
 	var (
 		currNumber = m.CurrentBlock().NumberU64()
@@ -653,8 +686,8 @@ func (m *MinorBlockChain) getNeedStoreHeight(rootHash common.Hash, heightDiff []
 		if headerTip.Number >= 1 {
 			heightDiff = append(heightDiff, currNumber-(headerTip.Number-1))
 		}
-		if headerTip.Number >= triesInMemory {
-			heightDiff = append(heightDiff, currNumber-(headerTip.Number-triesInMemory))
+		if headerTip.Number >= m.stateRetention() {
+			heightDiff = append(heightDiff, currNumber-(headerTip.Number-m.stateRetention()))
 		}
 		currBlockNumber := m.CurrentBlock().Number()
 		for index := headerTip.Number; index <= currBlockNumber; index++ {
@@ -688,7 +721,7 @@ func (m *MinorBlockChain) Stop() {
 		triedb := m.stateCache.TrieDB()
 		var (
 			currNumber = m.CurrentBlock().NumberU64()
-			heightDiff = []uint64{0, 1, triesInMemory - 1}
+			heightDiff = []uint64{0, 1, m.stateRetention() - 1}
 		)
 		if m.rootTip != nil {
 			log.Info("need stored tire", "number", m.rootTip.Number)
@@ -847,6 +880,7 @@ func (m *MinorBlockChain) InsertReceiptChain(blockChain []types.IBlock, receiptC
 		rawdb.WriteMinorBlock(batch, block.(*types.MinorBlock))
 		rawdb.WriteReceipts(batch, block.Hash(), receipts)
 		rawdb.WriteBlockContentLookupEntriesWithCrossShardHashList(batch, block, nil)
+		rawdb.WriteEthTxHashes(batch, block.(*types.MinorBlock))
 
 		stats.processed++
 
@@ -925,7 +959,7 @@ func (m *MinorBlockChain) WriteBlockWithState(block *types.MinorBlock, receipts
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		m.triegc.Push(root, -int64(block.NumberU64()))
 
-		if current := block.NumberU64(); current > triesInMemory {
+		if current := block.NumberU64(); current > m.stateRetention() {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
 			var (
 				nodes, imgs = triedb.Size()
@@ -935,10 +969,10 @@ func (m *MinorBlockChain) WriteBlockWithState(block *types.MinorBlock, receipts
 				triedb.Cap(limit - ethdb.IdealBatchSize)
 			}
 			// Find the next state trie we need to commit
-			header := m.GetHeaderByNumber(current - triesInMemory)
-			preBlockInterface := m.GetBlockByNumber(current - triesInMemory)
+			header := m.GetHeaderByNumber(current - m.stateRetention())
+			preBlockInterface := m.GetBlockByNumber(current - m.stateRetention())
 			if qkcCommon.IsNil(preBlockInterface) {
-				log.Error("minorBlock not found", "height", current-triesInMemory)
+				log.Error("minorBlock not found", "height", current-m.stateRetention())
 			}
 			preBlock := preBlockInterface.(*types.MinorBlock)
 			chosen := header.NumberU64()
@@ -947,8 +981,8 @@ func (m *MinorBlockChain) WriteBlockWithState(block *types.MinorBlock, receipts
 			if m.gcproc > m.cacheConfig.TrieTimeLimit {
 				// If we're exceeding limits but haven't reached a large enough memory gap,
 				// warn the user that the system is becoming unstable.
-				if chosen < lastWrite+triesInMemory && m.gcproc >= 2*m.cacheConfig.TrieTimeLimit {
-					log.Info("State in memory for too long, committing", "time", m.gcproc, "allowance", m.cacheConfig.TrieTimeLimit, "optimum", float64(chosen-lastWrite)/triesInMemory)
+				if chosen < lastWrite+m.stateRetention() && m.gcproc >= 2*m.cacheConfig.TrieTimeLimit {
+					log.Info("State in memory for too long, committing", "time", m.gcproc, "allowance", m.cacheConfig.TrieTimeLimit, "optimum", float64(chosen-lastWrite)/float64(m.stateRetention()))
 				}
 				// Flush an entire trie and restart the counters
 				triedb.Commit(preBlock.GetMetaData().Root, true)
@@ -1189,6 +1223,7 @@ func (m *MinorBlockChain) insertChain(chain []types.IBlock, verifySeals bool, is
 		if err != nil {
 			return it.index, events, coalescedLogs, xShardList, err
 		}
+		m.blockInsertTimer.UpdateSince(start)
 		switch status {
 		case CanonStatTy:
 			log.Debug("Inserted new block", "number", mBlock.NumberU64(), "hash", mBlock.Hash(),
@@ -141,6 +141,27 @@ func (m *MinorBlockChain) updateTip(state *state.StateDB, block *types.MinorBloc
 	return updateTip, nil
 }
 
+// validateEthTx checks the fields of a tx mapped from an Ethereum tx that are not covered
+// by its signature, so that it can only be applied in the first shard of the chain it is
+// signed for and in the default token.
+func (m *MinorBlockChain) validateEthTx(evmTx *types.EvmTransaction) error {
+	ethChainID, err := evmTx.EthChainID()
+	if err != nil {
+		return err
+	}
+	if expected := m.clusterConfig.Quarkchain.GetEthChainID(evmTx.FromChainID()); expected == 0 || ethChainID != expected {
+		return types.ErrInvalidEthChainID
+	}
+	if evmTx.FromFullShardKey() != evmTx.FromChainID()<<16 || evmTx.ToFullShardKey() != evmTx.FromFullShardKey() {
+		return errors.New("eth tx must be in the first shard of its chain")
+	}
+	tokenID := m.clusterConfig.Quarkchain.GetDefaultChainTokenID()
+	if evmTx.GasTokenID() != tokenID || evmTx.TransferTokenID() != tokenID {
+		return errors.New("eth tx must use the default token")
+	}
+	return nil
+}
+
 func (m *MinorBlockChain) validateTx(tx *types.Transaction, evmState *state.StateDB, fromAddress *account.Address, gas, xShardGasLimit *uint64) (*types.Transaction, error) {
 	if evmState == nil && fromAddress != nil {
 		return nil, errors.New("validateTx params err")
@@ -176,6 +197,16 @@ func (m *MinorBlockChain) validateTx(tx *types.Transaction, evmState *state.Stat
 	if evmTx.NetworkId() != m.clusterConfig.Quarkchain.NetworkID {
 		return nil, ErrNetWorkID
 	}
+	if evmTx.Version() == types.EthTxVersion {
+		// the state of the tx pool is that of the head block, so that eth txs
+		// are only accepted by the pool once the head reaches the height
+		if !m.clusterConfig.Quarkchain.IsEthTxEnabled(evmState.GetBlockNumber()) {
+			return nil, ErrEthTxNotEnabled
+		}
+		if err := m.validateEthTx(evmTx); err != nil {
+			return nil, err
+		}
+	}
 	if !m.branch.IsInBranch(evmTx.FromFullShardId()) {
 		return nil, ErrBranch
 	}
@@ -545,20 +576,38 @@ func (m *MinorBlockChain) GetStorageAt(recipient account.Recipient, key common.H
 
 // ExecuteTx execute tx
 func (m *MinorBlockChain) ExecuteTx(tx *types.Transaction, fromAddress *account.Address, height *uint64) ([]byte, error) {
+	ret, _, _, err := m.executeTx(tx, fromAddress, height, m.vmConfig)
+	return ret, err
+}
+
+// TraceCall executes tx like ExecuteTx with the given tracer attached to the evm
+func (m *MinorBlockChain) TraceCall(tx *types.Transaction, fromAddress *account.Address, height *uint64, tracer vm.Tracer) ([]byte, uint64, bool, error) {
+	return m.executeTx(tx, fromAddress, height, m.traceVMConfig(tracer))
+}
+
+// traceVMConfig returns the vm config of the chain with the tracer attached
+func (m *MinorBlockChain) traceVMConfig(tracer vm.Tracer) vm.Config {
+	cfg := m.vmConfig
+	cfg.Debug = true
+	cfg.Tracer = tracer
+	return cfg
+}
+
+func (m *MinorBlockChain) executeTx(tx *types.Transaction, fromAddress *account.Address, height *uint64, cfg vm.Config) ([]byte, uint64, bool, error) {
 	if height == nil {
 		temp := m.CurrentBlock().NumberU64()
 		height = &temp
 	}
 	if fromAddress == nil {
-		return nil, errors.New("from address should not empty")
+		return nil, 0, false, errors.New("from address should not empty")
 	}
 	mBlock, ok := m.GetBlockByNumber(*height).(*types.MinorBlock)
 	if !ok {
-		return nil, ErrMinorBlockIsNil
+		return nil, 0, false, ErrMinorBlockIsNil
 	}
 	evmState, err := m.stateAtWithSenderDisallowMap(mBlock, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, false, err
 	}
 	state := evmState.Copy()
 	state.SetGasUsed(new(big.Int).SetUint64(0))
@@ -570,7 +619,7 @@ func (m *MinorBlockChain) ExecuteTx(tx *types.Transaction, fromAddress *account.
 	}
 	evmTx, err := m.validateTx(tx, state, fromAddress, &gas, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, false, err
 	}
 	gp := new(GasPool).AddGas(mBlock.GasLimit().Uint64())
 
@@ -583,10 +632,97 @@ func (m *MinorBlockChain) ExecuteTx(tx *types.Transaction, fromAddress *account.
 	state.SetQuarkChainConfig(m.clusterConfig.Quarkchain)
 
 	context := NewEVMContext(msg, m.CurrentBlock().IHeader().(*types.MinorBlockHeader), m)
-	evmEnv := vm.NewEVM(context, state, m.ethChainConfig, m.vmConfig)
-	ret, _, _, err := ApplyMessage(evmEnv, msg, gp)
-	return ret, err
+	evmEnv := vm.NewEVM(context, state, m.ethChainConfig, cfg)
+	return ApplyMessage(evmEnv, msg, gp)
+}
+
+// TraceTransaction replays the tx with the given hash against the parent state of its block:
+// xshard deposits and the txs before it are re-applied first, then the tx itself is applied
+// with the given tracer attached to the evm
+func (m *MinorBlockChain) TraceTransaction(hash common.Hash, tracer vm.Tracer) ([]byte, uint64, bool, error) {
+	_, mHash, txIndex := rawdb.ReadTransaction(m.db, hash)
+	if mHash == qkcCommon.EmptyHash {
+		return nil, 0, false, fmt.Errorf("transaction %v not found", hash.String())
+	}
+	block := m.GetMinorBlock(mHash)
+	if block == nil {
+		return nil, 0, false, ErrMinorBlockIsNil
+	}
+	if int(txIndex) >= len(block.GetTransactions()) {
+		return nil, 0, false, fmt.Errorf("transaction %v is not an in-shard tx of block %v", hash.String(), mHash.String())
+	}
+
+	evmState, err := m.traceState(block, m.vmConfig)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	evmState.SetQuarkChainConfig(m.clusterConfig.Quarkchain)
+	evmState.SetBlockCoinbase(block.Coinbase().Recipient)
+	evmState.SetGasLimit(block.GasLimit())
+	var (
+		usedGas = new(uint64)
+		header  = block.IHeader()
+		gp      = new(GasPool).AddGas(block.GasLimit().Uint64())
+		xGas    = block.GetXShardGasLimit().Uint64()
+	)
+	for i, tx := range block.GetTransactions()[:txIndex+1] {
+		evmTx, err := m.validateTx(tx, evmState, nil, nil, &xGas)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		evmState.Prepare(tx.Hash(), block.Hash(), i)
+		cfg := m.vmConfig
+		if uint32(i) == txIndex {
+			cfg = m.traceVMConfig(tracer)
+		}
+		ret, receipt, gas, err := ApplyTransaction(m.ethChainConfig, m, gp, evmState, header, evmTx, usedGas, cfg)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		if uint32(i) == txIndex {
+			return ret, gas, receipt.Status == types.ReceiptStatusFailed, nil
+		}
+	}
+	return nil, 0, false, fmt.Errorf("transaction %v not found in block %v", hash.String(), mHash.String())
+}
+
+// TraceBlock re-executes the block with the given hash against its parent state like runBlock,
+// with the tracer attached to the evm of both the xshard deposits and the txs of the block.
+// The resulting evm state is returned so that it can be compared with the block.
+func (m *MinorBlockChain) TraceBlock(hash common.Hash, tracer vm.Tracer) (*types.MinorBlock, *state.StateDB, error) {
+	block := m.GetMinorBlock(hash)
+	if block == nil {
+		return nil, nil, ErrMinorBlockIsNil
+	}
+	cfg := m.traceVMConfig(tracer)
+	evmState, err := m.traceState(block, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, _, _, err := m.processor.Process(block, evmState, cfg); err != nil {
+		return nil, nil, err
+	}
+	return block, evmState, nil
+}
 
+// traceState returns the parent state of the block with its xshard deposits applied
+// with cfg like runBlock, on which the txs of the block are replayed for tracing
+func (m *MinorBlockChain) traceState(block *types.MinorBlock, cfg vm.Config) (*state.StateDB, error) {
+	evmState, err := m.getEvmStateForNewBlock(block.Header(), true)
+	if err != nil {
+		return nil, err
+	}
+	_, txCursorInfo, _, err := m.runCrossShardTxWithCursor(evmState, block, cfg)
+	if err != nil {
+		return nil, err
+	}
+	evmState.SetTxCursorInfo(txCursorInfo)
+	xShardGasLimit := block.GetXShardGasLimit()
+	if evmState.GetGasUsed().Cmp(xShardGasLimit) == -1 {
+		left := new(big.Int).Sub(xShardGasLimit, evmState.GetGasUsed())
+		evmState.SetGasLimit(new(big.Int).Sub(evmState.GetGasLimit(), left))
+	}
+	return evmState, nil
 }
 
 func checkEqual(a, b types.IBlock) bool {
@@ -840,6 +976,17 @@ func (m *MinorBlockChain) AddCrossShardTxListByMinorBlockHash(h common.Hash, txL
 	rawdb.WriteCrossShardTxList(m.db, h, txList)
 }
 
+// needCrossShardTxList checks if the shard keeps the xshard tx list of a minor
+// block of another shard confirmed by a root block.
+func (m *MinorBlockChain) needCrossShardTxList(mHeader *types.MinorBlockHeader) bool {
+	prevRootHeader := m.GetRootBlockByHash(mHeader.PrevRootBlockHash)
+	// prev_root_header can be None when the shard is not created at root height 0
+	if prevRootHeader == nil || prevRootHeader.Number() == uint32(m.clusterConfig.Quarkchain.GetGenesisRootHeight(m.branch.Value)) {
+		return false
+	}
+	return m.isNeighbor(mHeader.Branch, &prevRootHeader.Header().Number)
+}
+
 // AddRootBlock add root block for minorBlockChain
 func (m *MinorBlockChain) AddRootBlock(rBlock *types.RootBlock) (bool, error) {
 	if rBlock.Number() <= uint32(m.clusterConfig.Quarkchain.GetGenesisRootHeight(m.branch.Value)) {
@@ -871,10 +1018,7 @@ func (m *MinorBlockChain) AddRootBlock(rBlock *types.RootBlock) (bool, error) {
 			shardHeaders = append(shardHeaders, mHeader)
 			continue
 		}
-		prevRootHeader := m.GetRootBlockByHash(mHeader.PrevRootBlockHash)
-
-		// prev_root_header can be None when the shard is not created at root height 0
-		if prevRootHeader == nil || prevRootHeader.Number() == uint32(m.clusterConfig.Quarkchain.GetGenesisRootHeight(m.branch.Value)) || !m.isNeighbor(mHeader.Branch, &prevRootHeader.Header().Number) {
+		if !m.needCrossShardTxList(mHeader) {
 			if data := m.ReadCrossShardTxList(h); data != nil {
 				errXshardListAlreadyHave := errors.New("already have")
 				log.Error(m.logInfo, "addrootBlock err-1", errXshardListAlreadyHave)
@@ -883,6 +1027,10 @@ func (m *MinorBlockChain) AddRootBlock(rBlock *types.RootBlock) (bool, error) {
 			continue
 		}
 
+		// the lists are downloaded from peers once the state sync pivot is activated
+		if m.isStateSyncPending() {
+			continue
+		}
 		if data := m.ReadCrossShardTxList(h); data == nil {
 			errXshardListNotHave := errors.New("not have")
 			log.Error(m.logInfo, "addrootBlock err-2", errXshardListNotHave, "h", h.String())
@@ -927,6 +1075,7 @@ func (m *MinorBlockChain) AddRootBlock(rBlock *types.RootBlock) (bool, error) {
 	m.rootTip = rBlock.Header()
 	m.confirmedHeaderTip = shardHeader
 	m.mu.Unlock()
+	m.commitCheckpointState(shardHeader)
 	origHeaderTip := m.CurrentBlock()
 	if shardHeader != nil {
 		origBlock := m.GetBlockByNumber(shardHeader.Number)
@@ -982,8 +1131,9 @@ func (m *MinorBlockChain) AddRootBlock(rBlock *types.RootBlock) (bool, error) {
 	return true, nil
 }
 
-// GetTransactionByHash get tx by hash
+// GetTransactionByHash get tx by hash, or by the hash of the Ethereum tx it is mapped from
 func (m *MinorBlockChain) GetTransactionByHash(hash common.Hash) (*types.MinorBlock, uint32) {
+	hash = m.txHashByEthHash(hash)
 	_, mHash, txIndex := rawdb.ReadTransaction(m.db, hash)
 	if mHash == qkcCommon.EmptyHash { //TODO need? for test???
 		tx := m.txPool.all.Get(hash)
@@ -997,8 +1147,21 @@ func (m *MinorBlockChain) GetTransactionByHash(hash common.Hash) (*types.MinorBl
 	return m.GetMinorBlock(mHash), txIndex
 }
 
-// GetTransactionReceipt get tx receipt by hash for slave
+// txHashByEthHash returns the hash of the pending or included tx mapped from the
+// Ethereum tx of hash, or hash itself if it is not the hash of such a tx.
+func (m *MinorBlockChain) txHashByEthHash(hash common.Hash) common.Hash {
+	if tx := m.txPool.all.GetByEthHash(hash); tx != nil {
+		return tx.Hash()
+	}
+	if txHash := rawdb.ReadTxHashByEthHash(m.db, hash); txHash != (common.Hash{}) {
+		return txHash
+	}
+	return hash
+}
+
+// GetTransactionReceipt get tx receipt by hash, or by the hash of the Ethereum tx it is mapped from, for slave
 func (m *MinorBlockChain) GetTransactionReceipt(hash common.Hash) (*types.MinorBlock, uint32, *types.Receipt) {
+	hash = m.txHashByEthHash(hash)
 	block, index := m.GetTransactionByHash(hash)
 	if block == nil {
 		return nil, 0, nil
@@ -1012,6 +1175,89 @@ func (m *MinorBlockChain) GetTransactionReceipt(hash common.Hash) (*types.MinorB
 	return nil, 0, nil
 }
 
+// GetXShardDepositBlock returns the canonical block which applies the xshard
+// deposit of the tx txHash of the minor block mHash confirmed by the root block
+// rHash, or nil if the deposit is not applied yet.
+func (m *MinorBlockChain) GetXShardDepositBlock(rHash, mHash, txHash common.Hash) (*types.MinorBlock, error) {
+	rBlock := m.GetRootBlockByHash(rHash)
+	if rBlock == nil {
+		// the root block is not added to the shard yet
+		return nil, nil
+	}
+	mBlockIndex := -1
+	for i, header := range rBlock.MinorBlockHeaders() {
+		if header.Hash() == mHash {
+			mBlockIndex = i + 1
+			break
+		}
+	}
+	if mBlockIndex < 0 {
+		return nil, errors.New("minor block not confirmed by root block")
+	}
+	xShardDepositIndex := -1
+	if xTxList := m.ReadCrossShardTxList(mHash); xTxList != nil {
+		for i, deposit := range xTxList.TXList {
+			if deposit.TxHash == txHash {
+				xShardDepositIndex = i
+				break
+			}
+		}
+	}
+	if xShardDepositIndex < 0 {
+		return nil, errors.New("xshard deposit not found")
+	}
+	cursor := &types.XShardTxCursorInfo{
+		RootBlockHeight:    rBlock.NumberU64(),
+		MinorBlockIndex:    uint64(mBlockIndex),
+		XShardDepositIndex: uint64(xShardDepositIndex),
+	}
+
+	// the cursors of the canonical blocks never go backwards, so the deposit is
+	// applied by the first block whose cursor reaches it
+	reached := func(number uint64) bool {
+		block, ok := m.GetBlockByNumber(number).(*types.MinorBlock)
+		return ok && !isXShardTxCursorBefore(block.Meta().XShardTxCursorInfo, cursor)
+	}
+	tip := m.CurrentBlock().NumberU64()
+	if !reached(tip) {
+		return nil, nil
+	}
+	number := sort.Search(int(tip), func(i int) bool { return reached(uint64(i)) })
+	return m.GetBlockByNumber(uint64(number)).(*types.MinorBlock), nil
+}
+
+// isXShardTxCursorBefore reports whether the cursor a is before the cursor b.
+func isXShardTxCursorBefore(a, b *types.XShardTxCursorInfo) bool {
+	if a.RootBlockHeight != b.RootBlockHeight {
+		return a.RootBlockHeight < b.RootBlockHeight
+	}
+	if a.MinorBlockIndex != b.MinorBlockIndex {
+		return a.MinorBlockIndex < b.MinorBlockIndex
+	}
+	return a.XShardDepositIndex < b.XShardDepositIndex
+}
+
+// GetXShardDepositReceipt returns the receipt of the xshard deposit of the tx
+// txHash of another shard, with the canonical block which applied it and its
+// index in the receipts of the block. Deposits are looked up by the hash of
+// their tx, and have no receipt if applied before evm is enabled.
+func (m *MinorBlockChain) GetXShardDepositReceipt(txHash common.Hash) (*types.MinorBlock, uint32, *types.Receipt) {
+	blockHash, index := rawdb.ReadBlockContentLookupEntry(m.db, txHash)
+	if blockHash == (common.Hash{}) {
+		return nil, 0, nil
+	}
+	block := m.GetMinorBlock(blockHash)
+	// the receipts of the deposits follow the receipts of the txs of the block
+	if block == nil || int(index) < len(block.Transactions()) {
+		return nil, 0, nil
+	}
+	receipts := m.GetReceiptsByHash(blockHash)
+	if int(index) >= len(receipts) || receipts[index].TxHash != txHash {
+		return nil, 0, nil
+	}
+	return block, index, receipts[index]
+}
+
 // GetShardStats show shardStatus
 func (m *MinorBlockChain) GetShardStats() (*rpc.ShardStatus, error) {
 	// getBlockCountByHeight have lock
@@ -1062,6 +1308,17 @@ func (m *MinorBlockChain) GetPendingCount() int {
 	return m.txPool.PendingCount()
 }
 
+// GetTxPoolContent returns the pending and the queued txs of the tx pool,
+// grouped by sender and sorted by nonce.
+func (m *MinorBlockChain) GetTxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return m.txPool.Content()
+}
+
+// GetTxPoolStats returns the number of pending and of queued txs of the tx pool.
+func (m *MinorBlockChain) GetTxPoolStats() (int, int) {
+	return m.txPool.Stats()
+}
+
 // EstimateGas estimate gas for this tx
 func (m *MinorBlockChain) EstimateGas(tx *types.Transaction, fromAddress account.Address) (uint32, error) {
 	// no need to locks
@@ -1140,8 +1397,35 @@ func (m *MinorBlockChain) EstimateGas(tx *types.Transaction, fromAddress account
 
 // GasPrice gas price
 func (m *MinorBlockChain) GasPrice(tokenID uint64) (uint64, error) {
+	price, observed, err := m.suggestGasPrice(tokenID)
+	if err != nil {
+		return 0, err
+	}
+	if !observed {
+		return m.clusterConfig.Quarkchain.MinTXPoolGasPrice.Uint64(), nil
+	}
+	return price, nil
+}
+
+// GasPriceRate returns the gas price suggested for the token like GasPrice, which
+// gas prices in different tokens are compared at, but fails instead of falling back
+// to MinTXPoolGasPrice if no tx of the recent blocks paid gas in the token.
+func (m *MinorBlockChain) GasPriceRate(tokenID uint64) (uint64, error) {
+	price, observed, err := m.suggestGasPrice(tokenID)
+	if err != nil {
+		return 0, err
+	}
+	if !observed {
+		return 0, fmt.Errorf("no gas price paid in token %v in recent blocks", tokenID)
+	}
+	return price, nil
+}
+
+// suggestGasPrice returns the percentile of the gas prices paid in the token in
+// the recent blocks, and whether any tx of the recent blocks paid gas in it.
+func (m *MinorBlockChain) suggestGasPrice(tokenID uint64) (uint64, bool, error) {
 	if !m.clusterConfig.Quarkchain.IsAllowedTokenID(tokenID) {
-		return 0, fmt.Errorf("no support tokenID %v", tokenID)
+		return 0, false, fmt.Errorf("no support tokenID %v", tokenID)
 	}
 
 	currHead := m.CurrentBlock().Hash()
@@ -1149,7 +1433,7 @@ func (m *MinorBlockChain) GasPrice(tokenID uint64) (uint64, error) {
 		currHead: currHead,
 		tokenID:  tokenID,
 	}); ok {
-		return data.(uint64), nil
+		return data.(uint64), true, nil
 	}
 
 	currHeight := m.CurrentBlock().NumberU64()
@@ -1162,7 +1446,7 @@ func (m *MinorBlockChain) GasPrice(tokenID uint64) (uint64, error) {
 		block, ok := m.GetBlockByNumber(uint64(index)).(*types.MinorBlock)
 		if !ok {
 			log.Error(m.logInfo, "failed to get block", index)
-			return 0, errors.New("failed to get block")
+			return 0, false, errors.New("failed to get block")
 		}
 		tempPreBlockPrices := make([]uint64, 0)
 		for _, tx := range block.GetTransactions() {
@@ -1173,7 +1457,7 @@ func (m *MinorBlockChain) GasPrice(tokenID uint64) (uint64, error) {
 		prices = append(prices, tempPreBlockPrices...)
 	}
 	if len(prices) == 0 {
-		return m.clusterConfig.Quarkchain.MinTXPoolGasPrice.Uint64(), nil
+		return 0, false, nil
 	}
 
 	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
@@ -1182,7 +1466,7 @@ func (m *MinorBlockChain) GasPrice(tokenID uint64) (uint64, error) {
 		currHead: currHead,
 		tokenID:  tokenID,
 	}, price)
-	return price, nil
+	return price, true, nil
 }
 
 func (m *MinorBlockChain) getBlockCountByHeight(height uint64) uint64 {
@@ -1286,6 +1570,7 @@ func (m *MinorBlockChain) putTxIndexFromBlock(batch rawdb.DatabaseWriter, block
 	if !ok {
 		return errors.New("minor block is nil")
 	}
+	rawdb.WriteEthTxHashes(batch, minorBlock)
 	for index, tx := range minorBlock.Transactions() { // put qkc's inshard tx
 		if err := m.putTxHistoryIndex(tx, minorBlock.Number(), index); err != nil {
 			return err
@@ -1367,11 +1652,6 @@ func (m *MinorBlockChain) getPendingTxByAddress(address account.Address, transfe
 }
 
 func (m *MinorBlockChain) getTransactionDetails(start, end []byte, limit uint32, getTxType GetTxDetailType, skipCoinbaseRewards bool, transferTokenID *uint64) ([]*rpc.TransactionDetail, []byte, error) {
-	qkcDB, ok := m.db.(*qkcdb.RDBDatabase)
-	if !ok {
-		return nil, nil, errors.New("only support qkcdb now")
-	}
-
 	skipXShard := func(xShardTx *types.CrossShardTransactionDeposit) bool {
 		if xShardTx.IsFromRootChain {
 			return skipCoinbaseRewards || xShardTx.Value.Value.Uint64() == 0
@@ -1386,7 +1666,6 @@ func (m *MinorBlockChain) getTransactionDetails(start, end []byte, limit uint32,
 	var (
 		next       = end
 		txList     = make([]*rpc.TransactionDetail, 0)
-		it         = qkcDB.NewIterator()
 		height     uint64
 		crossShard bool
 		err        error
@@ -1394,30 +1673,28 @@ func (m *MinorBlockChain) getTransactionDetails(start, end []byte, limit uint32,
 		txHashes   = make(map[common.Hash]struct{})
 	)
 
-	it.SeekForPrev(start)
-	for it.Valid() {
-		if bytes.Compare(it.Key().Data(), end) < 0 {
-			break
-		}
+	iterErr := qkcdb.IterateReverse(m.db, start, end, func(key, value []byte) bool {
 		if getTxType == GetTxFromAddress {
-			height, crossShard, index, err = decodeTxKey(it.Key().Data(), len(addressTxKey), 20)
+			height, crossShard, index, err = decodeTxKey(key, len(addressTxKey), 20)
 		} else if getTxType == GetAllTransaction {
-			height, crossShard, index, err = decodeTxKey(it.Key().Data(), len(allTxKey), 0)
+			height, crossShard, index, err = decodeTxKey(key, len(allTxKey), 0)
 		} else {
-			return nil, nil, errors.New("not support yet")
+			err = errors.New("not support yet")
 		}
 		if err != nil {
-			return nil, nil, err
+			return false
 		}
 		mBlock, ok := m.GetBlockByNumber(height).(*types.MinorBlock)
 		if !ok {
 			log.Error(m.logInfo, "get minor block fialed height", height)
-			return nil, nil, errors.New("get minBlock failed")
+			err = errors.New("get minBlock failed")
+			return false
 		}
 		if crossShard {
 			xShardReceiveTxList := rawdb.ReadConfirmedCrossShardTxList(m.db, mBlock.Hash())
 			if index >= uint32(len(xShardReceiveTxList.TXList)) {
-				return nil, nil, errors.New("tx's index bigger than txs's len ")
+				err = errors.New("tx's index bigger than txs's len ")
+				return false
 			}
 			tx := xShardReceiveTxList.TXList[index]
 			_, ok := txHashes[tx.TxHash]
@@ -1445,9 +1722,10 @@ func (m *MinorBlockChain) getTransactionDetails(start, end []byte, limit uint32,
 			if !ok && !skipTx(evmTx) {
 				limit--
 				receipt, _, _ := rawdb.ReadReceipt(m.db, tx.Hash())
-				sender, err := types.Sender(types.MakeSigner(m.clusterConfig.Quarkchain.NetworkID), evmTx)
+				var sender account.Recipient
+				sender, err = types.Sender(types.MakeSigner(m.clusterConfig.Quarkchain.NetworkID), evmTx)
 				if err != nil {
-					return nil, nil, err
+					return false
 				}
 				toAddr := new(account.Address)
 				if evmTx.To() == nil {
@@ -1480,11 +1758,14 @@ func (m *MinorBlockChain) getTransactionDetails(start, end []byte, limit uint32,
 			}
 
 		}
-		next = bytesSubOne(it.Key().Data())
-		if limit == 0 {
-			break
-		}
-		it.Prev()
+		next = bytesSubOne(key)
+		return limit != 0
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if iterErr != nil {
+		return nil, nil, iterErr
 	}
 	return txList, next, nil
 }
@@ -1616,7 +1897,13 @@ func (m *MinorBlockChain) ReadCrossShardTxList(hash common.Hash) *types.CrossSha
 
 func (m *MinorBlockChain) RunCrossShardTxWithCursor(evmState *state.StateDB,
 	mBlock *types.MinorBlock) ([]*types.CrossShardTransactionDeposit, *types.XShardTxCursorInfo, types.Receipts, error) {
+	return m.runCrossShardTxWithCursor(evmState, mBlock, *m.GetVMConfig())
+}
 
+func (m *MinorBlockChain) runCrossShardTxWithCursor(evmState *state.StateDB, mBlock *types.MinorBlock,
+	cfg vm.Config) ([]*types.CrossShardTransactionDeposit, *types.XShardTxCursorInfo, types.Receipts, error) {
+
+	tracer := getBlockTracer(cfg)
 	preMinorBlock := m.GetMinorBlock(mBlock.ParentHash())
 	if preMinorBlock == nil {
 		return nil, nil, nil, errors.New("no pre block")
@@ -1636,11 +1923,17 @@ func (m *MinorBlockChain) RunCrossShardTxWithCursor(evmState *state.StateDB,
 		}
 		checkIsFromRootChain := cursor.rBlock.Header().NumberU64() >= m.clusterConfig.Quarkchain.XShardGasDDOSFixRootHeight
 		txIndex := 0
+		if tracer != nil {
+			tracer.CaptureXShardDepositStart(xShardDepositTx)
+		}
 		receipt, err := ApplyCrossShardDeposit(m.ethChainConfig, m, mBlock.Header(),
-			*m.GetVMConfig(), evmState, xShardDepositTx, gasUsed, checkIsFromRootChain, txIndex)
+			cfg, evmState, xShardDepositTx, gasUsed, checkIsFromRootChain, txIndex)
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		if tracer != nil {
+			tracer.CaptureTxEnd(receipt)
+		}
 		txIndex++
 		if receipt != nil {
 			receipts = append(receipts, receipt)
@@ -1701,12 +1994,112 @@ func (m *MinorBlockChain) CommitMinorBlockByHash(h common.Hash) {
 	rawdb.WriteCommitMinorBlock(m.db, h)
 }
 
+// InsertBlockWithoutState stores a minor block confirmed by the root chain
+// without executing it. State sync stores the blocks before its pivot this way
+// and downloads the state of the last of them, the pivot, from peers. The head
+// of the chain stays where it is until ActivateStateSyncPivot is called.
+func (m *MinorBlockChain) InsertBlockWithoutState(block *types.MinorBlock) error {
+	if m.HasBlock(block.Hash()) {
+		return nil
+	}
+	parent := m.GetMinorBlock(block.ParentHash())
+	if parent == nil || parent.NumberU64()+1 != block.NumberU64() {
+		return ErrMinorBlockIsNil
+	}
+	if block.Branch().Value != m.branch.Value {
+		return ErrBranch
+	}
+	if block.MetaHash() != block.GetMetaData().Hash() {
+		return ErrMetaHash
+	}
+	if types.CalculateMerkleRoot(block.GetTransactions()) != block.GetMetaData().TxHash {
+		return ErrTxHash
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.putMinorBlock(block, nil); err != nil {
+		return err
+	}
+	rawdb.WriteCanonicalHash(m.db, rawdb.ChainTypeMinor, block.Hash(), block.NumberU64())
+	rawdb.WriteHeadFastBlockHash(m.db, block.Hash())
+	m.CommitMinorBlockByHash(block.Hash())
+	return nil
+}
+
+func (m *MinorBlockChain) isStateSyncPending() bool {
+	return rawdb.ReadHeadFastBlockHash(m.db) != (common.Hash{})
+}
+
+// StateSyncPivot returns the last block stored without state, whose state is
+// to be downloaded, or nil if there is none.
+func (m *MinorBlockChain) StateSyncPivot() *types.MinorBlock {
+	hash := rawdb.ReadHeadFastBlockHash(m.db)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return m.GetMinorBlock(hash)
+}
+
+// CrossShardTxListsToSync returns the hashes of the neighbor minor blocks whose
+// xshard tx lists the blocks after the state sync pivot consume but the shard
+// does not have, as it did not execute the blocks of its neighbors either.
+// These are confirmed by the root blocks from the xshard cursor of the pivot
+// to the root tip.
+func (m *MinorBlockChain) CrossShardTxListsToSync(pivot *types.MinorBlock) []common.Hash {
+	var (
+		hashes = make([]common.Hash, 0)
+		cursor = pivot.GetMetaData().XShardTxCursorInfo
+	)
+	for rBlock := m.GetRootBlockByHash(m.GetRootTip().Hash()); rBlock != nil && rBlock.NumberU64() >= cursor.RootBlockHeight; rBlock = m.GetRootBlockByHash(rBlock.ParentHash()) {
+		for _, mHeader := range rBlock.MinorBlockHeaders() {
+			if mHeader.Branch == m.branch || !m.needCrossShardTxList(mHeader) {
+				continue
+			}
+			if m.ReadCrossShardTxList(mHeader.Hash()) == nil {
+				hashes = append(hashes, mHeader.Hash())
+			}
+		}
+	}
+	return hashes
+}
+
+// ActivateStateSyncPivot sets the head of the chain to the state sync pivot
+// once its state has been downloaded, after which the blocks are executed again.
+func (m *MinorBlockChain) ActivateStateSyncPivot() error {
+	pivot := m.StateSyncPivot()
+	if pivot == nil {
+		return errors.New("no state sync pivot")
+	}
+	evmState, err := m.StateAt(pivot.Root())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.currentEvmState = evmState
+	m.insert(pivot)
+	rawdb.WriteHeadFastBlockHash(m.db, common.Hash{})
+	log.Info(m.logInfo, "state sync pivot activated", pivot.NumberU64(), "hash", pivot.Hash().String())
+	return nil
+}
+
 func (m *MinorBlockChain) GetMiningInfo(address account.Recipient, stake *types.TokenBalances) (mineable, mined uint64, err error) {
 	_, mineable, mined, err = m.posw.GetPoSWInfo(m.CurrentHeader(), stake.GetTokenBalance(m.Config().GetDefaultChainTokenID()), address)
 	return
 }
 
+// AddTxList adds the txs received from peers to txPool. Unlike the txs added by
+// AddTx, they are not local, so they are neither exempt from eviction nor
+// journaled.
 func (m *MinorBlockChain) AddTxList(txs []*types.Transaction) []error {
-	errList := m.txPool.AddLocals(txs)
+	errList := m.txPool.AddRemotesSync(txs)
 	return errList
 }
+
+// StartTxJournal reloads the local txs journaled at path into txPool and keeps
+// journaling the local txs of txPool there.
+func (m *MinorBlockChain) StartTxJournal(path string) {
+	m.txPool.StartJournal(path)
+}
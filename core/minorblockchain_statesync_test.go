@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncTestState downloads the state with the given root from src into dst the
+// way state sync does from peers.
+func syncTestState(t *testing.T, src, dst *MinorBlockChain, root common.Hash) {
+	sched := state.NewStateSync(root, dst.db)
+	for sched.Pending() > 0 {
+		results := make([]trie.SyncResult, 0)
+		for _, hash := range sched.Missing(16) {
+			data, err := src.TrieNode(hash)
+			assert.NoError(t, err)
+			results = append(results, trie.SyncResult{Hash: hash, Data: data})
+		}
+		_, _, err := sched.Process(results)
+		assert.NoError(t, err)
+		_, err = sched.Commit(dst.db)
+		assert.NoError(t, err)
+	}
+}
+
+func TestStateSyncPivot(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	src := newTestCluster(t, env.clusterConfig)
+	pending := make(map[uint32][]*types.MinorBlockHeader)
+	for id, shard := range src.shards {
+		pending[id] = []*types.MinorBlockHeader{shard.CurrentBlock().Header()}
+	}
+	for i := 0; i < 3; i++ {
+		mineTestCluster(t, src, pending, true)
+	}
+
+	// store the minor blocks confirmed by every root block without state
+	dst := newTestCluster(t, env.clusterConfig)
+	for n := uint64(1); n <= src.rootChain.CurrentBlock().NumberU64(); n++ {
+		rBlock := src.rootChain.GetBlockByNumber(n).(*types.RootBlock)
+		for _, header := range rBlock.MinorBlockHeaders() {
+			block := src.shards[header.Branch.Value].GetMinorBlock(header.Hash())
+			assert.NoError(t, dst.shards[header.Branch.Value].InsertBlockWithoutState(block))
+			dst.rootChain.AddValidatedMinorBlockHeader(header.Hash(), header.CoinbaseAmount)
+		}
+		assert.NoError(t, dst.addRootBlock(rBlock))
+	}
+	for id, shard := range dst.shards {
+		pivot := shard.StateSyncPivot()
+		assert.Equal(t, src.shards[id].CurrentBlock().Hash(), pivot.Hash())
+		assert.Equal(t, uint64(0), shard.CurrentBlock().NumberU64())
+		assert.True(t, shard.HasBlock(pivot.Hash()))
+		assert.Error(t, shard.ActivateStateSyncPivot())
+	}
+
+	// download the state and the xshard tx lists, then execute blocks again
+	for id, shard := range dst.shards {
+		pivot := shard.StateSyncPivot()
+		syncTestState(t, src.shards[id], shard, pivot.Root())
+		hashes := shard.CrossShardTxListsToSync(pivot)
+		assert.NotEmpty(t, hashes)
+		for _, hash := range hashes {
+			list := src.shards[id].ReadCrossShardTxList(hash)
+			assert.NotNil(t, list)
+			shard.AddCrossShardTxListByMinorBlockHash(hash, *list)
+		}
+		assert.Empty(t, shard.CrossShardTxListsToSync(pivot))
+		assert.NoError(t, shard.ActivateStateSyncPivot())
+		assert.Nil(t, shard.StateSyncPivot())
+		assert.Equal(t, pivot.Hash(), shard.CurrentBlock().Hash())
+	}
+
+	pending = make(map[uint32][]*types.MinorBlockHeader)
+	mineTestCluster(t, dst, pending, true)
+	mineTestCluster(t, src, make(map[uint32][]*types.MinorBlockHeader), false)
+	for id, shard := range dst.shards {
+		assert.Equal(t, uint64(4), shard.CurrentBlock().NumberU64())
+		assert.Equal(t, src.shards[id].CurrentBlock().Root(), shard.CurrentBlock().Root())
+	}
+}
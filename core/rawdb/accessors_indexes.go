@@ -64,6 +64,30 @@ func DeleteBlockContentLookupEntry(db DatabaseDeleter, hash common.Hash) {
 	db.Delete(lookupKey(hash))
 }
 
+// ReadTxHashByEthHash retrieves the hash of the tx mapped from the Ethereum tx of
+// ethHash, or the empty hash if no such tx is included in a block.
+func ReadTxHashByEthHash(db DatabaseReader, ethHash common.Hash) common.Hash {
+	data, _ := db.Get(ethTxHashKey(ethHash))
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteEthTxHashes stores the hash of every tx of the block mapped from an Ethereum
+// tx under the hash of the Ethereum tx. The entries depend on the txs only, so they
+// are kept when the block is reverted.
+func WriteEthTxHashes(db DatabaseWriter, block *types.MinorBlock) {
+	for _, tx := range block.Transactions() {
+		if tx.EvmTx == nil || tx.EvmTx.Version() != types.EthTxVersion {
+			continue
+		}
+		if err := db.Put(ethTxHashKey(tx.EvmTx.EthHash()), tx.Hash().Bytes()); err != nil {
+			log.Crit("Failed to store eth tx hash", "err", err)
+		}
+	}
+}
+
 // ReadMinorHeader retrieves a specific MinorHeader from the database, along with
 // its added positional metadata.
 func ReadMinorHeaderFromRootBlock(db DatabaseReader, hash common.Hash) (*types.MinorBlockHeader, common.Hash, uint32) {
@@ -0,0 +1,120 @@
+package rawdb
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/QuarkChain/goquarkchain/qkcdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// keyKind is a kind of database entry told apart by the prefix of its key and,
+// when the prefix is shared with another kind, by the length of its key.
+type keyKind struct {
+	name   string
+	prefix []byte
+	size   int // length of the whole key, any if zero
+}
+
+// keyKinds lists the kinds of entries in the schema, longer prefixes first.
+var keyKinds = []keyKind{
+	{"chain config", configPrefix, len(configPrefix) + common.HashLength},
+	{"preimage", preimagePrefix, len(preimagePrefix) + common.HashLength},
+	{"genesis", genesis, len(genesis) + common.HashLength},
+	{"tx history by address", []byte("iaddr"), 0},
+	{"tx history", []byte("iall"), 0},
+	{"minor block count", countMinor, len(countMinor) + 8},
+	{"latest minor headers", latestMHeaderPrefix, len(latestMHeaderPrefix) + common.HashLength},
+	{"total tx count", totalTxKey, len(totalTxKey) + common.HashLength},
+	{"xshard tx list", xShardLists, len(xShardLists) + common.HashLength},
+	{"last confirmed minor", rLastM, len(rLastM) + common.HashLength},
+	{"minor coinbase", mHeader, len(mHeader) + common.HashLength},
+	{"committed minor", commitBlockByHash, len(commitBlockByHash) + common.HashLength},
+	{"root canonical", rootHashPrefix, len(rootHashPrefix) + 8},
+	{"minor canonical", minorHashPrefix, len(minorHashPrefix) + 8},
+	{"bloombits index", BloomBitsIndexPrefix, 0},
+	{"confirmed xshard tx list", xConfirmedShardKey, len(xConfirmedShardKey) + common.HashLength},
+	{"xshard deposit hashes", xsHashList, len(xsHashList) + common.HashLength},
+	{"minor confirmed by root", mConfiredByRoot, 0},
+	{"header", headerPrefix, len(headerPrefix) + common.HashLength},
+	{"header number", headerNumberPrefix, len(headerNumberPrefix) + common.HashLength},
+	{"block", blockPrefix, len(blockPrefix) + common.HashLength},
+	{"receipts", blockReceiptsPrefix, len(blockReceiptsPrefix) + common.HashLength},
+	{"tx lookup", lookupPrefix, len(lookupPrefix) + common.HashLength},
+	{"bloombits", bloomBitsPrefix, len(bloomBitsPrefix) + 2 + 8 + common.HashLength},
+}
+
+// metadataKeys are the single entries tracking the state of the database.
+var metadataKeys = [][]byte{
+	databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, rbCommittingKey, fastTrieProgressKey,
+}
+
+// KeyKind returns the kind of database entry the key belongs to, "state" for
+// the trie nodes and contract code of the state, and "unknown" if the key is
+// not part of the schema.
+func KeyKind(key []byte) string {
+	for _, kind := range keyKinds {
+		if bytes.HasPrefix(key, kind.prefix) && (kind.size == 0 || len(key) == kind.size) {
+			return kind.name
+		}
+	}
+	for _, meta := range metadataKeys {
+		if bytes.Equal(key, meta) {
+			return "metadata"
+		}
+	}
+	if len(key) == common.HashLength {
+		return "state"
+	}
+	return "unknown"
+}
+
+// KeyStats is the number and total size of the entries of a kind.
+type KeyStats struct {
+	Kind  string
+	Count int
+	Size  common.StorageSize
+}
+
+// InspectDatabase iterates over the whole database and returns the number and
+// size of its entries by kind, largest first.
+func InspectDatabase(db ethdb.Database) ([]*KeyStats, error) {
+	stats := make(map[string]*KeyStats)
+	err := qkcdb.Iterate(db, nil, func(key, value []byte) bool {
+		kind := KeyKind(key)
+		if stats[kind] == nil {
+			stats[kind] = &KeyStats{Kind: kind}
+		}
+		stats[kind].Count++
+		stats[kind].Size += common.StorageSize(len(key) + len(value))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*KeyStats, 0, len(stats))
+	for _, s := range stats {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Size != list[j].Size {
+			return list[i].Size > list[j].Size
+		}
+		return list[i].Kind < list[j].Kind
+	})
+	return list, nil
+}
+
+// ReadLastConfirmedMinorBlockHashes returns the hashes of the minor blocks
+// written as the last one confirmed by a root block.
+func ReadLastConfirmedMinorBlockHashes(db ethdb.Database) ([]common.Hash, error) {
+	hashes := make([]common.Hash, 0)
+	err := qkcdb.Iterate(db, rLastM, func(key, value []byte) bool {
+		if len(key) == len(rLastM)+common.HashLength && len(value) == common.HashLength {
+			hashes = append(hashes, common.BytesToHash(value))
+		}
+		return true
+	})
+	return hashes, err
+}
@@ -0,0 +1,67 @@
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestKeyKind(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	tests := []struct {
+		key  []byte
+		kind string
+	}{
+		{headerKey(hash), "header"},
+		{headerNumberKey(hash), "header number"},
+		{latestMHeaderKey(hash), "latest minor headers"},
+		{lookupKey(common.BytesToHash(append([]byte("mh"), make([]byte, 30)...))), "tx lookup"},
+		{headerHashKey(ChainTypeRoot, 1), "root canonical"},
+		{headerHashKey(ChainTypeMinor, 1), "minor canonical"},
+		{blockReceiptsKey(common.BytesToHash(append([]byte("n"), make([]byte, 31)...))), "receipts"},
+		{makeRLastMHash(hash), "last confirmed minor"},
+		{makeMinorCount(1, 2), "minor block count"},
+		{headBlockKey, "metadata"},
+		{crypto.Keccak256([]byte{1}), "state"},
+		{[]byte("something"), "unknown"},
+	}
+	for _, test := range tests {
+		if kind := KeyKind(test.key); kind != test.kind {
+			t.Errorf("key %x: kind mismatch: have %s, want %s", test.key, kind, test.kind)
+		}
+	}
+}
+
+func TestInspectDatabase(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	WriteMinorBlockHeader(db, header1)
+	WriteMinorBlockHeader(db, header2)
+	WriteCanonicalHash(db, ChainTypeMinor, header1.Hash(), header1.Number)
+	WriteLastConfirmedMinorBlockHeaderAtRootBlock(db, common.Hash{1}, header1.Hash())
+	WriteLastConfirmedMinorBlockHeaderAtRootBlock(db, common.Hash{2}, header2.Hash())
+
+	stats, err := InspectDatabase(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, s := range stats {
+		counts[s.Kind] = s.Count
+	}
+	want := map[string]int{"header": 2, "header number": 2, "minor canonical": 1, "last confirmed minor": 2}
+	for kind, count := range want {
+		if counts[kind] != count {
+			t.Errorf("%s: count mismatch: have %d, want %d", kind, counts[kind], count)
+		}
+	}
+
+	hashes, err := ReadLastConfirmedMinorBlockHashes(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 {
+		t.Errorf("last confirmed minor blocks mismatch: have %d, want 2", len(hashes))
+	}
+}
@@ -35,8 +35,9 @@ var (
 	blockPrefix         = []byte("b") // blockPrefix + hash -> block rootBlockBody
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 
-	lookupPrefix    = []byte("l") // lookupPrefix + hash -> transaction/receipt lookup metadata
-	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	lookupPrefix    = []byte("l")  // lookupPrefix + hash -> transaction/receipt lookup metadata
+	ethTxHashPrefix = []byte("eh") // ethTxHashPrefix + eth tx hash -> tx hash
+	bloomBitsPrefix = []byte("B")  // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
@@ -122,6 +123,11 @@ func lookupKey(hash common.Hash) []byte {
 	return append(lookupPrefix, hash.Bytes()...)
 }
 
+// ethTxHashKey = ethTxHashPrefix + eth tx hash
+func ethTxHashKey(ethHash common.Hash) []byte {
+	return append(ethTxHashPrefix, ethHash.Bytes()...)
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
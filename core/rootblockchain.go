@@ -30,11 +30,15 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	lru "github.com/hashicorp/golang-lru"
 )
 
 var (
 	ErrNoGenesis = errors.New("Genesis not found in chain")
+
+	rootHeadGauge        = metrics.NewRegisteredGauge("root/chain/head", nil)
+	rootBlockInsertTimer = metrics.NewRegisteredTimer("root/chain/inserts", nil)
 )
 
 const (
@@ -53,6 +57,7 @@ type CacheConfig struct {
 	TrieCleanLimit int           // Memory allowance (MB) to use for caching trie nodes in memory
 	TrieDirtyLimit int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieTimeLimit  time.Duration // Time limit after which to flush the current in-memory trie to disk
+	StateRetention uint64        // Number of recent blocks whose state is kept, triesInMemory if zero
 }
 
 // RootBlockChain represents the canonical chain given a database with a genesis
@@ -185,6 +190,7 @@ func (bc *RootBlockChain) loadLastState() error {
 	}
 	// Everything seems to be fine, set as the head block
 	bc.currentBlock.Store(currentBlock)
+	rootHeadGauge.Update(int64(currentBlock.NumberU64()))
 	return nil
 }
 
@@ -328,6 +334,7 @@ func (bc *RootBlockChain) insert(block *types.RootBlock) {
 
 	rawdb.WriteHeadBlockHash(bc.db, block.Hash())
 	bc.currentBlock.Store(block)
+	rootHeadGauge.Update(int64(block.NumberU64()))
 }
 
 // Genesis retrieves the chain's genesis block.
@@ -654,6 +661,7 @@ func (bc *RootBlockChain) insertChain(chain []types.IBlock, verifySeals bool) (i
 		if err != nil {
 			return it.index, events, err
 		}
+		rootBlockInsertTimer.UpdateSince(start)
 		switch status {
 		case CanonStatTy:
 			log.Debug("Inserted new block", "number", block.NumberU64(), "hash", block.Hash(),
@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"math/big"
@@ -17,12 +18,16 @@ import (
 	"github.com/QuarkChain/goquarkchain/core/rawdb"
 	"github.com/QuarkChain/goquarkchain/core/types"
 	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/QuarkChain/goquarkchain/core/vm/tracers"
 	"github.com/QuarkChain/goquarkchain/params"
 	"github.com/QuarkChain/goquarkchain/serialize"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	ethParams "github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -213,6 +218,82 @@ func TestGasPrice(t *testing.T) {
 
 }
 
+func TestReplaceTxAcrossGasTokens(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	checkErr(err)
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	testGenesisMinorTokenBalance = map[string]*big.Int{
+		"QKC": new(big.Int).SetUint64(100000000),
+		"QI":  new(big.Int).SetUint64(100000000),
+	}
+	defer func() {
+		testGenesisMinorTokenBalance = make(map[string]*big.Int)
+	}()
+	fakeData := uint64(100000000)
+	env := setUp(&acc1, &fakeData, nil)
+	shardState := createDefaultShardState(env, nil, nil, nil, nil)
+	defer shardState.Stop()
+
+	qkcToken := qkcCommon.TokenIDEncode("QKC")
+	qiToken := qkcCommon.TokenIDEncode("QI")
+	// suggested gas prices, a unit of gas costs twice as many QI as QKC
+	head := shardState.CurrentBlock().Hash()
+	shardState.gasPriceSuggestionOracle.cache.Add(gasPriceKey{currHead: head, tokenID: qkcToken}, uint64(10))
+	shardState.gasPriceSuggestionOracle.cache.Add(gasPriceKey{currHead: head, tokenID: qiToken}, uint64(20))
+
+	newTx := func(gasPrice, gasToken uint64) *types.Transaction {
+		nonce := uint64(0)
+		return createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, acc1, new(big.Int), nil, &gasPrice, &nonce, nil, &gasToken, &gasToken)
+	}
+	assert.NoError(t, shardState.AddTx(newTx(100, qkcToken)))
+	assert.Equal(t, ErrReplaceUnderpriced, shardState.AddTx(newTx(219, qiToken)))
+	assert.NoError(t, shardState.AddTx(newTx(220, qiToken)))
+	assert.Equal(t, ErrReplaceUnderpriced, shardState.AddTx(newTx(120, qkcToken)))
+	assert.NoError(t, shardState.AddTx(newTx(121, qkcToken)))
+
+	pending, _ := shardState.GetTxPoolContent()
+	txs := pending[acc1.Recipient]
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, qkcToken, txs[0].EvmTx.GasTokenID())
+	assert.Equal(t, uint64(121), txs[0].EvmTx.GasPrice().Uint64())
+}
+
+func TestReplaceTxWithoutGasPriceHistory(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	checkErr(err)
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	testGenesisMinorTokenBalance = map[string]*big.Int{
+		"QKC": new(big.Int).SetUint64(100000000),
+		"QI":  new(big.Int).SetUint64(100000000),
+	}
+	defer func() {
+		testGenesisMinorTokenBalance = make(map[string]*big.Int)
+	}()
+	fakeData := uint64(100000000)
+	env := setUp(&acc1, &fakeData, nil)
+	shardState := createDefaultShardState(env, nil, nil, nil, nil)
+	defer shardState.Stop()
+
+	qkcToken := qkcCommon.TokenIDEncode("QKC")
+	qiToken := qkcCommon.TokenIDEncode("QI")
+	head := shardState.CurrentBlock().Hash()
+	shardState.gasPriceSuggestionOracle.cache.Add(gasPriceKey{currHead: head, tokenID: qkcToken}, uint64(10))
+	// no gas was paid in QI, whose suggested gas price falls back to the minimum
+	_, err = shardState.GasPriceRate(qiToken)
+	assert.Error(t, err)
+	price, err := shardState.GasPrice(qiToken)
+	assert.NoError(t, err)
+	assert.Equal(t, shardState.clusterConfig.Quarkchain.MinTXPoolGasPrice.Uint64(), price)
+
+	newTx := func(gasPrice, gasToken uint64) *types.Transaction {
+		nonce := uint64(0)
+		return createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, acc1, new(big.Int), nil, &gasPrice, &nonce, nil, &gasToken, &gasToken)
+	}
+	assert.NoError(t, shardState.AddTx(newTx(100, qkcToken)))
+	assert.Equal(t, ErrReplaceUnderpriced, shardState.AddTx(newTx(1000, qiToken)))
+	assert.NoError(t, shardState.AddTx(newTx(110, qkcToken)))
+}
+
 func TestEstimateGas(t *testing.T) {
 	id1, err := account.CreatRandomIdentity()
 	checkErr(err)
@@ -459,6 +540,76 @@ func TestAddInvalidTxFail(t *testing.T) {
 	assert.Equal(t, len(pending), 0)
 }
 
+func TestAddEthTx(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	acc2, err := account.CreatRandomAccountWithFullShardKey(0)
+	assert.NoError(t, err)
+	acc3, err := account.CreatRandomAccountWithFullShardKey(0)
+	assert.NoError(t, err)
+	fakeMoney := uint64(10000000)
+	env := setUp(&acc1, &fakeMoney, nil)
+	shardState := createDefaultShardState(env, nil, nil, nil, nil)
+	defer shardState.Stop()
+
+	prvKey, err := crypto.ToECDSA(id1.GetKey().Bytes())
+	assert.NoError(t, err)
+	ethTx, err := ethTypes.SignTx(ethTypes.NewTransaction(0, acc2.Recipient, big.NewInt(12345), 21000, big.NewInt(1), nil),
+		ethTypes.NewEIP155Signer(big.NewInt(100000)), prvKey)
+	assert.NoError(t, err)
+	encodedTx, err := rlp.EncodeToBytes(ethTx)
+	assert.NoError(t, err)
+	evmTx, err := types.DecodeEthTransaction(encodedTx, env.clusterConfig.Quarkchain.NetworkID, 0, shardState.GetGenesisToken())
+	assert.NoError(t, err)
+	tx := &types.Transaction{TxType: types.EvmTx, EvmTx: evmTx}
+
+	// eth txs are rejected below their activation height, by the tx pool and
+	// in blocks, whatever the base eth chain id
+	assert.Equal(t, ErrEthTxNotEnabled, shardState.AddTx(tx))
+	env.clusterConfig.Quarkchain.BaseEthChainID = 100000
+	env.clusterConfig.Quarkchain.EnableEthTxHeight = 2
+	assert.Equal(t, ErrEthTxNotEnabled, shardState.AddTx(tx))
+	b0, err := shardState.CreateBlockToMine(nil, &acc3, nil, nil, nil)
+	assert.NoError(t, err)
+	b0.AddTx(tx)
+	_, _, err = shardState.FinalizeAndAddBlock(b0)
+	assert.Equal(t, ErrEthTxNotEnabled, err)
+
+	// and unless the base eth chain id is set
+	env.clusterConfig.Quarkchain.BaseEthChainID = 0
+	env.clusterConfig.Quarkchain.EnableEthTxHeight = 0
+	assert.Equal(t, types.ErrInvalidEthChainID, shardState.AddTx(tx))
+	env.clusterConfig.Quarkchain.BaseEthChainID = 100000
+	assert.NoError(t, shardState.AddTx(tx))
+	// the tx is also known by the hash of the eth tx, pending and included
+	block, index := shardState.GetTransactionByHash(ethTx.Hash())
+	assert.Equal(t, tx.Hash(), block.Transactions()[index].Hash())
+	_, _, receipt := shardState.GetTransactionReceipt(ethTx.Hash())
+	assert.Nil(t, receipt)
+
+	b1, err := shardState.CreateBlockToMine(nil, &acc3, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(b1.Transactions()))
+	_, _, err = shardState.FinalizeAndAddBlock(b1)
+	assert.NoError(t, err)
+	block, index, receipt = shardState.GetTransactionReceipt(ethTx.Hash())
+	assert.Equal(t, b1.Hash(), block.Hash())
+	assert.Equal(t, uint32(0), index)
+	assert.Equal(t, tx.Hash(), receipt.TxHash)
+	assert.Equal(t, tx.Hash(), rawdb.ReadTxHashByEthHash(shardState.db, ethTx.Hash()))
+	state, err := shardState.State()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), state.GetNonce(acc1.Recipient))
+	assert.Equal(t, int64(12345), state.GetBalance(acc2.Recipient, shardState.GetGenesisToken()).Int64())
+
+	// the fields not covered by the signature must be the defaults
+	evmTx, err = types.DecodeEthTransaction(encodedTx, env.clusterConfig.Quarkchain.NetworkID, 1, shardState.GetGenesisToken())
+	assert.NoError(t, err)
+	_, err = shardState.validateTx(&types.Transaction{TxType: types.EvmTx, EvmTx: evmTx}, shardState.currentEvmState, nil, nil, nil)
+	assert.Error(t, err)
+}
+
 func TestAddNonNeighborTxFail(t *testing.T) {
 	id1, err := account.CreatRandomIdentity()
 	checkErr(err)
@@ -967,6 +1118,91 @@ func TestXShardTxReceived(t *testing.T) {
 	assert.Equal(t, uint64(9000), shardState0.currentEvmState.GetXShardReceiveGasUsed().Uint64())
 }
 
+func TestGetXShardDepositBlock(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	checkErr(err)
+
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	acc2 := account.CreatAddressFromIdentity(id1, 16)
+	newGenesisMinorQuarkash := uint64(10000000)
+	fakeShardSize := uint32(64)
+	env := setUp(&acc1, &newGenesisMinorQuarkash, &fakeShardSize)
+	env1 := setUp(&acc1, &newGenesisMinorQuarkash, &fakeShardSize)
+	fakeID := uint32(0)
+	shardState0 := createDefaultShardState(env, &fakeID, nil, nil, nil)
+	fakeID = uint32(16)
+	shardState1 := createDefaultShardState(env1, &fakeID, nil, nil, nil)
+	rootBlock := shardState0.rootTip.CreateBlockToAppend(nil, nil, nil, nil, nil)
+	rootBlock.AddMinorBlockHeader(shardState0.CurrentBlock().Header())
+	rootBlock.AddMinorBlockHeader(shardState1.CurrentBlock().Header())
+	rootBlock.Finalize(nil, nil, common.Hash{})
+	_, err = shardState0.AddRootBlock(rootBlock)
+	checkErr(err)
+
+	// a x-shard tx from a block of shard 16
+	b1 := shardState1.CurrentBlock().CreateBlockToAppend(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	b1Header := b1.Header()
+	b1Header.PrevRootBlockHash = rootBlock.Hash()
+	b1 = types.NewMinorBlock(b1Header, b1.Meta(), b1.Transactions(), nil, nil)
+	fakeGas := uint64(30000)
+	fakeGasPrice := uint64(2)
+	value := new(big.Int).SetUint64(888888)
+	tx := createTransferTransaction(shardState1, id1.GetKey().Bytes(), acc2, acc1, value, &fakeGas, &fakeGasPrice, nil, nil, nil, nil)
+	b1.AddTx(tx)
+	txList := types.CrossShardTransactionDepositList{}
+	txList.TXList = append(txList.TXList, &types.CrossShardTransactionDeposit{
+		TxHash:          tx.Hash(),
+		From:            acc2,
+		To:              acc1,
+		Value:           &serialize.Uint256{Value: value},
+		GasPrice:        &serialize.Uint256{Value: new(big.Int).SetUint64(fakeGasPrice)},
+		GasRemained:     &serialize.Uint256{Value: new(big.Int).SetUint64(tx.EvmTx.Gas() - 21000 - params.GtxxShardCost.Uint64())},
+		TransferTokenID: tx.EvmTx.TransferTokenID(),
+		GasTokenID:      tx.EvmTx.GasTokenID(),
+	})
+	shardState0.AddCrossShardTxListByMinorBlockHash(b1.Hash(), txList)
+
+	// not applied before the root block confirming b1 is added
+	rootBlock = shardState0.rootTip.CreateBlockToAppend(nil, nil, nil, nil, nil)
+	rootBlock.AddMinorBlockHeader(b1.Header())
+	rootBlock.Finalize(nil, nil, common.Hash{})
+	block, err := shardState0.GetXShardDepositBlock(rootBlock.Hash(), b1.Hash(), tx.Hash())
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+	_, err = shardState0.AddRootBlock(rootBlock)
+	checkErr(err)
+	block, err = shardState0.GetXShardDepositBlock(rootBlock.Hash(), b1.Hash(), tx.Hash())
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+	_, err = shardState0.GetXShardDepositBlock(rootBlock.Hash(), b1.Hash(), common.Hash{})
+	assert.Error(t, err)
+
+	// applied by the first block built on the root block, and no later block
+	b2, err := shardState0.CreateBlockToMine(nil, nil, nil, nil, nil)
+	checkErr(err)
+	b2, _, err = shardState0.FinalizeAndAddBlock(b2)
+	checkErr(err)
+	b3, err := shardState0.CreateBlockToMine(nil, nil, nil, nil, nil)
+	checkErr(err)
+	_, _, err = shardState0.FinalizeAndAddBlock(b3)
+	checkErr(err)
+	block, err = shardState0.GetXShardDepositBlock(rootBlock.Hash(), b1.Hash(), tx.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, b2.Hash(), block.Hash())
+
+	// the receipt of the deposit follows the receipts of the txs of b2 and
+	// of the deposits of the root block coinbase
+	block, index, receipt := shardState0.GetXShardDepositReceipt(tx.Hash())
+	assert.Equal(t, b2.Hash(), block.Hash())
+	assert.True(t, index >= uint32(len(b2.Transactions())))
+	assert.Equal(t, shardState0.GetReceiptsByHash(b2.Hash())[index], receipt)
+	assert.Equal(t, tx.Hash(), receipt.TxHash)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	block, _, receipt = shardState0.GetXShardDepositReceipt(common.Hash{})
+	assert.Nil(t, block)
+	assert.Nil(t, receipt)
+}
+
 func TestXShardTxReceivedExcludeNonNeighbor(t *testing.T) {
 	id1, err := account.CreatRandomIdentity()
 	checkErr(err)
@@ -1975,6 +2211,356 @@ func TestContractCall(t *testing.T) {
 	assert.Equal(t, uint64(0x1), receipt.Status)
 }
 
+func TestTraceTransaction(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	id2, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	acc2 := account.CreatAddressFromIdentity(id2, 0)
+	fakeMoney := uint64(10000000)
+	env := setUp(&acc1, &fakeMoney, nil)
+	shardState := createDefaultShardState(env, nil, nil, nil, nil)
+	defer shardState.Stop()
+
+	rootBlock := shardState.rootTip.CreateBlockToAppend(nil, nil, nil, nil, nil).Finalize(nil, nil, common.Hash{})
+	_, err = shardState.AddRootBlock(rootBlock)
+	checkErr(err)
+
+	tx0, err := CreateContract(shardState, id1.GetKey(), acc1, acc1.FullShardKey, ContractWithStorage2)
+	assert.NoError(t, err)
+	err = shardState.AddTx(tx0)
+	assert.NoError(t, err)
+	b1, err := shardState.CreateBlockToMine(nil, &acc2, nil, nil, nil)
+	assert.NoError(t, err)
+	b1, _, err = shardState.FinalizeAndAddBlock(b1)
+	assert.NoError(t, err)
+	_, _, contractReceipt := shardState.GetTransactionReceipt(tx0.Hash())
+	contractAddress := account.NewAddress(contractReceipt.ContractAddress, contractReceipt.ContractFullShardKey)
+
+	// a plain transfer goes first so the traced tx is replayed on top of it
+	data, err := hex.DecodeString("c2e171d7")
+	value, gasPrice, gas := big.NewInt(0), uint64(1), uint64(50000)
+	tx1 := createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, acc2,
+		big.NewInt(1), nil, &gasPrice, nil, nil, nil, nil)
+	assert.NoError(t, shardState.AddTx(tx1))
+	nonce := tx1.EvmTx.Nonce() + 1
+	tx2 := createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, contractAddress,
+		value, &gas, &gasPrice, &nonce, data, nil, nil)
+	assert.NoError(t, shardState.AddTx(tx2))
+	b2, err := shardState.CreateBlockToMine(nil, &acc2, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(b2.Transactions()))
+	b2, _, err = shardState.FinalizeAndAddBlock(b2)
+	assert.NoError(t, err)
+	_, _, receipt := shardState.GetTransactionReceipt(tx2.Hash())
+
+	logger := vm.NewStructLogger(nil)
+	_, gasUsed, failed, err := shardState.TraceTransaction(tx2.Hash(), logger)
+	assert.NoError(t, err)
+	assert.False(t, failed)
+	assert.Equal(t, receipt.GasUsed, gasUsed)
+	hasSStore := false
+	for _, l := range logger.StructLogs() {
+		if l.Op == vm.SSTORE {
+			hasSStore = true
+		}
+	}
+	assert.True(t, hasSStore)
+	// the dispatcher of the contract loads the free memory pointer first
+	for i, op := range []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.MSTORE} {
+		assert.Equal(t, op, logger.StructLogs()[i].Op)
+		assert.Equal(t, uint64(2*i), logger.StructLogs()[i].Pc)
+		assert.Equal(t, 1, logger.StructLogs()[i].Depth)
+	}
+
+	tracer, err := tracers.New("callTracer", &tracers.Context{GasLimit: gas})
+	assert.NoError(t, err)
+	_, _, failed, err = shardState.TraceTransaction(tx2.Hash(), tracer)
+	assert.NoError(t, err)
+	assert.False(t, failed)
+	ret, err := tracer.GetResult()
+	assert.NoError(t, err)
+	var call struct {
+		Type    string          `json:"type"`
+		From    common.Address  `json:"from"`
+		To      common.Address  `json:"to"`
+		Gas     hexutil.Uint64  `json:"gas"`
+		GasUsed hexutil.Uint64  `json:"gasUsed"`
+		Input   hexutil.Bytes   `json:"input"`
+		Output  hexutil.Bytes   `json:"output"`
+		Error   string          `json:"error"`
+		Calls   json.RawMessage `json:"calls"`
+	}
+	assert.NoError(t, json.Unmarshal(ret, &call))
+	assert.Equal(t, "CALL", call.Type)
+	assert.Equal(t, acc1.Recipient, call.From)
+	assert.Equal(t, contractAddress.Recipient, call.To)
+	// the gas of the frame is what is left after the intrinsic gas
+	intrinsic, err := IntrinsicGas(data, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, gas-intrinsic, uint64(call.Gas))
+	assert.Equal(t, receipt.GasUsed-intrinsic, uint64(call.GasUsed))
+	assert.Equal(t, hexutil.Bytes(data), call.Input)
+	assert.Empty(t, call.Output)
+	assert.Empty(t, call.Error)
+	assert.Nil(t, call.Calls)
+
+	tracer, err = tracers.New("4byteTracer", nil)
+	assert.NoError(t, err)
+	_, _, _, err = shardState.TraceTransaction(tx2.Hash(), tracer)
+	assert.NoError(t, err)
+	ret, err = tracer.GetResult()
+	assert.NoError(t, err)
+	ids := make(map[string]int)
+	assert.NoError(t, json.Unmarshal(ret, &ids))
+	assert.Equal(t, map[string]int{"0xc2e171d7-0": 1}, ids)
+
+	callTx := createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, contractAddress,
+		value, &gas, &gasPrice, nil, data, nil, nil)
+	logger = vm.NewStructLogger(&vm.LogConfig{DisableStack: true, Limit: 3})
+	_, _, failed, err = shardState.TraceCall(callTx, &acc1, nil, logger)
+	assert.NoError(t, err)
+	assert.False(t, failed)
+	assert.Equal(t, 3, len(logger.StructLogs()))
+	assert.Nil(t, logger.StructLogs()[0].Stack)
+
+	_, _, _, err = shardState.TraceTransaction(common.Hash{}, vm.NewStructLogger(nil))
+	assert.Error(t, err)
+}
+
+func TestTraceTransactionWithTracers(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	acc2, err := account.CreatRandomAccountWithFullShardKey(0)
+	assert.NoError(t, err)
+	acc3 := account.CreatAddressFromIdentity(id1, 1)
+
+	testGenesisMinorTokenBalance = map[string]*big.Int{
+		"QKC": new(big.Int).SetUint64(10000000),
+		"QI":  new(big.Int).SetUint64(10000000),
+	}
+	defer func() {
+		testGenesisMinorTokenBalance = make(map[string]*big.Int)
+	}()
+	fakeMoney := uint64(10000000)
+	env := setUp(&acc1, &fakeMoney, nil)
+	shardState := createDefaultShardState(env, nil, nil, nil, nil)
+	defer shardState.Stop()
+	env1 := setUp(&acc1, &fakeMoney, nil)
+	id := uint32(1)
+	shardState1 := createDefaultShardState(env1, &id, nil, nil, nil)
+	defer shardState1.Stop()
+
+	rootBlock := shardState.rootTip.CreateBlockToAppend(nil, nil, nil, nil, nil)
+	rootBlock.AddMinorBlockHeader(shardState.CurrentBlock().Header())
+	rootBlock.AddMinorBlockHeader(shardState1.CurrentBlock().Header())
+	rootBlock = rootBlock.Finalize(nil, nil, common.Hash{})
+	_, err = shardState.AddRootBlock(rootBlock)
+	assert.NoError(t, err)
+
+	// send QI to acc2 through the transferMnt precompile, then QKC to another shard
+	qiToken := qkcCommon.TokenIDEncode("QI")
+	transferMnt := account.NewAddress(common.HexToAddress(vm.TransferMntAddr), acc1.FullShardKey)
+	data := common.LeftPadBytes(acc2.Recipient.Bytes(), 32)
+	data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(qiToken).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+	gas := uint64(100000)
+	tx1 := createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, transferMnt,
+		new(big.Int), &gas, nil, nil, data, nil, nil)
+	assert.NoError(t, shardState.AddTx(tx1))
+	nonce := tx1.EvmTx.Nonce() + 1
+	xShardGas := uint64(30000)
+	tx2 := createTransferTransaction(shardState, id1.GetKey().Bytes(), acc1, acc3,
+		big.NewInt(888888), &xShardGas, nil, &nonce, nil, nil, nil)
+	assert.NoError(t, shardState.AddTx(tx2))
+	b1, err := shardState.CreateBlockToMine(nil, &acc2, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(b1.Transactions()))
+	_, _, err = shardState.FinalizeAndAddBlock(b1)
+	assert.NoError(t, err)
+	state, err := shardState.State()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1000), state.GetBalance(acc2.Recipient, qiToken).Uint64())
+
+	type frame struct {
+		Type            string         `json:"type"`
+		From            common.Address `json:"from"`
+		To              common.Address `json:"to"`
+		ToFullShardKey  *hexutil.Uint  `json:"toFullShardKey"`
+		Value           *hexutil.Big   `json:"value"`
+		TransferTokenID hexutil.Uint64 `json:"transferTokenId"`
+		Calls           []frame        `json:"calls"`
+	}
+	trace := func(hash common.Hash, name string, gasLimit uint64, result interface{}) {
+		tracer, err := tracers.New(name, &tracers.Context{GasLimit: gasLimit})
+		assert.NoError(t, err)
+		_, _, failed, err := shardState.TraceTransaction(hash, tracer)
+		assert.NoError(t, err)
+		assert.False(t, failed)
+		ret, err := tracer.GetResult()
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(ret, result))
+	}
+
+	var call frame
+	trace(tx1.Hash(), "callTracer", gas, &call)
+	assert.Equal(t, "CALL", call.Type)
+	assert.Equal(t, transferMnt.Recipient, call.To)
+	assert.Equal(t, 1, len(call.Calls))
+	assert.Equal(t, acc1.Recipient, call.Calls[0].From)
+	assert.Equal(t, acc2.Recipient, call.Calls[0].To)
+	assert.Equal(t, int64(1000), call.Calls[0].Value.ToInt().Int64())
+	assert.Equal(t, qiToken, uint64(call.Calls[0].TransferTokenID))
+
+	var prestate map[common.Address]struct {
+		Balances []struct {
+			TokenID hexutil.Uint64 `json:"tokenId"`
+			Balance *hexutil.Big   `json:"balance"`
+		} `json:"balances"`
+		Nonce uint64 `json:"nonce"`
+	}
+	trace(tx1.Hash(), "prestateTracer", gas, &prestate)
+	sender, ok := prestate[acc1.Recipient]
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), sender.Nonce)
+	assert.Equal(t, 2, len(sender.Balances))
+	for _, balance := range sender.Balances {
+		assert.Equal(t, int64(10000000), balance.Balance.ToInt().Int64())
+	}
+	recipient, ok := prestate[acc2.Recipient]
+	assert.True(t, ok)
+	assert.Equal(t, 0, len(recipient.Balances))
+
+	var xShard frame
+	trace(tx2.Hash(), "callTracer", xShardGas, &xShard)
+	assert.Equal(t, "XSHARD_CALL", xShard.Type)
+	assert.Equal(t, acc3.Recipient, xShard.To)
+	assert.Equal(t, hexutil.Uint(acc3.FullShardKey), *xShard.ToFullShardKey)
+	assert.Equal(t, int64(888888), xShard.Value.ToInt().Int64())
+
+	ids := make(map[string]int)
+	trace(tx2.Hash(), "4byteTracer", xShardGas, &ids)
+	assert.Equal(t, 0, len(ids))
+}
+
+func TestTraceBlock(t *testing.T) {
+	id1, err := account.CreatRandomIdentity()
+	assert.NoError(t, err)
+	acc1 := account.CreatAddressFromIdentity(id1, 0)
+	acc2 := account.CreatAddressFromIdentity(id1, 16)
+	acc3, err := account.CreatRandomAccountWithFullShardKey(0)
+	assert.NoError(t, err)
+	newGenesisMinorQuarkash := uint64(10000000)
+	fakeShardSize := uint32(64)
+	env := setUp(&acc1, &newGenesisMinorQuarkash, &fakeShardSize)
+	env1 := setUp(&acc1, &newGenesisMinorQuarkash, &fakeShardSize)
+	fakeID := uint32(0)
+	shardState0 := createDefaultShardState(env, &fakeID, nil, nil, nil)
+	defer shardState0.Stop()
+	fakeID = uint32(16)
+	shardState1 := createDefaultShardState(env1, &fakeID, nil, nil, nil)
+	defer shardState1.Stop()
+	rootBlock := shardState0.rootTip.CreateBlockToAppend(nil, nil, nil, nil, nil)
+	rootBlock.AddMinorBlockHeader(shardState0.CurrentBlock().Header())
+	rootBlock.AddMinorBlockHeader(shardState1.CurrentBlock().Header())
+	rootBlock.Finalize(nil, nil, common.Hash{})
+	_, err = shardState0.AddRootBlock(rootBlock)
+	assert.NoError(t, err)
+	_, err = shardState1.AddRootBlock(rootBlock)
+	assert.NoError(t, err)
+
+	// receive a x-shard tx from shard 16
+	b1 := shardState1.CurrentBlock().CreateBlockToAppend(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	b1Header := b1.Header()
+	b1Header.PrevRootBlockHash = rootBlock.Hash()
+	b1 = types.NewMinorBlock(b1Header, b1.Meta(), b1.Transactions(), nil, nil)
+	fakeGas := uint64(30000)
+	fakeGasPrice := uint64(2)
+	value := new(big.Int).SetUint64(888888)
+	xTx := createTransferTransaction(shardState1, id1.GetKey().Bytes(), acc2, acc1, value, &fakeGas, &fakeGasPrice, nil, nil, nil, nil)
+	b1.AddTx(xTx)
+	intrinsic := uint64(21000) + params.GtxxShardCost.Uint64()
+	txList := types.CrossShardTransactionDepositList{}
+	txList.TXList = append(txList.TXList, &types.CrossShardTransactionDeposit{
+		TxHash:          xTx.Hash(),
+		From:            acc2,
+		To:              acc1,
+		Value:           &serialize.Uint256{Value: value},
+		GasPrice:        &serialize.Uint256{Value: new(big.Int).SetUint64(fakeGasPrice)},
+		GasRemained:     &serialize.Uint256{Value: new(big.Int).SetUint64(xTx.EvmTx.Gas() - intrinsic)},
+		TransferTokenID: xTx.EvmTx.TransferTokenID(),
+		GasTokenID:      xTx.EvmTx.GasTokenID(),
+	})
+	shardState0.AddCrossShardTxListByMinorBlockHash(b1.Hash(), txList)
+	rootBlock = shardState0.rootTip.CreateBlockToAppend(nil, nil, nil, nil, nil)
+	rootBlock.AddMinorBlockHeader(b1.Header())
+	rootBlock.Finalize(nil, nil, common.Hash{})
+	_, err = shardState0.AddRootBlock(rootBlock)
+	assert.NoError(t, err)
+
+	// and send an in-shard tx in the same block
+	tx := createTransferTransaction(shardState0, id1.GetKey().Bytes(), acc1, acc3, big.NewInt(12345), nil, nil, nil, nil, nil, nil)
+	assert.NoError(t, shardState0.AddTx(tx))
+	b2, err := shardState0.CreateBlockToMine(nil, &acc3, nil, nil, nil)
+	assert.NoError(t, err)
+	b2, _, err = shardState0.FinalizeAndAddBlock(b2)
+	assert.NoError(t, err)
+
+	blockTracer := tracers.NewBlockTracer(func(ctx *tracers.Context) vm.Tracer {
+		tracer, err := tracers.New("callTracer", ctx)
+		assert.NoError(t, err)
+		return tracer
+	})
+	block, evmState, err := shardState0.TraceBlock(b2.Hash(), blockTracer)
+	assert.NoError(t, err)
+	assert.Equal(t, b2.Hash(), block.Hash())
+	assert.Equal(t, b2.GasUsed(), evmState.GetGasUsed())
+	assert.Equal(t, b2.CrossShardGasUsed(), evmState.GetXShardReceiveGasUsed())
+	assert.Equal(t, b2.Meta().XShardTxCursorInfo, evmState.GetTxCursorInfo())
+	assert.Equal(t, b2.Root(), evmState.IntermediateRoot(true))
+
+	type frame struct {
+		Type  string         `json:"type"`
+		From  common.Address `json:"from"`
+		To    common.Address `json:"to"`
+		Value *hexutil.Big   `json:"value"`
+	}
+	// the coinbase deposits of the root blocks are applied before the x-shard tx
+	traces := blockTracer.Traces()
+	assert.Equal(t, 5, len(traces))
+	for _, trace := range traces[:3] {
+		assert.True(t, trace.XShardDeposit)
+	}
+	traces = traces[3:]
+	for i, expect := range []struct {
+		hash          common.Hash
+		xShardDeposit bool
+		from, to      common.Address
+		value         *big.Int
+	}{
+		{xTx.Hash(), true, acc2.Recipient, acc1.Recipient, value},
+		{tx.Hash(), false, acc1.Recipient, acc3.Recipient, big.NewInt(12345)},
+	} {
+		assert.Equal(t, expect.hash, traces[i].TxHash)
+		assert.Equal(t, expect.xShardDeposit, traces[i].XShardDeposit)
+		assert.NotNil(t, traces[i].Receipt)
+		ret, err := traces[i].Tracer.(tracers.Tracer).GetResult()
+		assert.NoError(t, err)
+		var call frame
+		assert.NoError(t, json.Unmarshal(ret, &call))
+		assert.Equal(t, "CALL", call.Type)
+		assert.Equal(t, expect.from, call.From)
+		assert.Equal(t, expect.to, call.To)
+		assert.Equal(t, expect.value, call.Value.ToInt())
+	}
+	assert.Equal(t, uint64(9000), traces[0].Receipt.GasUsed)
+
+	_, _, err = shardState0.TraceBlock(common.Hash{}, blockTracer)
+	assert.Error(t, err)
+}
+
 func TestXShardRootBlockCoinbase(t *testing.T) {
 	id1, err := account.CreatRandomIdentity()
 	assert.NoError(t, err)
@@ -51,6 +51,25 @@ func NewStateProcessor(config *params.ChainConfig, bc *MinorBlockChain, engine c
 	}
 }
 
+// BlockTracer is implemented by a vm.Tracer attached to the processing of a
+// whole block, which is told where each xshard deposit and tx of the block
+// starts and ends so that they can be traced one by one.
+type BlockTracer interface {
+	vm.Tracer
+	CaptureXShardDepositStart(deposit *types.CrossShardTransactionDeposit)
+	CaptureTxStart(tx *types.Transaction)
+	// CaptureTxEnd is called with a nil receipt for deposits applied before evm is enabled
+	CaptureTxEnd(receipt *types.Receipt)
+}
+
+func getBlockTracer(cfg vm.Config) BlockTracer {
+	if !cfg.Debug {
+		return nil
+	}
+	tracer, _ := cfg.Tracer.(BlockTracer)
+	return tracer
+}
+
 // Process processes the state changes according to the Ethereum rules by running
 // the transaction messages using the statedb and applying any rewards to both
 // the processor (coinbase) and any included uncles.
@@ -69,6 +88,7 @@ func (p *StateProcessor) Process(block *types.MinorBlock, statedb *state.StateDB
 		allLogs  []*types.Log
 		gp       = new(GasPool).AddGas(block.GasLimit().Uint64())
 		xGas     = block.GetXShardGasLimit().Uint64()
+		tracer   = getBlockTracer(cfg)
 	)
 
 	// Iterate over and process the individual transactions
@@ -78,10 +98,16 @@ func (p *StateProcessor) Process(block *types.MinorBlock, statedb *state.StateDB
 			return nil, nil, 0, err
 		}
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		if tracer != nil {
+			tracer.CaptureTxStart(tx)
+		}
 		_, receipt, _, err := ApplyTransaction(p.config, p.bc, gp, statedb, header, evmTx, usedGas, cfg)
 		if err != nil {
 			return nil, nil, 0, err
 		}
+		if tracer != nil {
+			tracer.CaptureTxEnd(receipt)
+		}
 		receipts = append(receipts, receipt)
 		allLogs = append(allLogs, receipt.Logs...)
 	}
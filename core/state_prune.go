@@ -0,0 +1,224 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/qkcdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// pruneBatchSize is the number of deletions written to the database at once.
+const pruneBatchSize = 10000
+
+// PruneState removes the state trie nodes and contract code of the shard that
+// are not reachable from the states it keeps: those of the last retention
+// canonical minor blocks, and of the minor blocks confirmed by the last
+// retention root blocks. The entries to keep are marked in a temporary database
+// in markDir, which is removed afterwards, so that memory does not grow with
+// the state. It must only be called while the cluster is stopped, and returns
+// the number of entries removed.
+func PruneState(m *MinorBlockChain, retention uint64, markDir string) (int, error) {
+	if retention == 0 {
+		return 0, fmt.Errorf("prune failed on shard %d: retention must be positive", m.branch.Value)
+	}
+	head := m.CurrentBlock()
+	if _, err := m.StateAt(head.Root()); err != nil {
+		return 0, fmt.Errorf("prune failed on shard %d: head state missing: %v", m.branch.Value, err)
+	}
+
+	roots := make(map[common.Hash]struct{})
+	for i := uint64(0); i < retention && i <= head.NumberU64(); i++ {
+		if block, ok := m.GetBlockByNumber(head.NumberU64() - i).(*types.MinorBlock); ok {
+			roots[block.Root()] = struct{}{}
+		}
+	}
+	rHeader := m.GetRootTip()
+	for i := uint64(0); i < retention && rHeader != nil; i++ {
+		if header := m.getLastConfirmedMinorBlockHeaderAtRootBlock(rHeader.Hash()); header != nil {
+			if block := m.GetMinorBlock(header.Hash()); block != nil {
+				roots[block.Root()] = struct{}{}
+			}
+		}
+		rHeader = m.getRootBlockHeaderByHash(rHeader.ParentHash)
+	}
+
+	start := time.Now()
+	keep, err := newStateMarks(markDir)
+	if err != nil {
+		return 0, fmt.Errorf("prune failed on shard %d: %v", m.branch.Value, err)
+	}
+	defer keep.close()
+	for root := range roots {
+		if _, err := m.StateAt(root); err != nil {
+			// already pruned before, or never written by a pruned node
+			continue
+		}
+		if err := markState(m.stateCache, root, keep); err != nil {
+			return 0, fmt.Errorf("prune failed on shard %d: state %x: %v", m.branch.Value, root, err)
+		}
+	}
+	if err := keep.flush(); err != nil {
+		return 0, fmt.Errorf("prune failed on shard %d: %v", m.branch.Value, err)
+	}
+	log.Info("Marked state", "shard", m.branch.Value, "states", len(roots), "entries", keep.count, "elapsed", time.Since(start))
+
+	// Trie nodes and contract code are the only entries keyed by the hash of
+	// their value.
+	var (
+		batch   = m.db.NewBatch()
+		pending = 0
+		removed = 0
+	)
+	iterErr := qkcdb.Iterate(m.db, nil, func(key, value []byte) bool {
+		if len(key) != common.HashLength || !bytes.Equal(crypto.Keccak256(value), key) {
+			return true
+		}
+		var marked bool
+		if marked, err = keep.has(common.BytesToHash(key)); err != nil || marked {
+			return err == nil
+		}
+		if err = batch.Delete(common.CopyBytes(key)); err != nil {
+			return false
+		}
+		removed++
+		if pending++; pending >= pruneBatchSize {
+			if err = batch.Write(); err != nil {
+				return false
+			}
+			batch, pending = m.db.NewBatch(), 0
+			log.Info("Pruning state", "shard", m.branch.Value, "removed", removed)
+		}
+		return true
+	})
+	if iterErr != nil {
+		return removed, iterErr
+	}
+	if err != nil {
+		return removed, err
+	}
+	if err := batch.Write(); err != nil {
+		return removed, err
+	}
+	log.Info("Pruned state", "shard", m.branch.Value, "kept", keep.count, "removed", removed, "elapsed", time.Since(start))
+	return removed, nil
+}
+
+// stateMarks is the set of the hashes of the state entries to keep, held in a
+// database apart from the chain. The marks not written yet are also held in
+// memory, up to pruneBatchSize of them.
+type stateMarks struct {
+	dir     string
+	db      qkcdb.Database
+	batch   ethdb.Batch
+	pending map[common.Hash]struct{}
+	count   int
+}
+
+func newStateMarks(dir string) (*stateMarks, error) {
+	db, err := qkcdb.Open(dir, &qkcdb.Config{Backend: qkcdb.LevelDBBackend}, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return &stateMarks{dir: dir, db: db, batch: db.NewBatch(), pending: make(map[common.Hash]struct{})}, nil
+}
+
+func (s *stateMarks) has(hash common.Hash) (bool, error) {
+	if _, ok := s.pending[hash]; ok {
+		return true, nil
+	}
+	return s.db.Has(hash[:])
+}
+
+func (s *stateMarks) add(hash common.Hash) error {
+	if err := s.batch.Put(hash[:], nil); err != nil {
+		return err
+	}
+	s.pending[hash] = struct{}{}
+	s.count++
+	if len(s.pending) >= pruneBatchSize {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *stateMarks) flush() error {
+	if err := s.batch.Write(); err != nil {
+		return err
+	}
+	s.batch.Reset()
+	s.pending = make(map[common.Hash]struct{})
+	return nil
+}
+
+func (s *stateMarks) close() {
+	s.db.Close()
+	if err := os.RemoveAll(s.dir); err != nil {
+		log.Warn("Failed to remove prune marks", "dir", s.dir, "err", err)
+	}
+}
+
+// markState adds the hashes of the trie nodes and contract code reachable from
+// the state root to keep. Subtries already in keep are not walked again, so
+// marking many similar states costs little more than marking one.
+func markState(db state.Database, root common.Hash, keep *stateMarks) error {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	emptyCodeHash := crypto.Keccak256(nil)
+	it := tr.NodeIterator(nil)
+	for descend := true; it.Next(descend); {
+		if descend, err = markNode(it.Hash(), keep); err != nil {
+			return err
+		} else if !descend {
+			continue
+		}
+		if !it.Leaf() {
+			continue
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+			return err
+		}
+		if !bytes.Equal(account.CodeHash, emptyCodeHash) {
+			if err := keep.add(common.BytesToHash(account.CodeHash)); err != nil {
+				return err
+			}
+		}
+		storage, err := db.OpenStorageTrie(common.BytesToHash(it.LeafKey()), account.Root)
+		if err != nil {
+			return err
+		}
+		sit := storage.NodeIterator(nil)
+		for sdescend := true; sit.Next(sdescend); {
+			if sdescend, err = markNode(sit.Hash(), keep); err != nil {
+				return err
+			}
+		}
+		if sit.Error() != nil {
+			return sit.Error()
+		}
+	}
+	return it.Error()
+}
+
+// markNode adds a hashed node to keep and reports whether its children still
+// need to be walked. Nodes embedded in their parent have no hash.
+func markNode(hash common.Hash, keep *stateMarks) (bool, error) {
+	if hash == (common.Hash{}) {
+		return true, nil
+	}
+	if marked, err := keep.has(hash); err != nil || marked {
+		return false, err
+	}
+	return true, keep.add(hash)
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPruneTestCluster(t *testing.T, cfg *config.ClusterConfig, rounds int) (*clusterImporter, *MinorBlockChain) {
+	imp := newTestCluster(t, cfg)
+	pending := make(map[uint32][]*types.MinorBlockHeader)
+	for id, shard := range imp.shards {
+		pending[id] = []*types.MinorBlockHeader{shard.CurrentBlock().Header()}
+	}
+	for i := 0; i < rounds; i++ {
+		mineTestCluster(t, imp, pending, true)
+	}
+	return imp, imp.shards[sortedShardIDs(imp.shards)[0]]
+}
+
+// hasStateOnDisk reports whether the state is in the database of the shard,
+// bypassing the trie caches of the chain.
+func hasStateOnDisk(shard *MinorBlockChain, root common.Hash) bool {
+	_, err := state.New(root, state.NewDatabase(shard.db))
+	return err == nil
+}
+
+func TestPruneState(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	imp, shard := newPruneTestCluster(t, env.clusterConfig, 6)
+	roots := make([]common.Hash, 0)
+	for n := uint64(0); n <= shard.CurrentBlock().NumberU64(); n++ {
+		root := shard.GetBlockByNumber(n).(*types.MinorBlock).Root()
+		assert.True(t, hasStateOnDisk(shard, root))
+		roots = append(roots, root)
+	}
+
+	dir, err := ioutil.TempDir("", "prune")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	markDir := filepath.Join(dir, "marks")
+
+	_, err = PruneState(shard, 0, markDir)
+	assert.Error(t, err)
+	removed, err := PruneState(shard, 2, markDir)
+	assert.NoError(t, err)
+	assert.NotZero(t, removed)
+	_, err = os.Stat(markDir)
+	assert.True(t, os.IsNotExist(err))
+	// the genesis state of the test shards is empty
+	for n := 1; n < len(roots); n++ {
+		assert.Equal(t, n >= len(roots)-2, hasStateOnDisk(shard, roots[n]), "block %d", n)
+	}
+
+	// pruning again keeps the same states
+	removed, err = PruneState(shard, 2, markDir)
+	assert.NoError(t, err)
+	assert.Zero(t, removed)
+
+	mineTestCluster(t, imp, make(map[uint32][]*types.MinorBlockHeader), true)
+	assert.Equal(t, uint64(len(roots)), shard.CurrentBlock().NumberU64())
+}
+
+func TestPrunedModeCommitsConfirmedState(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	env.clusterConfig.GCMode = config.GCModePruned
+	env.clusterConfig.StateRetention = 2
+	imp, shard := newPruneTestCluster(t, env.clusterConfig, 4)
+	assert.False(t, shard.cacheConfig.Disabled)
+	assert.Equal(t, uint64(2), shard.stateRetention())
+	for n := uint64(1); n <= shard.CurrentBlock().NumberU64(); n++ {
+		assert.True(t, hasStateOnDisk(shard, shard.GetBlockByNumber(n).(*types.MinorBlock).Root()), "block %d", n)
+	}
+
+	// the state of a block not confirmed by a root block stays in memory
+	mineTestCluster(t, imp, make(map[uint32][]*types.MinorBlockHeader), false)
+	head := shard.CurrentBlock()
+	_, err := shard.StateAt(head.Root())
+	assert.NoError(t, err)
+	assert.False(t, hasStateOnDisk(shard, head.Root()))
+
+	shard.Stop()
+	assert.True(t, hasStateOnDisk(shard, head.Root()))
+}
@@ -0,0 +1,483 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/core/rawdb"
+	"github.com/QuarkChain/goquarkchain/core/state"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/serialize"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// A state snapshot uses the record framing of cluster archives. It holds the
+// root genesis block and the root blocks leading to the snapshot root block,
+// the minor block headers it confirms, and then for every shard the genesis
+// minor block, the minor blocks leading to the confirmed one, the cross-shard
+// deposits still to be processed and the nodes of its state trie, parents
+// before children.
+const (
+	snapshotRootBlock    byte = 2
+	snapshotMinorHeaders byte = 3
+	snapshotMinorBlock   byte = 4
+	snapshotXShardList   byte = 5
+	snapshotStateNode    byte = 6
+)
+
+var errSnapshotNotEmpty = errors.New("snapshot can only be imported into empty databases")
+
+type snapshotMinorHeaderList struct {
+	Headers []*types.MinorBlockHeader `bytesizeofslicelen:"4"`
+}
+
+type snapshotMinorBlockEntry struct {
+	Block   *types.MinorBlock
+	TotalTx uint32
+}
+
+type snapshotXShardListEntry struct {
+	Branch uint32
+	Hash   common.Hash
+	List   types.CrossShardTransactionDepositList
+}
+
+type snapshotStateNodeEntry struct {
+	Branch uint32
+	Hash   common.Hash
+	Data   []byte `bytesizeofslicelen:"4"`
+}
+
+// ExportSnapshot writes a snapshot of the cluster at root block number of the
+// active root chain to w. Besides the state of every shard at the minor block
+// confirmed by that root block, it keeps the blocks needed to validate the
+// next ones: a PoSW window of root and minor blocks, and the root blocks and
+// cross-shard deposits the cross-shard cursors of the shards still refer to.
+func ExportSnapshot(w io.Writer, rootChain *RootBlockChain, shards map[uint32]*MinorBlockChain, number uint64) error {
+	rBlock, ok := rootChain.GetBlockByNumber(number).(*types.RootBlock)
+	if !ok {
+		return fmt.Errorf("snapshot failed on root block #%d: not found", number)
+	}
+	qkcCfg := rootChain.Config()
+	latest := rootChain.GetLatestMinorBlockHeaders(rBlock.Hash())
+
+	// Find the confirmed minor block of each shard and the first root block
+	// any of them still needs.
+	from := windowStart(number, qkcCfg.Root.PoSWConfig.WindowSize)
+	tips := make(map[uint32]*types.MinorBlock)
+	for _, id := range sortedShardIDs(shards) {
+		if uint64(qkcCfg.GetGenesisRootHeight(id)) > number {
+			continue
+		}
+		shard := shards[id]
+		tip, ok := shard.GetBlockByNumber(0).(*types.MinorBlock)
+		if header, confirmed := latest[id]; confirmed {
+			tip = shard.GetMinorBlock(header.Hash())
+			ok = tip != nil
+		}
+		if !ok {
+			return fmt.Errorf("snapshot failed on shard %d: confirmed minor block not found", id)
+		}
+		prevRoot := shard.GetRootBlockByHash(tip.PrevRootBlockHash())
+		if prevRoot == nil {
+			return fmt.Errorf("snapshot failed on shard %d: root block %x not found", id, tip.PrevRootBlockHash())
+		}
+		if prevRoot.NumberU64() < from {
+			from = prevRoot.NumberU64()
+		}
+		if cursor := tip.Meta().XShardTxCursorInfo.RootBlockHeight; cursor < from {
+			from = cursor
+		}
+		tips[id] = tip
+	}
+	rBlocks := make([]*types.RootBlock, 0, number-from+1)
+	for nr := from; nr <= number; nr++ {
+		block, ok := rootChain.GetBlockByNumber(nr).(*types.RootBlock)
+		if !ok {
+			return fmt.Errorf("snapshot failed on root block #%d: not found", nr)
+		}
+		rBlocks = append(rBlocks, block)
+	}
+	// Minor blocks in the window were created on top of older root blocks.
+	for _, block := range rBlocks {
+		for _, header := range block.MinorBlockHeaders() {
+			prevRoot := rootChain.GetHeader(header.PrevRootBlockHash)
+			if prevRoot == nil {
+				return fmt.Errorf("snapshot failed on root block %x: not found", header.PrevRootBlockHash)
+			}
+			if prevRoot.NumberU64() < from {
+				from = prevRoot.NumberU64()
+			}
+		}
+	}
+	for nr := rBlocks[0].NumberU64(); nr > from; nr-- {
+		block, ok := rootChain.GetBlockByNumber(nr - 1).(*types.RootBlock)
+		if !ok {
+			return fmt.Errorf("snapshot failed on root block #%d: not found", nr-1)
+		}
+		rBlocks = append([]*types.RootBlock{block}, rBlocks...)
+	}
+	log.Info("Exporting snapshot", "root block", number, "root blocks", len(rBlocks), "shards", len(tips))
+
+	if from > 0 {
+		if err := writeArchiveRecord(w, snapshotRootBlock, rootChain.GetBlockByNumber(0)); err != nil {
+			return err
+		}
+	}
+	for _, block := range rBlocks {
+		if err := writeArchiveRecord(w, snapshotRootBlock, block); err != nil {
+			return err
+		}
+	}
+	headers := new(snapshotMinorHeaderList)
+	for _, id := range sortedShardIDs(shards) {
+		if header, ok := latest[id]; ok {
+			headers.Headers = append(headers.Headers, header)
+		}
+	}
+	if err := writeArchiveRecord(w, snapshotMinorHeaders, headers); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	for _, id := range sortedShardIDs(shards) {
+		if tip, ok := tips[id]; ok {
+			if err := exportShardSnapshot(w, shards[id], tip, rBlocks); err != nil {
+				return err
+			}
+			log.Info("Exported shard snapshot", "shard", id, "number", tip.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+		}
+	}
+	return nil
+}
+
+func exportShardSnapshot(w io.Writer, shard *MinorBlockChain, tip *types.MinorBlock, rBlocks []*types.RootBlock) error {
+	id := shard.branch.Value
+	writeBlock := func(nr uint64) error {
+		block, ok := shard.GetBlockByNumber(nr).(*types.MinorBlock)
+		if !ok {
+			return fmt.Errorf("snapshot failed on minor block %d #%d: not found", id, nr)
+		}
+		entry := &snapshotMinorBlockEntry{Block: block}
+		if totalTx := shard.getTotalTxCount(block.Hash()); totalTx != nil {
+			entry.TotalTx = *totalTx
+		}
+		return writeArchiveRecord(w, snapshotMinorBlock, entry)
+	}
+	if err := writeBlock(0); err != nil {
+		return err
+	}
+	windowSize := shard.clusterConfig.Quarkchain.GetShardConfigByFullShardID(id).PoswConfig.WindowSize
+	for nr := windowStart(tip.NumberU64(), windowSize); nr <= tip.NumberU64(); nr++ {
+		if nr == 0 {
+			continue
+		}
+		if err := writeBlock(nr); err != nil {
+			return err
+		}
+	}
+
+	for _, rBlock := range rBlocks {
+		for _, header := range rBlock.MinorBlockHeaders() {
+			if header.Branch.Value == id {
+				continue
+			}
+			if list := shard.ReadCrossShardTxList(header.Hash()); list != nil {
+				entry := &snapshotXShardListEntry{Branch: id, Hash: header.Hash(), List: *list}
+				if err := writeArchiveRecord(w, snapshotXShardList, entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	statedb, err := state.New(tip.Root(), shard.stateCache)
+	if err != nil {
+		return fmt.Errorf("snapshot failed on shard %d: %v", id, err)
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+		if it.Hash == (common.Hash{}) {
+			continue
+		}
+		data, err := shard.TrieNode(it.Hash)
+		if err != nil {
+			return fmt.Errorf("snapshot failed on shard %d: state node %x: %v", id, it.Hash, err)
+		}
+		entry := &snapshotStateNodeEntry{Branch: id, Hash: it.Hash, Data: data}
+		if err := writeArchiveRecord(w, snapshotStateNode, entry); err != nil {
+			return err
+		}
+	}
+	return it.Error
+}
+
+// ImportSnapshot writes a snapshot read from r into the empty databases of
+// master and slaves, so that the cluster starts at the root block of the
+// snapshot without replaying history. rootDb is the database of the root
+// chain and shardDb opens the database of a shard. The state of each shard is
+// verified against the state root of its minor block, and the root block of
+// the snapshot is returned.
+func ImportSnapshot(r io.Reader, rootDb ethdb.Database, shardDb func(fullShardID uint32) (ethdb.Database, error)) (*types.RootBlock, error) {
+	if rawdb.ReadHeadBlockHash(rootDb) != (common.Hash{}) {
+		return nil, errSnapshotNotEmpty
+	}
+	imp := &snapshotImporter{
+		rootDb:  rootDb,
+		shardDb: shardDb,
+		shards:  make(map[uint32]*shardSnapshot),
+	}
+	start, reported := time.Now(), time.Now()
+	for {
+		kind, data, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := imp.importRecord(kind, data); err != nil {
+			return nil, err
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing snapshot", "root blocks", len(imp.rBlocks), "shards", len(imp.shards),
+				"state nodes", imp.nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	return imp.finish()
+}
+
+type shardSnapshot struct {
+	db    ethdb.Database
+	batch ethdb.Batch
+	tip   *types.MinorBlock
+	sched *trie.Sync
+}
+
+type snapshotImporter struct {
+	rootDb  ethdb.Database
+	shardDb func(fullShardID uint32) (ethdb.Database, error)
+
+	rBlocks []*types.RootBlock
+	headers []*types.MinorBlockHeader
+	shards  map[uint32]*shardSnapshot
+	nodes   int
+}
+
+func (imp *snapshotImporter) importRecord(kind byte, data []byte) error {
+	switch kind {
+	case snapshotRootBlock:
+		block := new(types.RootBlock)
+		if err := serialize.DeserializeFromBytes(data, block); err != nil {
+			return err
+		}
+		return imp.addRootBlock(block)
+	case snapshotMinorHeaders:
+		headers := new(snapshotMinorHeaderList)
+		if err := serialize.DeserializeFromBytes(data, headers); err != nil {
+			return err
+		}
+		imp.headers = headers.Headers
+		return nil
+	case snapshotMinorBlock:
+		entry := new(snapshotMinorBlockEntry)
+		if err := serialize.DeserializeFromBytes(data, entry); err != nil {
+			return err
+		}
+		return imp.addMinorBlock(entry)
+	case snapshotXShardList:
+		entry := new(snapshotXShardListEntry)
+		if err := serialize.DeserializeFromBytes(data, entry); err != nil {
+			return err
+		}
+		shard, err := imp.shard(entry.Branch)
+		if err != nil {
+			return err
+		}
+		rawdb.WriteCrossShardTxList(shard.db, entry.Hash, entry.List)
+		return nil
+	case snapshotStateNode:
+		entry := new(snapshotStateNodeEntry)
+		if err := serialize.DeserializeFromBytes(data, entry); err != nil {
+			return err
+		}
+		return imp.addStateNode(entry)
+	default:
+		return fmt.Errorf("unknown snapshot record kind %d", kind)
+	}
+}
+
+func (imp *snapshotImporter) addRootBlock(block *types.RootBlock) error {
+	if n := len(imp.rBlocks); n > 0 {
+		parent := imp.rBlocks[n-1]
+		if parent.NumberU64() > 0 && block.ParentHash() != parent.Hash() {
+			return fmt.Errorf("snapshot root block #%d does not extend root block #%d", block.NumberU64(), parent.NumberU64())
+		}
+	} else if block.NumberU64() != 0 {
+		return errors.New("snapshot does not start with the root genesis block")
+	}
+	rawdb.WriteRootBlock(imp.rootDb, block)
+	rawdb.WriteCanonicalHash(imp.rootDb, rawdb.ChainTypeRoot, block.Hash(), block.NumberU64())
+	imp.rBlocks = append(imp.rBlocks, block)
+	return nil
+}
+
+func (imp *snapshotImporter) shard(fullShardID uint32) (*shardSnapshot, error) {
+	if shard, ok := imp.shards[fullShardID]; ok {
+		return shard, nil
+	}
+	db, err := imp.shardDb(fullShardID)
+	if err != nil {
+		return nil, err
+	}
+	if rawdb.ReadHeadBlockHash(db) != (common.Hash{}) {
+		return nil, errSnapshotNotEmpty
+	}
+	shard := &shardSnapshot{db: db, batch: db.NewBatch()}
+	imp.shards[fullShardID] = shard
+	return shard, nil
+}
+
+func (imp *snapshotImporter) addMinorBlock(entry *snapshotMinorBlockEntry) error {
+	block := entry.Block
+	shard, err := imp.shard(block.Branch().Value)
+	if err != nil {
+		return err
+	}
+	if shard.sched != nil {
+		return fmt.Errorf("snapshot minor block %d #%d follows the state", block.Branch().Value, block.NumberU64())
+	}
+	switch {
+	case shard.tip == nil:
+		if block.NumberU64() != 0 {
+			return fmt.Errorf("snapshot of shard %d does not start with its genesis block", block.Branch().Value)
+		}
+		rawdb.WriteGenesisBlock(shard.db, block.PrevRootBlockHash(), block)
+	case shard.tip.NumberU64() > 0 && block.ParentHash() != shard.tip.Hash():
+		return fmt.Errorf("snapshot minor block %d #%d does not extend #%d", block.Branch().Value, block.NumberU64(), shard.tip.NumberU64())
+	}
+	rawdb.WriteMinorBlock(shard.db, block)
+	rawdb.WriteCanonicalHash(shard.db, rawdb.ChainTypeMinor, block.Hash(), block.NumberU64())
+	rawdb.WriteTotalTx(shard.db, block.Hash(), entry.TotalTx)
+	rawdb.WriteCommitMinorBlock(shard.db, block.Hash())
+	shard.tip = block
+	return nil
+}
+
+func (imp *snapshotImporter) addStateNode(entry *snapshotStateNodeEntry) error {
+	shard, err := imp.shard(entry.Branch)
+	if err != nil {
+		return err
+	}
+	if shard.tip == nil {
+		return fmt.Errorf("snapshot state of shard %d precedes its blocks", entry.Branch)
+	}
+	if crypto.Keccak256Hash(entry.Data) != entry.Hash {
+		return fmt.Errorf("snapshot state node %x of shard %d is corrupt", entry.Hash, entry.Branch)
+	}
+	if shard.sched == nil {
+		shard.sched = state.NewStateSync(shard.tip.Root(), shard.db)
+	}
+	// Nodes shared by several tries are only requested once.
+	_, _, err = shard.sched.Process([]trie.SyncResult{{Hash: entry.Hash, Data: entry.Data}})
+	if err == trie.ErrNotRequested || err == trie.ErrAlreadyProcessed {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("snapshot state node %x of shard %d: %v", entry.Hash, entry.Branch, err)
+	}
+	imp.nodes++
+	if imp.nodes%1024 == 0 {
+		return shard.commitState()
+	}
+	return nil
+}
+
+func (shard *shardSnapshot) commitState() error {
+	if _, err := shard.sched.Commit(shard.batch); err != nil {
+		return err
+	}
+	if shard.batch.ValueSize() < ethdb.IdealBatchSize {
+		return nil
+	}
+	if err := shard.batch.Write(); err != nil {
+		return err
+	}
+	shard.batch.Reset()
+	return nil
+}
+
+// finish checks every shard got its complete state and sets the heads of the
+// chains. Each shard also gets the root blocks of the snapshot with the minor
+// block they confirm in that shard, as slaves keep them.
+func (imp *snapshotImporter) finish() (*types.RootBlock, error) {
+	if len(imp.rBlocks) == 0 {
+		return nil, errors.New("snapshot has no root block")
+	}
+	rBlock := imp.rBlocks[len(imp.rBlocks)-1]
+	latest := make(map[uint32]common.Hash)
+	for _, header := range imp.headers {
+		latest[header.Branch.Value] = header.Hash()
+	}
+
+	for id, shard := range imp.shards {
+		if shard.tip == nil {
+			return nil, fmt.Errorf("snapshot of shard %d has no blocks", id)
+		}
+		if hash, ok := latest[id]; ok && hash != shard.tip.Hash() {
+			return nil, fmt.Errorf("snapshot of shard %d ends at minor block #%d not confirmed by the root block", id, shard.tip.NumberU64())
+		}
+		if shard.sched == nil {
+			shard.sched = state.NewStateSync(shard.tip.Root(), shard.db)
+		}
+		if _, err := shard.sched.Commit(shard.batch); err != nil {
+			return nil, err
+		}
+		if err := shard.batch.Write(); err != nil {
+			return nil, err
+		}
+		if pending := shard.sched.Pending(); pending > 0 {
+			return nil, fmt.Errorf("snapshot of shard %d misses %d state entries", id, pending)
+		}
+
+		var confirmed common.Hash
+		for _, block := range imp.rBlocks {
+			for _, header := range block.MinorBlockHeaders() {
+				if header.Branch.Value == id {
+					confirmed = header.Hash()
+				}
+			}
+			if block.Hash() == rBlock.Hash() {
+				confirmed = latest[id]
+			}
+			rawdb.WriteRootBlock(shard.db, block)
+			if confirmed != (common.Hash{}) {
+				rawdb.WriteLastConfirmedMinorBlockHeaderAtRootBlock(shard.db, block.Hash(), confirmed)
+			}
+		}
+		rawdb.WriteHeadHeaderHash(shard.db, shard.tip.Hash())
+		rawdb.WriteHeadBlockHash(shard.db, shard.tip.Hash())
+	}
+
+	rawdb.WriteLatestMinorBlockHeaders(imp.rootDb, rBlock.Hash(), imp.headers)
+	rawdb.WriteHeadHeaderHash(imp.rootDb, rBlock.Hash())
+	rawdb.WriteHeadBlockHash(imp.rootDb, rBlock.Hash())
+	log.Info("Imported snapshot", "root block", rBlock.NumberU64(), "shards", len(imp.shards), "state nodes", imp.nodes)
+	return rBlock, nil
+}
+
+// windowStart returns the first block number of a window of size blocks
+// ending at number.
+func windowStart(number, size uint64) uint64 {
+	if number+1 > size {
+		return number + 1 - size
+	}
+	return 0
+}
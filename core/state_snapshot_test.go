@@ -0,0 +1,97 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/consensus"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportSnapshot(t *testing.T) {
+	env := setUp(nil, nil, nil)
+	src := newTestCluster(t, env.clusterConfig)
+
+	pending := make(map[uint32][]*types.MinorBlockHeader)
+	for id, shard := range src.shards {
+		pending[id] = []*types.MinorBlockHeader{shard.CurrentBlock().Header()}
+	}
+	for i := 0; i < 3; i++ {
+		mineTestCluster(t, src, pending, true)
+	}
+	mineTestCluster(t, src, pending, false)
+	rTip := src.rootChain.CurrentBlock()
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, ExportSnapshot(buf, src.rootChain, src.shards, rTip.NumberU64()))
+	snapshot := buf.Bytes()
+
+	rootDb := ethdb.NewMemDatabase()
+	shardDbs := make(map[uint32]ethdb.Database)
+	openShardDb := func(fullShardID uint32) (ethdb.Database, error) {
+		shardDbs[fullShardID] = ethdb.NewMemDatabase()
+		return shardDbs[fullShardID], nil
+	}
+	rBlock, err := ImportSnapshot(bytes.NewReader(snapshot), rootDb, openShardDb)
+	assert.NoError(t, err)
+	assert.Equal(t, rTip.Hash(), rBlock.Hash())
+	assert.Equal(t, len(src.shards), len(shardDbs))
+
+	// start the cluster from the snapshot the way master and slaves do
+	_, _, err = SetupGenesisRootBlock(rootDb, NewGenesis(env.clusterConfig.Quarkchain))
+	assert.NoError(t, err)
+	rootChain, err := NewRootBlockChain(rootDb, env.clusterConfig.Quarkchain, new(consensus.FakeEngine))
+	assert.NoError(t, err)
+	assert.Equal(t, rTip.Hash(), rootChain.CurrentBlock().Hash())
+	dst := &clusterImporter{
+		rootChain: rootChain,
+		shards:    make(map[uint32]*MinorBlockChain),
+		newShard: func(fullShardID uint32, rBlock *types.RootBlock) (*MinorBlockChain, error) {
+			return NewMinorBlockChain(shardDbs[fullShardID], nil, params.TestChainConfig, env.clusterConfig,
+				new(consensus.FakeEngine), vm.Config{}, nil, fullShardID)
+		},
+	}
+	rootChain.SetRootChainStakesFunc(dst.getRootChainStakes)
+	assert.NoError(t, dst.createShards(rootChain.CurrentBlock()))
+	latest := src.rootChain.GetLatestMinorBlockHeaders(rTip.Hash())
+	for id, shard := range dst.shards {
+		assert.Equal(t, latest[id].Hash(), shard.CurrentBlock().Hash())
+		assert.Equal(t, uint64(3), shard.CurrentBlock().NumberU64())
+		assert.Equal(t, src.shards[id].GetMinorBlock(latest[id].Hash()).Root(), shard.CurrentBlock().Root())
+	}
+
+	// the bootstrapped cluster keeps growing
+	pending = make(map[uint32][]*types.MinorBlockHeader)
+	mineTestCluster(t, dst, pending, true)
+	assert.Equal(t, rTip.NumberU64()+1, dst.rootChain.CurrentBlock().NumberU64())
+	for _, shard := range dst.shards {
+		assert.Equal(t, uint64(4), shard.CurrentBlock().NumberU64())
+	}
+
+	// a snapshot is only imported into empty databases
+	_, err = ImportSnapshot(bytes.NewReader(snapshot), rootDb, openShardDb)
+	assert.Equal(t, errSnapshotNotEmpty, err)
+
+	// a snapshot missing a state node fails
+	r := bytes.NewReader(snapshot)
+	var first, end int
+	for {
+		start := len(snapshot) - r.Len()
+		kind, _, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if kind == snapshotStateNode && first == 0 {
+			first, end = start, len(snapshot)-r.Len()
+		}
+	}
+	pruned := append(append([]byte{}, snapshot[:first]...), snapshot[end:]...)
+	_, err = ImportSnapshot(bytes.NewReader(pruned), ethdb.NewMemDatabase(), openShardDb)
+	assert.Contains(t, err.Error(), "misses")
+}
@@ -335,6 +335,7 @@ func (st *StateTransition) AddCrossShardTxDeposit(intrinsicGas uint64) (ret []by
 			CreateContract:  true,
 		}
 		state.AppendXShardList(crossShardData)
+		evm.CaptureXShardDeposit(crossShardData)
 		failed = false
 
 	} else {
@@ -370,6 +371,7 @@ func (st *StateTransition) AddCrossShardTxDeposit(intrinsicGas uint64) (ret []by
 			CreateContract:  false,
 		}
 		state.AppendXShardList(crossShardData)
+		evm.CaptureXShardDeposit(crossShardData)
 		failed = false
 	}
 	localGasUsed := st.gasUsed()
@@ -243,22 +243,79 @@ func (l *txList) Overlaps(tx *types.Transaction) bool {
 	return l.txs.Get(tx.EvmTx.Nonce()) != nil
 }
 
+// GasPriceRate returns the gas price suggested for a gas token, which prices
+// in different gas tokens are compared by, or nil if it is unknown.
+type GasPriceRate func(tokenID uint64) *big.Int
+
+// replacesTx reports whether tx is priced high enough to replace old, which has
+// the same nonce: its gas price must be higher than the one of old by priceBump
+// percent. When they pay gas in different tokens, each gas price is taken as a
+// share of the price rate suggests for its token, and the replacement is
+// refused if rate does not know either of them.
+func replacesTx(old, tx *types.Transaction, priceBump uint64, rate GasPriceRate) bool {
+	oldPrice, newPrice := old.EvmTx.GasPrice(), tx.EvmTx.GasPrice()
+	if old.EvmTx.GasTokenID() != tx.EvmTx.GasTokenID() {
+		oldRate, newRate := gasPriceRates(rate, old.EvmTx.GasTokenID(), tx.EvmTx.GasTokenID())
+		if oldRate == nil {
+			return false
+		}
+		// oldPrice/oldRate < newPrice/newRate without the rounding of a division
+		oldPrice = new(big.Int).Mul(oldPrice, newRate)
+		newPrice = new(big.Int).Mul(newPrice, oldRate)
+	}
+	threshold := new(big.Int).Div(new(big.Int).Mul(oldPrice, big.NewInt(100+int64(priceBump))), big.NewInt(100))
+	// Have to ensure that the new gas price is higher than the old gas
+	// price as well as checking the percentage threshold to ensure that
+	// this is accurate for low (Wei-level) gas price replacements
+	return oldPrice.Cmp(newPrice) < 0 && threshold.Cmp(newPrice) <= 0
+}
+
+// gasPriceRates returns the rates of two gas tokens, or nils if either is
+// unknown.
+func gasPriceRates(rate GasPriceRate, oldTokenID, newTokenID uint64) (*big.Int, *big.Int) {
+	if rate == nil {
+		return nil, nil
+	}
+	oldRate, newRate := rate(oldTokenID), rate(newTokenID)
+	if oldRate == nil || newRate == nil || oldRate.Sign() <= 0 || newRate.Sign() <= 0 {
+		return nil, nil
+	}
+	return oldRate, newRate
+}
+
+// ReplacementGasPrice returns the lowest gas price in the gas token tokenID of
+// a tx replacing old by the rules of replacesTx, or nil if old pays gas in
+// another token and rate does not know either of them.
+func ReplacementGasPrice(old *types.Transaction, tokenID uint64, priceBump uint64, rate GasPriceRate) *big.Int {
+	oldRate, newRate := big.NewInt(1), big.NewInt(1)
+	if old.EvmTx.GasTokenID() != tokenID {
+		if oldRate, newRate = gasPriceRates(rate, old.EvmTx.GasTokenID(), tokenID); oldRate == nil {
+			return nil
+		}
+	}
+	// the price of old in tokenID, and the bumped one rounded up
+	oldPrice := new(big.Int).Mul(old.EvmTx.GasPrice(), newRate)
+	num := new(big.Int).Mul(oldPrice, big.NewInt(100+int64(priceBump)))
+	den := new(big.Int).Mul(oldRate, big.NewInt(100))
+	price := num.Div(num.Add(num, new(big.Int).Sub(den, big.NewInt(1))), den)
+	// a bump too small to raise the price still has to go above it
+	if new(big.Int).Mul(price, oldRate).Cmp(oldPrice) <= 0 {
+		price.Add(price, big.NewInt(1))
+	}
+	return price
+}
+
 // Add tries to insert a new transaction into the list, returning whether the
 // transaction was accepted, and if yes, any previous transaction it replaced.
+// A transaction of the same nonce is only replaced by the rules of replacesTx.
 //
 // If the new transaction is accepted into the list, the lists' cost and gas
 // thresholds are also potentially updated.
-func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Transaction) {
+func (l *txList) Add(tx *types.Transaction, priceBump uint64, rate GasPriceRate) (bool, *types.Transaction) {
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.EvmTx.Nonce())
-	if old != nil {
-		threshold := new(big.Int).Div(new(big.Int).Mul(old.EvmTx.GasPrice(), big.NewInt(100+int64(priceBump))), big.NewInt(100))
-		// Have to ensure that the new gas price is higher than the old gas
-		// price as well as checking the percentage threshold to ensure that
-		// this is accurate for low (Wei-level) gas price replacements
-		if old.EvmTx.GasPrice().Cmp(tx.EvmTx.GasPrice()) >= 0 || threshold.Cmp(tx.EvmTx.GasPrice()) > 0 {
-			return false, nil
-		}
+	if old != nil && !replacesTx(old, tx, priceBump, rate) {
+		return false, nil
 	}
 	// Otherwise overwrite the old transaction with the current one
 	l.txs.Put(tx)
@@ -17,10 +17,15 @@
 package core
 
 import (
-	"github.com/QuarkChain/goquarkchain/core/types"
+	"crypto/ecdsa"
+	"math/big"
 	"math/rand"
 	"testing"
 
+	"github.com/QuarkChain/goquarkchain/account"
+	qkcCommon "github.com/QuarkChain/goquarkchain/common"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -37,7 +42,7 @@ func TestStrictTxListAdd(t *testing.T) {
 	// Insert the transactions in a random order
 	list := newTxList(true)
 	for _, v := range rand.Perm(len(txs)) {
-		list.Add(txs[v], DefaultTxPoolConfig.PriceBump)
+		list.Add(txs[v], DefaultTxPoolConfig.PriceBump, nil)
 	}
 	// Verify internal state
 	if len(list.txs.items) != len(txs) {
@@ -49,3 +54,52 @@ func TestStrictTxListAdd(t *testing.T) {
 		}
 	}
 }
+
+func gasTokenTransaction(nonce uint64, gasPrice int64, gasTokenID uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewEvmTransaction(nonce, account.BytesToIdentityRecipient(common.Address{}.Bytes()), big.NewInt(100), 21000,
+		big.NewInt(gasPrice), 0, 0, 3, 0, []byte{}, gasTokenID, gasTokenID), types.MakeSigner(3), key)
+	return &types.Transaction{TxType: types.EvmTx, EvmTx: tx}
+}
+
+// Tests that a transaction paying gas in another token replaces one of the same
+// nonce only if its gas price is bumped at the rates of the tokens.
+func TestTxListReplaceAcrossGasTokens(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	qkc, qi := qkcCommon.TokenIDEncode("QKC"), qkcCommon.TokenIDEncode("QI")
+	rates := map[uint64]*big.Int{qkc: big.NewInt(10), qi: big.NewInt(20)}
+	rate := func(tokenID uint64) *big.Int { return rates[tokenID] }
+
+	list := newTxList(true)
+	if inserted, _ := list.Add(gasTokenTransaction(0, 100, qkc, key), DefaultTxPoolConfig.PriceBump, rate); !inserted {
+		t.Fatalf("failed to add original transaction")
+	}
+	// 100 QKC is worth 200 QI, bumped by 10%
+	if price := ReplacementGasPrice(list.txs.Get(0), qi, DefaultTxPoolConfig.PriceBump, rate); price.Cmp(big.NewInt(220)) != 0 {
+		t.Errorf("replacement gas price mismatch: have %v, want %v", price, 220)
+	}
+	if inserted, _ := list.Add(gasTokenTransaction(0, 219, qi, key), DefaultTxPoolConfig.PriceBump, rate); inserted {
+		t.Errorf("underpriced replacement accepted")
+	}
+	if inserted, _ := list.Add(gasTokenTransaction(0, 1000, qi, key), DefaultTxPoolConfig.PriceBump, nil); inserted {
+		t.Errorf("replacement accepted without rates")
+	}
+	delete(rates, qi)
+	if inserted, _ := list.Add(gasTokenTransaction(0, 1000, qi, key), DefaultTxPoolConfig.PriceBump, rate); inserted {
+		t.Errorf("replacement accepted without the rate of its token")
+	}
+	if price := ReplacementGasPrice(list.txs.Get(0), qi, DefaultTxPoolConfig.PriceBump, rate); price != nil {
+		t.Errorf("replacement gas price without rate: have %v, want nil", price)
+	}
+	rates[qi] = big.NewInt(20)
+	inserted, old := list.Add(gasTokenTransaction(0, 220, qi, key), DefaultTxPoolConfig.PriceBump, rate)
+	if !inserted || old == nil || old.EvmTx.GasTokenID() != qkc {
+		t.Fatalf("failed to replace original transaction")
+	}
+	// and back, with a bump too small to raise a low price
+	if price := ReplacementGasPrice(list.txs.Get(0), qkc, DefaultTxPoolConfig.PriceBump, rate); price.Cmp(big.NewInt(121)) != 0 {
+		t.Errorf("replacement gas price mismatch: have %v, want %v", price, 121)
+	}
+	if price := ReplacementGasPrice(list.txs.Get(0), qi, 0, rate); price.Cmp(big.NewInt(221)) != 0 {
+		t.Errorf("replacement gas price mismatch: have %v, want %v", price, 221)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/prque"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 
 	"github.com/QuarkChain/goquarkchain/core/state"
 	"github.com/QuarkChain/goquarkchain/core/types"
@@ -103,12 +104,15 @@ type minorBlockChain interface {
 	Config() *config.QuarkChainConfig
 	SubscribeChainHeadEvent(ch chan<- MinorChainHeadEvent) event.Subscription
 	validateTx(tx *types.Transaction, evmState *state.StateDB, fromAddress *account.Address, gas, xShardGasLimit *uint64) (*types.Transaction, error)
+	GasPriceRate(tokenID uint64) (uint64, error)
 }
 
 // TxPoolConfig are the configuration parameters of the transaction pool.
 type TxPoolConfig struct {
-	Locals   []common.Address // Addresses that should be treated by default as local
-	NoLocals bool             // Whether local transaction handling should be disabled
+	Locals    []common.Address // Addresses that should be treated by default as local
+	NoLocals  bool             // Whether local transaction handling should be disabled
+	Journal   string           // Journal of local transactions to survive node restarts
+	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
@@ -125,6 +129,7 @@ type TxPoolConfig struct {
 // DefaultTxPoolConfig contains the default configurations for the transaction
 // pool.
 var DefaultTxPoolConfig = TxPoolConfig{
+	Rejournal: time.Hour,
 
 	PriceLimit: 1,
 	PriceBump:  10,
@@ -159,7 +164,8 @@ type TxPool struct {
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
 
-	locals *accountSet // Set of local transaction to exempt from eviction rules
+	locals  *accountSet // Set of local transaction to exempt from eviction rules
+	journal *txJournal  // Journal of local transaction to back up to disk
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
@@ -176,6 +182,9 @@ type TxPool struct {
 	reorgShutdownCh  chan struct{}  // requests shutdown of scheduleReorgLoop
 	wg               sync.WaitGroup // tracks loop, scheduleReorgLoop
 	fakeChanForReset chan uint64
+
+	pendingGauge metrics.Gauge // Number of executable txs, updated at each stats report
+	queuedGauge  metrics.Gauge // Number of queued txs, updated at each stats report
 }
 
 type txpoolResetRequest struct {
@@ -185,6 +194,10 @@ type txpoolResetRequest struct {
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
 // transactions from the network.
 func NewTxPool(config TxPoolConfig, chain minorBlockChain) *TxPool {
+	if config.Rejournal < time.Second {
+		log.Warn("Sanitizing invalid txpool journal time", "provided", config.Rejournal, "updated", time.Second)
+		config.Rejournal = time.Second
+	}
 
 	// Create the transaction pool with its initial settings
 	pool := &TxPool{
@@ -204,6 +217,9 @@ func NewTxPool(config TxPoolConfig, chain minorBlockChain) *TxPool {
 		gasPrice:        new(big.Int).SetUint64(config.PriceLimit),
 		quarkConfig:     chain.Config(),
 	}
+	fullShardID := chain.CurrentBlock().Branch().Value
+	pool.pendingGauge = metrics.GetOrRegisterGauge(fmt.Sprintf("shard/%d/txpool/pending", fullShardID), nil)
+	pool.queuedGauge = metrics.GetOrRegisterGauge(fmt.Sprintf("shard/%d/txpool/queued", fullShardID), nil)
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
 		pool.locals.add(addr)
@@ -215,6 +231,11 @@ func NewTxPool(config TxPoolConfig, chain minorBlockChain) *TxPool {
 	pool.wg.Add(1)
 	go pool.scheduleReorgLoop()
 
+	// If local transactions and journaling is enabled, load from disk
+	if config.Journal != "" {
+		pool.StartJournal(config.Journal)
+	}
+
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
 	pool.wg.Add(1)
@@ -234,11 +255,14 @@ func (pool *TxPool) loop() {
 		// Start the stats reporting and transaction eviction tickers
 		report = time.NewTicker(statsReportInterval)
 		evict  = time.NewTicker(evictionInterval)
+		// Start the journal rotation ticker, which is idle until a journal is started
+		journal = time.NewTicker(pool.config.Rejournal)
 		// Track the previous head headers for transaction reorgs
 		head = pool.chain.CurrentBlock()
 	)
 	defer report.Stop()
 	defer evict.Stop()
+	defer journal.Stop()
 
 	for {
 		select {
@@ -260,6 +284,8 @@ func (pool *TxPool) loop() {
 			pending, queued := pool.stats()
 			stales := pool.priced.stales
 			pool.mu.RUnlock()
+			pool.pendingGauge.Update(int64(pending))
+			pool.queuedGauge.Update(int64(queued))
 
 			if pending != prevPending || queued != prevQueued || stales != prevStales {
 				log.Debug("Transaction pool status report", "executable", pending, "queued", queued, "stales", stales)
@@ -282,10 +308,41 @@ func (pool *TxPool) loop() {
 				}
 			}
 			pool.mu.Unlock()
+
+		// Handle local transaction journal rotation
+		case <-journal.C:
+			pool.mu.Lock()
+			if pool.journal != nil {
+				if err := pool.journal.rotate(pool.local()); err != nil {
+					log.Warn("Failed to rotate local tx journal", "err", err)
+				}
+			}
+			pool.mu.Unlock()
 		}
 	}
 }
 
+// StartJournal loads the local transactions journaled at path into the pool
+// and starts journaling the local transactions of the pool there, rewriting
+// the journal every Rejournal. It does nothing if local transaction handling
+// is disabled.
+func (pool *TxPool) StartJournal(path string) {
+	if pool.config.NoLocals {
+		return
+	}
+	journal := newTxJournal(path)
+	if err := journal.load(pool.AddLocals); err != nil {
+		log.Warn("Failed to load transaction journal", "err", err)
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.journal = journal
+	if err := journal.rotate(pool.local()); err != nil {
+		log.Warn("Failed to rotate transaction journal", "err", err)
+	}
+}
+
 // Stop terminates the transaction pool.
 func (pool *TxPool) Stop() {
 	// Unsubscribe all subscriptions registered from txpool
@@ -295,6 +352,9 @@ func (pool *TxPool) Stop() {
 	pool.chainHeadSub.Unsubscribe()
 	pool.wg.Wait()
 
+	if pool.journal != nil {
+		pool.journal.close()
+	}
 	log.Info("Transaction pool stopped")
 }
 
@@ -412,6 +472,18 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// gasPriceRate returns the gas price the chain suggests for the gas token, which
+// replacements paying gas in another token than the tx they replace are priced
+// by, or nil if no tx of the recent blocks paid gas in the token.
+func (pool *TxPool) gasPriceRate(tokenID uint64) *big.Int {
+	price, err := pool.chain.GasPriceRate(tokenID)
+	if err != nil {
+		log.Debug("No gas price for replacement", "token", tokenID, "err", err)
+		return nil
+	}
+	return new(big.Int).SetUint64(price)
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
@@ -485,7 +557,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 	from, _ := types.Sender(pool.signer, tx.EvmTx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
-		inserted, old := list.Add(tx, pool.config.PriceBump)
+		inserted, old := list.Add(tx, pool.config.PriceBump, pool.gasPriceRate)
 		if !inserted {
 			return false, ErrReplaceUnderpriced
 		}
@@ -496,6 +568,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		}
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
+		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.EvmTx.To())
 		return old != nil, nil
@@ -512,10 +585,23 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.locals.add(from)
 		}
 	}
+	pool.journalTx(from, tx)
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.EvmTx.To())
 	return replaced, nil
 }
 
+// journalTx adds the specified transaction to the local disk journal if it is
+// deemed to have been sent from a local account.
+func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
+	// Only journal if it's enabled and the transaction is local
+	if pool.journal == nil || !pool.locals.contains(from) {
+		return
+	}
+	if err := pool.journal.insert(tx); err != nil {
+		log.Warn("Failed to journal local transaction", "err", err)
+	}
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -525,7 +611,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	if pool.queue[from] == nil {
 		pool.queue[from] = newTxList(false)
 	}
-	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump)
+	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump, pool.gasPriceRate)
 	if !inserted {
 		// An older transaction was better, discard this
 		return false, ErrReplaceUnderpriced
@@ -553,7 +639,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	list := pool.pending[addr]
 
-	inserted, old := list.Add(tx, pool.config.PriceBump)
+	inserted, old := list.Add(tx, pool.config.PriceBump, pool.gasPriceRate)
 	if !inserted {
 		// An older transaction was better, discard this
 		pool.all.Remove(hash)
@@ -1360,6 +1446,7 @@ func (as *accountSet) merge(other *accountSet) {
 // TxPool.mu mutex.
 type txLookup struct {
 	all  map[common.Hash]*types.Transaction
+	eth  map[common.Hash]common.Hash // eth tx hash -> hash of the txs mapped from Ethereum txs
 	lock sync.RWMutex
 }
 
@@ -1367,6 +1454,7 @@ type txLookup struct {
 func newTxLookup() *txLookup {
 	return &txLookup{
 		all: make(map[common.Hash]*types.Transaction),
+		eth: make(map[common.Hash]common.Hash),
 	}
 }
 
@@ -1390,6 +1478,15 @@ func (t *txLookup) Get(hash common.Hash) *types.Transaction {
 	return t.all[hash]
 }
 
+// GetByEthHash returns the transaction mapped from the Ethereum tx of ethHash if it
+// exists in the lookup, or nil if not found.
+func (t *txLookup) GetByEthHash(ethHash common.Hash) *types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.all[t.eth[ethHash]]
+}
+
 // Count returns the current number of items in the lookup.
 func (t *txLookup) Count() int {
 	t.lock.RLock()
@@ -1404,6 +1501,9 @@ func (t *txLookup) Add(tx *types.Transaction) {
 	defer t.lock.Unlock()
 
 	t.all[tx.Hash()] = tx
+	if tx.EvmTx.Version() == types.EthTxVersion {
+		t.eth[tx.EvmTx.EthHash()] = tx.Hash()
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1411,5 +1511,8 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	if tx, ok := t.all[hash]; ok && tx.EvmTx.Version() == types.EthTxVersion {
+		delete(t.eth, tx.EvmTx.EthHash())
+	}
 	delete(t.all, hash)
 }
@@ -18,6 +18,7 @@ package core
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -85,6 +86,10 @@ func (m *testBlockChain) validateTx(tx *types.Transaction, evmState *state.State
 	return tx, nil
 }
 
+func (bc *testBlockChain) GasPriceRate(tokenID uint64) (uint64, error) {
+	return 0, errors.New("no gas price")
+}
+
 func transaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
 	return pricedTransaction(nonce, gaslimit, big.NewInt(1), key)
 }
@@ -1599,7 +1604,7 @@ func TestTransactionReplacement(t *testing.T) {
 
 // Tests that local transactions are journaled to disk, but remote transactions
 // get discarded between restarts.
-//func TestTransactionJournaling(t *testing.T)         { testTransactionJournaling(t, false) }
+func TestTransactionJournaling(t *testing.T)         { testTransactionJournaling(t, false) }
 func TestTransactionJournalingNoLocals(t *testing.T) { testTransactionJournaling(t, true) }
 
 func testTransactionJournaling(t *testing.T, nolocals bool) {
@@ -1623,6 +1628,8 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 
 	config := testTxPoolConfig
 	config.NoLocals = nolocals
+	config.Journal = journal
+	config.Rejournal = time.Second
 
 	pool := NewTxPool(config, blockchain)
 
@@ -1682,6 +1689,7 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	// Bump the nonce temporarily and ensure the newly invalidated transaction is removed
 	statedb.SetNonce(crypto.PubkeyToAddress(local.PublicKey), 2)
 	<-pool.requestReset(nil, nil)
+	time.Sleep(2 * config.Rejournal)
 	pool.Stop()
 
 	statedb.SetNonce(crypto.PubkeyToAddress(local.PublicKey), 1)
@@ -22,6 +22,14 @@ const (
 	EvmTx = 0
 )
 
+// EthTxVersion is the version of an EvmTransaction mapped from a plain EIP-155
+// signed Ethereum tx, whose signature only covers the Ethereum fields of the tx.
+const EthTxVersion = 2
+
+var (
+	ErrInvalidEthChainID = errors.New("invalid eth chain id")
+)
+
 //go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
 
 var (
@@ -166,6 +174,72 @@ func (tx *EvmTransaction) getUnsignedHash() common.Hash {
 	return rlpHash(unsigntx)
 }
 
+// ethTxdata is the RLP layout of a signed Ethereum tx before EIP-2718.
+type ethTxdata struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *account.Recipient `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	V, R, S      *big.Int
+}
+
+// DecodeEthTransaction decodes a RLP encoded EIP-155 signed Ethereum tx and maps it
+// onto an in-shard tx of fullShardKey, paying gas and transferring value in tokenID.
+// The Ethereum signature is kept, so the tx has version EthTxVersion.
+func DecodeEthTransaction(encodedTx []byte, networkId uint32, fullShardKey uint32, tokenID uint64) (*EvmTransaction, error) {
+	ethTx := new(ethTxdata)
+	if err := rlp.DecodeBytes(encodedTx, ethTx); err != nil {
+		return nil, err
+	}
+	tx := newEvmTransaction(ethTx.AccountNonce, ethTx.Recipient, ethTx.Amount, ethTx.GasLimit, ethTx.Price,
+		fullShardKey, fullShardKey, networkId, EthTxVersion, ethTx.Payload, tokenID, tokenID)
+	tx.SetVRS(ethTx.V, ethTx.R, ethTx.S)
+	if _, err := tx.EthChainID(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// EthChainID returns the EIP-155 chain id the tx is signed for, which is encoded
+// in V as chainId*2+35 or chainId*2+36. It is only defined for version EthTxVersion.
+func (tx *EvmTransaction) EthChainID() (uint64, error) {
+	if tx.data.Version != EthTxVersion || tx.data.V.BitLen() > 64 || tx.data.V.Uint64() < 35 {
+		return 0, ErrInvalidEthChainID
+	}
+	return (tx.data.V.Uint64() - 35) / 2, nil
+}
+
+// EthHash returns the hash of the Ethereum tx a tx of version EthTxVersion is mapped
+// from, which is the hash Ethereum tools know the tx by.
+func (tx *EvmTransaction) EthHash() common.Hash {
+	return rlpHash(&ethTxdata{
+		AccountNonce: tx.data.AccountNonce,
+		Price:        tx.data.Price,
+		GasLimit:     tx.data.GasLimit,
+		Recipient:    tx.data.Recipient,
+		Amount:       tx.data.Amount,
+		Payload:      tx.data.Payload,
+		V:            tx.data.V,
+		R:            tx.data.R,
+		S:            tx.data.S,
+	})
+}
+
+// ethSigHash returns the EIP-155 hash signed by the sender of an Ethereum tx.
+func (tx *EvmTransaction) ethSigHash(chainID uint64) common.Hash {
+	return rlpHash([]interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		chainID, uint(0), uint(0),
+	})
+}
+
 func (tx *EvmTransaction) typedHash() (common.Hash, error) {
 	sigHash, err := typedSignatureHash(evmTxToTypedData(tx))
 	if err != nil {
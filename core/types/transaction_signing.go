@@ -107,6 +107,14 @@ func (s EIP155Signer) Sender(tx *EvmTransaction) (account.Recipient, error) {
 			return account.Recipient{}, err
 		}
 		return recoverPlain(hashTyped, tx.data.R, tx.data.S, tx.data.V, true)
+	} else if tx.data.Version == EthTxVersion {
+		chainID, err := tx.EthChainID()
+		if err != nil {
+			return account.Recipient{}, err
+		}
+		// remove chainId*2+8 from V to get the recovery id plus 27
+		V := new(big.Int).Sub(tx.data.V, new(big.Int).SetUint64(chainID*2+8))
+		return recoverPlain(tx.ethSigHash(chainID), tx.data.R, tx.data.S, V, true)
 	} else {
 		return account.Recipient{}, fmt.Errorf("Version %d is not suppot", tx.data.Version)
 	}
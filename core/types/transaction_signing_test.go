@@ -20,7 +20,10 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 func TestEIP155Signing(t *testing.T) {
@@ -41,3 +44,52 @@ func TestEIP155Signing(t *testing.T) {
 		t.Errorf("exected from and address to be equal. Got %x want %x", from, recipient)
 	}
 }
+
+func TestEthTxSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x000000000000000000000000000000000000abcd")
+	ethTx, err := ethTypes.SignTx(ethTypes.NewTransaction(3, to, big.NewInt(100), 21000, big.NewInt(1), []byte{1}),
+		ethTypes.NewEIP155Signer(big.NewInt(100001)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedTx, err := rlp.EncodeToBytes(ethTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := DecodeEthTransaction(encodedTx, 3, 1<<16, 35760)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainID, err := tx.EthChainID(); err != nil || chainID != 100001 {
+		t.Errorf("expected eth chain id 100001, got %d %v", chainID, err)
+	}
+	if tx.Nonce() != 3 || *tx.To() != to || tx.Value().Int64() != 100 || tx.FromFullShardKey() != 1<<16 || tx.GasTokenID() != 35760 {
+		t.Errorf("tx fields not mapped")
+	}
+	if tx.EthHash() != ethTx.Hash() || tx.EthHash() != crypto.Keccak256Hash(encodedTx) {
+		t.Errorf("expected eth hash %x, got %x", ethTx.Hash(), tx.EthHash())
+	}
+	from, err := Sender(NewEIP155Signer(3), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("exected from and address to be equal. Got %x want %x", from, crypto.PubkeyToAddress(key.PublicKey))
+	}
+
+	// a tx without replay protection is rejected
+	ethTx, err = ethTypes.SignTx(ethTypes.NewTransaction(3, to, big.NewInt(100), 21000, big.NewInt(1), nil),
+		ethTypes.HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedTx, err = rlp.EncodeToBytes(ethTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeEthTransaction(encodedTx, 3, 1<<16, 35760); err != ErrInvalidEthChainID {
+		t.Errorf("expected %v, got %v", ErrInvalidEthChainID, err)
+	}
+}
@@ -0,0 +1,106 @@
+package tracers
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TxTrace is the trace of a single xshard deposit or tx of a traced block.
+type TxTrace struct {
+	TxHash        common.Hash
+	XShardDeposit bool
+	Tracer        vm.Tracer
+	Receipt       *types.Receipt // nil for deposits applied before evm is enabled
+}
+
+// BlockTracer traces each xshard deposit and tx of a block processed with it
+// attached to the evm with a tracer of its own, which is created by newTracer.
+// It implements core.BlockTracer.
+type BlockTracer struct {
+	newTracer func(ctx *Context) vm.Tracer
+	current   *TxTrace
+	traces    []*TxTrace
+}
+
+// NewBlockTracer returns a BlockTracer creating the tracer of each xshard
+// deposit and tx with newTracer.
+func NewBlockTracer(newTracer func(ctx *Context) vm.Tracer) *BlockTracer {
+	return &BlockTracer{newTracer: newTracer}
+}
+
+// Traces returns the traces of the xshard deposits and txs in the order they
+// were applied.
+func (t *BlockTracer) Traces() []*TxTrace {
+	return t.traces
+}
+
+func (t *BlockTracer) start(trace *TxTrace, gasLimit uint64) {
+	trace.Tracer = t.newTracer(&Context{GasLimit: gasLimit})
+	t.current = trace
+	t.traces = append(t.traces, trace)
+}
+
+// CaptureXShardDepositStart starts the trace of a deposit received from another shard.
+func (t *BlockTracer) CaptureXShardDepositStart(deposit *types.CrossShardTransactionDeposit) {
+	t.start(&TxTrace{TxHash: deposit.TxHash, XShardDeposit: true}, deposit.GasRemained.Value.Uint64())
+}
+
+// CaptureTxStart starts the trace of a tx of the block.
+func (t *BlockTracer) CaptureTxStart(tx *types.Transaction) {
+	t.start(&TxTrace{TxHash: tx.Hash()}, tx.EvmTx.Gas())
+}
+
+// CaptureTxEnd ends the trace started last.
+func (t *BlockTracer) CaptureTxEnd(receipt *types.Receipt) {
+	if t.current != nil {
+		t.current.Receipt = receipt
+		t.current = nil
+	}
+}
+
+// CaptureStart implements vm.Tracer.
+func (t *BlockTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	if t.current == nil {
+		return nil
+	}
+	return t.current.Tracer.CaptureStart(env, from, to, create, input, gas, value)
+}
+
+// CaptureState implements vm.Tracer.
+func (t *BlockTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.current == nil {
+		return nil
+	}
+	return t.current.Tracer.CaptureState(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+}
+
+// CaptureFault implements vm.Tracer.
+func (t *BlockTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.current == nil {
+		return nil
+	}
+	return t.current.Tracer.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+}
+
+// CaptureEnd implements vm.Tracer.
+func (t *BlockTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if t.current == nil {
+		return nil
+	}
+	return t.current.Tracer.CaptureEnd(output, gasUsed, d, err)
+}
+
+// CaptureXShardDeposit implements vm.XShardTracer.
+func (t *BlockTracer) CaptureXShardDeposit(env *vm.EVM, deposit *types.CrossShardTransactionDeposit) error {
+	if t.current == nil {
+		return nil
+	}
+	if tracer, ok := t.current.Tracer.(vm.XShardTracer); ok {
+		return tracer.CaptureXShardDeposit(env, deposit)
+	}
+	return nil
+}
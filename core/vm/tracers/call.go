@@ -0,0 +1,268 @@
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	xShardCallType   = "XSHARD_CALL"
+	xShardCreateType = "XSHARD_CREATE"
+)
+
+// callFrame is a single call in the call tree of a transaction.
+type callFrame struct {
+	Type            string          `json:"type"`
+	From            common.Address  `json:"from"`
+	To              common.Address  `json:"to"`
+	ToFullShardKey  *hexutil.Uint   `json:"toFullShardKey,omitempty"`
+	Value           *hexutil.Big    `json:"value,omitempty"`
+	TransferTokenID *hexutil.Uint64 `json:"transferTokenId,omitempty"`
+	Gas             *hexutil.Uint64 `json:"gas,omitempty"`
+	GasUsed         *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	Input           hexutil.Bytes   `json:"input"`
+	Output          hexutil.Bytes   `json:"output,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	Calls           []*callFrame    `json:"calls,omitempty"`
+
+	gasIn   uint64
+	gasCost uint64
+	outOff  *big.Int
+	outLen  *big.Int
+}
+
+func (f *callFrame) setValue(value *big.Int, tokenID uint64) {
+	f.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	f.TransferTokenID = (*hexutil.Uint64)(&tokenID)
+}
+
+// callTracer builds the tree of the internal calls made by a transaction, the
+// same way as the callTracer of go-ethereum does.
+//
+// Besides the plain evm calls it reports the token transfers made through the
+// transferMnt precompile as a CALL from the calling contract to the recipient
+// in the transferred token, and a transaction sent to another shard as an
+// XSHARD_CALL or XSHARD_CREATE frame since it never runs in the local evm.
+type callTracer struct {
+	root      *callFrame
+	callstack []*callFrame // callstack[i] is the frame running at depth i+1
+	descended bool
+}
+
+func newCallTracer(*Context) Tracer {
+	return new(callTracer)
+}
+
+// CaptureStart implements vm.Tracer. It is called for the message of the
+// transaction, and again for the inner call if the transaction is sent to the
+// transferMnt precompile directly.
+func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	frame := &callFrame{
+		Type:  "CALL",
+		From:  from,
+		To:    to,
+		Input: common.CopyBytes(input),
+		Gas:   (*hexutil.Uint64)(&gas),
+	}
+	if create {
+		frame.Type = "CREATE"
+	}
+	frame.setValue(value, env.TransferTokenID)
+	if t.root == nil {
+		t.root = frame
+	} else {
+		t.root.Calls = append(t.root.Calls, frame)
+	}
+	t.callstack = []*callFrame{frame}
+	return nil
+}
+
+// CaptureState implements vm.Tracer, tracking the frames entered and left by
+// the call and create opcodes.
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	if t.descended {
+		// The gas available to a call is only known once the callee runs,
+		// calls to accounts without code do not report any gas.
+		if depth >= len(t.callstack) {
+			t.callstack[len(t.callstack)-1].Gas = (*hexutil.Uint64)(&gas)
+		}
+		t.descended = false
+	}
+	if depth == len(t.callstack)-1 {
+		t.exit(env, memory, stack, gas)
+	}
+	if err != nil {
+		return t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		frame := &callFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			Input:   memorySlice(memory, stack.Back(1), stack.Back(2)),
+			gasIn:   gas,
+			gasCost: cost,
+		}
+		frame.setValue(stack.Back(0), env.TransferTokenID)
+		t.callstack = append(t.callstack, frame)
+		t.descended = true
+
+	case vm.SELFDESTRUCT:
+		tokenID := env.StateDB.GetQuarkChainConfig().GetDefaultChainTokenID()
+		frame := &callFrame{
+			Type: op.String(),
+			From: contract.Address(),
+			To:   common.BigToAddress(stack.Back(0)),
+		}
+		frame.setValue(env.StateDB.GetBalance(contract.Address(), tokenID), tokenID)
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, frame)
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		to := common.BigToAddress(stack.Back(1))
+		off := 0
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 1
+		}
+		input := memorySlice(memory, stack.Back(3-off), stack.Back(4-off))
+		frame := &callFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			To:      to,
+			Input:   input,
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  new(big.Int).Set(stack.Back(5 - off)),
+			outLen:  new(big.Int).Set(stack.Back(6 - off)),
+		}
+		if isPrecompiled(env, to) {
+			// The transferMnt precompile calls the recipient on behalf of the
+			// caller, so the frame stands for the transfer it makes. Other
+			// precompiles are not reported.
+			transfer := decodeMntTransfer(input)
+			if op != vm.CALL || to != transferMntAddr || transfer == nil {
+				return nil
+			}
+			frame.To = transfer.to
+			frame.Input = transfer.data
+			frame.setValue(transfer.value, transfer.tokenID)
+		} else if off == 0 {
+			frame.setValue(stack.Back(2), env.TransferTokenID)
+		}
+		t.callstack = append(t.callstack, frame)
+		t.descended = true
+
+	case vm.REVERT:
+		t.callstack[len(t.callstack)-1].Error = "execution reverted"
+	}
+	return nil
+}
+
+// exit pops the frame of the call which has just returned to the current
+// depth and fills its results.
+func (t *callTracer) exit(env *vm.EVM, memory *vm.Memory, stack *vm.Stack, gas uint64) {
+	frame := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+
+	success := len(stack.Data()) > 0 && stack.Back(0).Sign() != 0
+	if frame.Type == vm.CREATE.String() || frame.Type == vm.CREATE2.String() {
+		gasUsed := frame.gasIn - frame.gasCost - gas
+		frame.GasUsed = (*hexutil.Uint64)(&gasUsed)
+		if success {
+			frame.To = common.BigToAddress(stack.Back(0))
+			frame.Output = common.CopyBytes(env.StateDB.GetCode(frame.To))
+		}
+	} else {
+		if frame.Gas != nil {
+			gasUsed := frame.gasIn - frame.gasCost + uint64(*frame.Gas) - gas
+			frame.GasUsed = (*hexutil.Uint64)(&gasUsed)
+		}
+		if success {
+			frame.Output = memorySlice(memory, frame.outOff, frame.outLen)
+		}
+	}
+	if !success && frame.Error == "" {
+		frame.Error = "internal failure"
+	}
+	parent := t.callstack[len(t.callstack)-1]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+// CaptureFault implements vm.Tracer, failing the frame running at the given
+// depth. A failure of the outermost frame is reported by CaptureEnd.
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if len(t.callstack) <= 1 || depth != len(t.callstack) {
+		return nil
+	}
+	frame := t.callstack[len(t.callstack)-1]
+	if frame.Error != "" {
+		return nil
+	}
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	frame.Error = err.Error()
+	if frame.Gas != nil {
+		frame.GasUsed = frame.Gas
+	}
+	parent := t.callstack[len(t.callstack)-1]
+	parent.Calls = append(parent.Calls, frame)
+	return nil
+}
+
+// CaptureEnd implements vm.Tracer, closing the frame opened by CaptureStart.
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	frame := t.callstack[0]
+	frame.GasUsed = (*hexutil.Uint64)(&gasUsed)
+	frame.Output = common.CopyBytes(output)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	// Back to the transaction itself after the inner call of transferMnt.
+	t.callstack = []*callFrame{t.root}
+	return nil
+}
+
+// CaptureXShardDeposit implements vm.XShardTracer, recording the transaction
+// sent to another shard.
+func (t *callTracer) CaptureXShardDeposit(env *vm.EVM, deposit *types.CrossShardTransactionDeposit) error {
+	frame := &callFrame{
+		Type:  xShardCallType,
+		From:  deposit.From.Recipient,
+		To:    deposit.To.Recipient,
+		Input: common.CopyBytes(deposit.MessageData),
+	}
+	toFullShardKey := hexutil.Uint(deposit.To.FullShardKey)
+	frame.ToFullShardKey = &toFullShardKey
+	if deposit.CreateContract {
+		frame.Type = xShardCreateType
+	}
+	frame.setValue(deposit.Value.Value, deposit.TransferTokenID)
+	gas := deposit.GasRemained.Value.Uint64()
+	frame.Gas = (*hexutil.Uint64)(&gas)
+	if t.root == nil {
+		t.root = frame
+	} else {
+		t.root.Calls = append(t.root.Calls, frame)
+	}
+	return nil
+}
+
+// GetResult implements Tracer, returning the root frame of the call tree.
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if t.root == nil {
+		return nil, errors.New("no call captured")
+	}
+	return json.Marshal(t.root)
+}
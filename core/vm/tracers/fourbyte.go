@@ -0,0 +1,93 @@
+package tracers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fourByteTracer counts the 4 byte function selectors of the calls made by a
+// transaction together with the size of the call data, as "selector-size".
+//
+// The call data forwarded by the transferMnt precompile is counted for the
+// recipient instead of the input of the precompile itself.
+type fourByteTracer struct {
+	ids map[string]int
+}
+
+func newFourByteTracer(*Context) Tracer {
+	return &fourByteTracer{ids: make(map[string]int)}
+}
+
+func (t *fourByteTracer) store(input []byte) {
+	if len(input) < 4 {
+		return
+	}
+	t.ids[fmt.Sprintf("0x%x-%d", input[:4], len(input)-4)]++
+}
+
+// CaptureStart implements vm.Tracer. The inner call made by the transferMnt
+// precompile for a transaction sent to it is reported here as well.
+func (t *fourByteTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	if !create && !isPrecompiled(env, to) {
+		t.store(input)
+	}
+	return nil
+}
+
+// CaptureState implements vm.Tracer, collecting the selectors of the calls
+// made by contracts.
+func (t *fourByteTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil {
+		return nil
+	}
+	off := 0
+	switch op {
+	case vm.CALL, vm.CALLCODE:
+	case vm.DELEGATECALL, vm.STATICCALL:
+		off = 1
+	default:
+		return nil
+	}
+	to := common.BigToAddress(stack.Back(1))
+	input := memorySlice(memory, stack.Back(3-off), stack.Back(4-off))
+	if isPrecompiled(env, to) {
+		// The recipient of transferMnt is called by the precompile without
+		// a step of its own, so its call data is taken from the input.
+		if transfer := decodeMntTransfer(input); op == vm.CALL && to == transferMntAddr && transfer != nil {
+			t.store(transfer.data)
+		}
+		return nil
+	}
+	t.store(input)
+	return nil
+}
+
+// CaptureFault implements vm.Tracer.
+func (t *fourByteTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements vm.Tracer.
+func (t *fourByteTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	return nil
+}
+
+// CaptureXShardDeposit implements vm.XShardTracer, counting the call data of
+// the transaction sent to another shard.
+func (t *fourByteTracer) CaptureXShardDeposit(env *vm.EVM, deposit *types.CrossShardTransactionDeposit) error {
+	if !deposit.CreateContract {
+		t.store(deposit.MessageData)
+	}
+	return nil
+}
+
+// GetResult implements Tracer, returning the counts by selector and size.
+func (t *fourByteTracer) GetResult() (json.RawMessage, error) {
+	return json.Marshal(t.ids)
+}
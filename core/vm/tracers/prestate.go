@@ -0,0 +1,204 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"time"
+
+	qkcCommon "github.com/QuarkChain/goquarkchain/common"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type prestateBalance struct {
+	TokenID  hexutil.Uint64 `json:"tokenId"`
+	TokenStr string         `json:"tokenStr"`
+	Balance  *hexutil.Big   `json:"balance"`
+}
+
+type prestateAccount struct {
+	Balances []prestateBalance           `json:"balances"`
+	Nonce    uint64                      `json:"nonce"`
+	Code     hexutil.Bytes               `json:"code"`
+	Storage  map[common.Hash]common.Hash `json:"storage,omitempty"`
+
+	balances map[uint64]*big.Int
+}
+
+// prestateTracer collects the accounts touched by a transaction as they were
+// before it was applied, with the balances of all their tokens.
+//
+// The sender and the recipient are only looked up once the gas is bought and
+// the value is transferred, so their balances are corrected with the gas
+// limit and the value of the transaction, and the token transferred through
+// the transferMnt precompile.
+type prestateTracer struct {
+	ctx      *Context
+	prestate map[common.Address]*prestateAccount
+	created  map[common.Address]bool
+	started  bool
+}
+
+func newPrestateTracer(ctx *Context) Tracer {
+	return &prestateTracer{
+		ctx:      ctx,
+		prestate: make(map[common.Address]*prestateAccount),
+		created:  make(map[common.Address]bool),
+	}
+}
+
+// lookupAccount records the current state of addr if it was not seen before
+// and returns the recorded account, or nil if addr is created by the
+// transaction or was already recorded.
+func (t *prestateTracer) lookupAccount(db vm.StateDB, addr common.Address) *prestateAccount {
+	if _, ok := t.prestate[addr]; ok || t.created[addr] {
+		return nil
+	}
+	acc := &prestateAccount{balances: make(map[uint64]*big.Int)}
+	t.prestate[addr] = acc
+	// GetNonce and GetBalances create missing accounts, which must not be
+	// done by a tracer.
+	if !db.Exist(addr) {
+		return acc
+	}
+	acc.Nonce = db.GetNonce(addr)
+	acc.Code = common.CopyBytes(db.GetCode(addr))
+	for tokenID, balance := range db.GetBalances(addr).GetBalanceMap() {
+		acc.balances[tokenID] = new(big.Int).Set(balance)
+	}
+	return acc
+}
+
+func (t *prestateTracer) lookupStorage(db vm.StateDB, addr common.Address, key common.Hash) {
+	acc := t.prestate[addr]
+	if acc == nil {
+		return
+	}
+	if acc.Storage == nil {
+		acc.Storage = make(map[common.Hash]common.Hash)
+	}
+	if _, ok := acc.Storage[key]; !ok {
+		acc.Storage[key] = db.GetState(addr, key)
+	}
+}
+
+func (acc *prestateAccount) addBalance(tokenID uint64, amount *big.Int) {
+	balance, ok := acc.balances[tokenID]
+	if !ok {
+		balance = new(big.Int)
+		acc.balances[tokenID] = balance
+	}
+	balance.Add(balance, amount)
+}
+
+// CaptureStart implements vm.Tracer. It is called for the message of the
+// transaction, and again for the inner call if the transaction is sent to the
+// transferMnt precompile directly.
+func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	db := env.StateDB
+	if !t.started {
+		t.started = true
+		// A cross-shard deposit is credited to the sender before it is
+		// transferred, so only a local transaction has bought gas and
+		// increased the nonce of the sender.
+		if acc := t.lookupAccount(db, from); acc != nil && !env.IsApplyXShard {
+			t.revertPurchase(env, acc)
+			if from != to {
+				acc.addBalance(env.TransferTokenID, value)
+			}
+		}
+	}
+	if create {
+		t.created[to] = true
+		return nil
+	}
+	if acc := t.lookupAccount(db, to); acc != nil && from != to {
+		acc.addBalance(env.TransferTokenID, new(big.Int).Neg(value))
+	}
+	return nil
+}
+
+// revertPurchase reverts the nonce and the gas of the sender of a local
+// transaction.
+func (t *prestateTracer) revertPurchase(env *vm.EVM, acc *prestateAccount) {
+	acc.Nonce--
+	acc.addBalance(env.GasTokenID, new(big.Int).Mul(env.GasPrice, new(big.Int).SetUint64(t.ctx.GasLimit)))
+}
+
+// CaptureState implements vm.Tracer, recording the accounts and the storage
+// slots accessed by each step.
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil {
+		return nil
+	}
+	db := env.StateDB
+	t.lookupAccount(db, contract.Address())
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(db, contract.Address(), common.BigToHash(stack.Back(0)))
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH, vm.SELFDESTRUCT:
+		t.lookupAccount(db, common.BigToAddress(stack.Back(0)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		to := common.BigToAddress(stack.Back(1))
+		t.lookupAccount(db, to)
+		if op == vm.CALL && to == transferMntAddr && isPrecompiled(env, to) {
+			input := memorySlice(memory, stack.Back(3), stack.Back(4))
+			if transfer := decodeMntTransfer(input); transfer != nil {
+				t.lookupAccount(db, transfer.to)
+			}
+		}
+	case vm.CREATE:
+		t.created[vm.CreateAddress(contract.Address(), env.Context.ToFullShardKey, db.GetNonce(contract.Address()))] = true
+	case vm.CREATE2:
+		initCode := memorySlice(memory, stack.Back(1), stack.Back(2))
+		t.created[crypto.CreateAddress2(contract.Address(), common.BigToHash(stack.Back(3)), crypto.Keccak256(initCode))] = true
+	}
+	return nil
+}
+
+// CaptureFault implements vm.Tracer.
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements vm.Tracer.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	return nil
+}
+
+// CaptureXShardDeposit implements vm.XShardTracer. Only the sender of a
+// transaction sent to another shard is touched in the source shard.
+func (t *prestateTracer) CaptureXShardDeposit(env *vm.EVM, deposit *types.CrossShardTransactionDeposit) error {
+	if acc := t.lookupAccount(env.StateDB, deposit.From.Recipient); acc != nil {
+		t.revertPurchase(env, acc)
+		acc.addBalance(deposit.TransferTokenID, deposit.Value.Value)
+	}
+	return nil
+}
+
+// GetResult implements Tracer, returning the recorded accounts by address.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	for _, acc := range t.prestate {
+		acc.Balances = make([]prestateBalance, 0, len(acc.balances))
+		for tokenID, balance := range acc.balances {
+			if balance.Sign() == 0 {
+				continue
+			}
+			tokenStr, err := qkcCommon.TokenIdDecode(tokenID)
+			if err != nil {
+				return nil, err
+			}
+			acc.Balances = append(acc.Balances, prestateBalance{
+				TokenID:  hexutil.Uint64(tokenID),
+				TokenStr: tokenStr,
+				Balance:  (*hexutil.Big)(balance),
+			})
+		}
+		sort.Slice(acc.Balances, func(i, j int) bool { return acc.Balances[i].TokenID < acc.Balances[j].TokenID })
+	}
+	return json.Marshal(t.prestate)
+}
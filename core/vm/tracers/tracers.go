@@ -0,0 +1,91 @@
+// Package tracers implements the named evm tracers which can be selected by
+// the debug tracing APIs.
+package tracers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/QuarkChain/goquarkchain/core/vm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tracer is a vm.Tracer which aggregates what it captured into a JSON result.
+type Tracer interface {
+	vm.Tracer
+	// GetResult returns the JSON encoded result once the execution is done.
+	GetResult() (json.RawMessage, error)
+}
+
+// Context contains the information about the traced transaction which is not
+// available from within the evm.
+type Context struct {
+	GasLimit uint64 // gas limit of the transaction, which is bought before it runs
+}
+
+var lookup = map[string]func(ctx *Context) Tracer{
+	"callTracer":     newCallTracer,
+	"prestateTracer": newPrestateTracer,
+	"4byteTracer":    newFourByteTracer,
+}
+
+// New returns a new instance of the tracer with the given name.
+func New(name string, ctx *Context) (Tracer, error) {
+	if ctx == nil {
+		ctx = new(Context)
+	}
+	if newTracer, ok := lookup[name]; ok {
+		return newTracer(ctx), nil
+	}
+	return nil, fmt.Errorf("tracer %s not found", name)
+}
+
+// isPrecompiled returns whether addr is a precompiled contract which is
+// already enabled at the time of the current block.
+func isPrecompiled(env *vm.EVM, addr common.Address) bool {
+	p, ok := vm.PrecompiledContractsByzantium[addr]
+	return ok && env.StateDB.GetTimeStamp() > p.GetEnableTime()
+}
+
+var transferMntAddr = common.HexToAddress(vm.TransferMntAddr)
+
+// mntTransfer is the token transfer requested from the transferMnt precompile.
+type mntTransfer struct {
+	to      common.Address
+	tokenID uint64
+	value   *big.Int
+	data    []byte
+}
+
+// decodeMntTransfer decodes the input of the transferMnt precompile, which is
+// the recipient, the token id and the value as 32 bytes words followed by the
+// call data. It returns nil if the input is too short to be accepted.
+func decodeMntTransfer(input []byte) *mntTransfer {
+	if len(input) < 96 {
+		return nil
+	}
+	return &mntTransfer{
+		to:      common.BytesToAddress(input[:32]),
+		tokenID: new(big.Int).SetBytes(input[32:64]).Uint64(),
+		value:   new(big.Int).SetBytes(input[64:96]),
+		data:    common.CopyBytes(input[96:]),
+	}
+}
+
+// memorySlice returns a copy of the given memory range, cut to the size of
+// the memory.
+func memorySlice(memory *vm.Memory, offset, size *big.Int) []byte {
+	if !offset.IsUint64() || !size.IsUint64() || size.Sign() == 0 {
+		return nil
+	}
+	start, length := offset.Uint64(), size.Uint64()
+	end := uint64(memory.Len())
+	if start >= end {
+		return nil
+	}
+	if length > end-start {
+		length = end - start
+	}
+	return common.CopyBytes(memory.Data()[start : start+length])
+}
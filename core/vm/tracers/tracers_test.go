@@ -0,0 +1,35 @@
+package tracers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeMntTransfer(t *testing.T) {
+	to := common.HexToAddress("0x000000000000000000000000000000000000abcd")
+	input := common.LeftPadBytes(to.Bytes(), 32)
+	input = append(input, common.LeftPadBytes(big.NewInt(35760).Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+
+	assert.Nil(t, decodeMntTransfer(input[:95]))
+
+	transfer := decodeMntTransfer(append(input, 0xc2, 0xe1, 0x71, 0xd7))
+	assert.NotNil(t, transfer)
+	assert.Equal(t, to, transfer.to)
+	assert.Equal(t, uint64(35760), transfer.tokenID)
+	assert.Equal(t, int64(1000), transfer.value.Int64())
+	assert.Equal(t, []byte{0xc2, 0xe1, 0x71, 0xd7}, transfer.data)
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"callTracer", "prestateTracer", "4byteTracer"} {
+		tracer, err := New(name, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, tracer)
+	}
+	_, err := New("jsTracer", nil)
+	assert.Error(t, err)
+}
@@ -2,13 +2,16 @@ package encoder
 
 import (
 	"errors"
+	"fmt"
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/cluster/rpc"
 	"github.com/QuarkChain/goquarkchain/common"
 	"github.com/QuarkChain/goquarkchain/common/hexutil"
 	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/core/vm"
 	"github.com/QuarkChain/goquarkchain/serialize"
 	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 )
 
 func IDEncoder(hashByte []byte, fullShardKey uint32) hexutil.Bytes {
@@ -202,7 +205,24 @@ func MinorBlockEncoder(block *types.MinorBlock, includeTransaction bool, extraIn
 
 func TxEncoder(block *types.MinorBlock, i int) (map[string]interface{}, error) {
 	header := block.Header()
-	tx := block.Transactions()[i]
+	branch := block.Branch()
+	field, err := PendingTxEncoder(block.Transactions()[i])
+	if err != nil {
+		return nil, err
+	}
+	field["timestamp"] = hexutil.Uint64(header.Time)
+	field["fullShardId"] = hexutil.Uint64(header.Branch.GetFullShardID())
+	field["chainId"] = hexutil.Uint64(header.Branch.GetChainID())
+	field["shardId"] = hexutil.Uint64(header.Branch.GetShardID())
+	field["blockId"] = IDEncoder(header.Hash().Bytes(), branch.GetFullShardID())
+	field["blockHeight"] = hexutil.Uint64(header.Number)
+	field["transactionIndex"] = hexutil.Uint64(i)
+	return field, nil
+}
+
+// PendingTxEncoder encodes a tx which is not in a block yet, like the txs of the
+// tx pool, in the format of TxEncoder without the fields of the block.
+func PendingTxEncoder(tx *types.Transaction) (map[string]interface{}, error) {
 	evmtx := tx.EvmTx
 	v, r, s := evmtx.RawSignatureValues()
 	sender, err := types.Sender(types.MakeSigner(evmtx.NetworkId()), evmtx)
@@ -213,7 +233,6 @@ func TxEncoder(block *types.MinorBlock, i int) (map[string]interface{}, error) {
 	if evmtx.To() != nil {
 		toBytes = evmtx.To().Bytes()
 	}
-	branch := block.Branch()
 	transferTokenStr, err := common.TokenIdDecode(evmtx.TransferTokenID())
 	if err != nil {
 		return nil, err
@@ -226,13 +245,6 @@ func TxEncoder(block *types.MinorBlock, i int) (map[string]interface{}, error) {
 		"id":               IDEncoder(tx.Hash().Bytes(), evmtx.FromFullShardKey()),
 		"hash":             tx.Hash(),
 		"nonce":            hexutil.Uint64(evmtx.Nonce()),
-		"timestamp":        hexutil.Uint64(header.Time),
-		"fullShardId":      hexutil.Uint64(header.Branch.GetFullShardID()),
-		"chainId":          hexutil.Uint64(header.Branch.GetChainID()),
-		"shardId":          hexutil.Uint64(header.Branch.GetShardID()),
-		"blockId":          IDEncoder(header.Hash().Bytes(), branch.GetFullShardID()),
-		"blockHeight":      hexutil.Uint64(header.Number),
-		"transactionIndex": hexutil.Uint64(i),
 		"from":             DataEncoder(sender.Bytes()),
 		"to":               DataEncoder(toBytes),
 		"fromFullShardKey": FullShardKeyEncode(evmtx.FromFullShardKey()),
@@ -325,3 +337,179 @@ func ReceiptEncoder(block *types.MinorBlock, i int, receipt *types.Receipt) (map
 	}
 	return field, nil
 }
+
+func StructLogsEncoder(logs []vm.StructLog) []map[string]interface{} {
+	fields := make([]map[string]interface{}, 0, len(logs))
+	for _, trace := range logs {
+		field := map[string]interface{}{
+			"pc":      trace.Pc,
+			"op":      trace.Op.String(),
+			"gas":     trace.Gas,
+			"gasCost": trace.GasCost,
+			"depth":   trace.Depth,
+		}
+		if trace.Err != nil {
+			field["error"] = trace.Err.Error()
+		}
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, stackValue := range trace.Stack {
+				stack[i] = fmt.Sprintf("%x", math.PaddedBigBytes(stackValue, 32))
+			}
+			field["stack"] = stack
+		}
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
+			}
+			field["memory"] = memory
+		}
+		if trace.Storage != nil {
+			storage := make(map[string]string)
+			for k, v := range trace.Storage {
+				storage[fmt.Sprintf("%x", k)] = fmt.Sprintf("%x", v)
+			}
+			field["storage"] = storage
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func ExecutionResultEncoder(gas uint64, failed bool, returnValue []byte, logs []vm.StructLog) map[string]interface{} {
+	return map[string]interface{}{
+		"gas":         gas,
+		"failed":      failed,
+		"returnValue": fmt.Sprintf("%x", returnValue),
+		"structLogs":  StructLogsEncoder(logs),
+	}
+}
+
+// emptyUncleHash is the hash of an empty uncle list, minor blocks have no uncles.
+var emptyUncleHash = ethCommon.HexToHash("0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347")
+
+// EthMinorBlockEncoder encodes the minor block in the format of eth_getBlockByHash,
+// with the full txs if fullTx is set or only their hashes otherwise.
+func EthMinorBlockEncoder(block *types.MinorBlock, fullTx bool) (map[string]interface{}, error) {
+	serData, err := serialize.SerializeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	header := block.Header()
+	meta := block.Meta()
+	field := map[string]interface{}{
+		"number":           hexutil.Uint64(header.Number),
+		"hash":             header.Hash(),
+		"parentHash":       header.ParentHash,
+		"nonce":            hexutil.Bytes(common.Uint64ToBytes(header.Nonce)),
+		"mixHash":          header.MixDigest,
+		"sha3Uncles":       emptyUncleHash,
+		"logsBloom":        header.Bloom,
+		"transactionsRoot": meta.TxHash,
+		"stateRoot":        meta.Root,
+		"receiptsRoot":     meta.ReceiptHash,
+		"miner":            header.Coinbase.Recipient,
+		"difficulty":       (*hexutil.Big)(header.Difficulty),
+		"extraData":        hexutil.Bytes(header.Extra),
+		"size":             hexutil.Uint64(len(serData)),
+		"gasLimit":         (*hexutil.Big)(header.GasLimit.Value),
+		"gasUsed":          (*hexutil.Big)(meta.GasUsed.Value),
+		"timestamp":        hexutil.Uint64(header.Time),
+		"uncles":           []ethCommon.Hash{},
+	}
+	if fullTx {
+		txs := make([]map[string]interface{}, 0, len(block.Transactions()))
+		for i := range block.Transactions() {
+			tx, err := EthTxEncoder(block, i)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, tx)
+		}
+		field["transactions"] = txs
+	} else {
+		txHashes := make([]ethCommon.Hash, 0, len(block.Transactions()))
+		for _, tx := range block.Transactions() {
+			txHashes = append(txHashes, ethTxHash(tx))
+		}
+		field["transactions"] = txHashes
+	}
+	return field, nil
+}
+
+// ethTxHash returns the hash a tx is known by to Ethereum tools, which is the hash of
+// the Ethereum tx for a tx mapped from one.
+func ethTxHash(tx *types.Transaction) ethCommon.Hash {
+	if tx.EvmTx.Version() == types.EthTxVersion {
+		return tx.EvmTx.EthHash()
+	}
+	return tx.Hash()
+}
+
+// EthTxEncoder encodes the i-th tx of the block in the format of eth_getTransactionByHash.
+func EthTxEncoder(block *types.MinorBlock, i int) (map[string]interface{}, error) {
+	tx := block.Transactions()[i]
+	evmtx := tx.EvmTx
+	v, r, s := evmtx.RawSignatureValues()
+	sender, err := types.Sender(types.MakeSigner(evmtx.NetworkId()), evmtx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"hash":             ethTxHash(tx),
+		"nonce":            hexutil.Uint64(evmtx.Nonce()),
+		"blockHash":        block.Hash(),
+		"blockNumber":      hexutil.Uint64(block.NumberU64()),
+		"transactionIndex": hexutil.Uint64(i),
+		"from":             sender,
+		"to":               evmtx.To(),
+		"value":            (*hexutil.Big)(evmtx.Value()),
+		"gasPrice":         (*hexutil.Big)(evmtx.GasPrice()),
+		"gas":              hexutil.Uint64(evmtx.Gas()),
+		"input":            hexutil.Bytes(evmtx.Data()),
+		"chainId":          hexutil.Uint64(evmtx.NetworkId()),
+		"r":                (*hexutil.Big)(r),
+		"s":                (*hexutil.Big)(s),
+		"v":                (*hexutil.Big)(v),
+	}, nil
+}
+
+// EthReceiptEncoder encodes the receipt of the i-th tx of the block in the format of
+// eth_getTransactionReceipt. A receipt of a xshard deposit has no tx in the block,
+// so it is reported under txHash without sender and recipient.
+func EthReceiptEncoder(block *types.MinorBlock, i int, txHash ethCommon.Hash, receipt *types.Receipt) (map[string]interface{}, error) {
+	if block == nil {
+		return nil, errors.New("block is nil")
+	}
+	if receipt == nil {
+		return nil, errors.New("receipt is nil")
+	}
+	field := map[string]interface{}{
+		"transactionHash":   txHash,
+		"transactionIndex":  hexutil.Uint64(i),
+		"blockHash":         block.Hash(),
+		"blockNumber":       hexutil.Uint64(block.NumberU64()),
+		"from":              nil,
+		"to":                nil,
+		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
+		"gasUsed":           hexutil.Uint64(receipt.GasUsed - receipt.GetPrevGasUsed()),
+		"contractAddress":   nil,
+		"logs":              LogListEncoder(receipt.Logs, false),
+		"logsBloom":         receipt.Bloom,
+		"status":            hexutil.Uint64(receipt.Status),
+	}
+	if len(block.Transactions()) > i {
+		evmtx := block.Transactions()[i].EvmTx
+		sender, err := types.Sender(types.MakeSigner(evmtx.NetworkId()), evmtx)
+		if err != nil {
+			return nil, err
+		}
+		field["from"] = sender
+		field["to"] = evmtx.To()
+	}
+	if receipt.ContractAddress.Big().Uint64() != 0 {
+		field["contractAddress"] = receipt.ContractAddress
+	}
+	return field, nil
+}
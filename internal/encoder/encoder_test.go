@@ -0,0 +1,104 @@
+package encoder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/serialize"
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthEncoders(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	to := account.BytesToIdentityRecipient(ethCommon.HexToAddress("0x000000000000000000000000000000000000abcd").Bytes())
+	evmTx, err := types.SignTx(types.NewEvmTransaction(7, to, big.NewInt(100), 21000, big.NewInt(1), 0, 0, 3, 0, nil, 35760, 35760),
+		types.MakeSigner(3), key)
+	assert.NoError(t, err)
+	tx := &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}
+	header := &types.MinorBlockHeader{
+		Number:         12,
+		GasLimit:       &serialize.Uint256{Value: big.NewInt(30000)},
+		Difficulty:     big.NewInt(1000),
+		Coinbase:       account.CreatEmptyAddress(0),
+		CoinbaseAmount: types.NewEmptyTokenBalances(),
+	}
+	meta := &types.MinorBlockMeta{
+		GasUsed:            &serialize.Uint256{Value: big.NewInt(21000)},
+		CrossShardGasUsed:  &serialize.Uint256{Value: new(big.Int)},
+		XShardGasLimit:     &serialize.Uint256{Value: new(big.Int)},
+		XShardTxCursorInfo: &types.XShardTxCursorInfo{},
+	}
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, GasUsed: 21000}
+	block := types.NewMinorBlock(header, meta, []*types.Transaction{tx}, []*types.Receipt{receipt}, nil)
+
+	field, err := EthMinorBlockEncoder(block, false)
+	assert.NoError(t, err)
+	assert.Equal(t, block.Hash(), field["hash"])
+	assert.Equal(t, []ethCommon.Hash{tx.Hash()}, field["transactions"])
+	field, err = EthMinorBlockEncoder(block, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(field["transactions"].([]map[string]interface{})))
+
+	field, err = EthTxEncoder(block, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash(), field["hash"])
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), field["from"])
+	assert.Equal(t, evmTx.To(), field["to"])
+
+	field, err = EthReceiptEncoder(block, 0, tx.Hash(), receipt)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), field["from"])
+	assert.Nil(t, field["contractAddress"])
+
+	// the receipt of a xshard deposit has no tx in the block
+	depositHash := ethCommon.HexToHash("0x01")
+	field, err = EthReceiptEncoder(block, 1, depositHash, receipt)
+	assert.NoError(t, err)
+	assert.Equal(t, depositHash, field["transactionHash"])
+	assert.Nil(t, field["from"])
+}
+
+func TestTxEncoders(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	to := account.BytesToIdentityRecipient(ethCommon.HexToAddress("0x000000000000000000000000000000000000abcd").Bytes())
+	evmTx, err := types.SignTx(types.NewEvmTransaction(7, to, big.NewInt(100), 21000, big.NewInt(1), 0, 0, 3, 0, nil, 35760, 35760),
+		types.MakeSigner(3), key)
+	assert.NoError(t, err)
+	tx := &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}
+
+	field, err := PendingTxEncoder(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash(), field["hash"])
+	assert.Equal(t, DataEncoder(crypto.PubkeyToAddress(key.PublicKey).Bytes()), field["from"])
+	assert.Equal(t, "QKC", field["gasTokenStr"])
+	assert.Nil(t, field["blockId"])
+
+	header := &types.MinorBlockHeader{
+		Number:         12,
+		GasLimit:       &serialize.Uint256{Value: big.NewInt(30000)},
+		Difficulty:     big.NewInt(1000),
+		Coinbase:       account.CreatEmptyAddress(0),
+		CoinbaseAmount: types.NewEmptyTokenBalances(),
+	}
+	meta := &types.MinorBlockMeta{
+		GasUsed:            &serialize.Uint256{Value: big.NewInt(21000)},
+		CrossShardGasUsed:  &serialize.Uint256{Value: new(big.Int)},
+		XShardGasLimit:     &serialize.Uint256{Value: new(big.Int)},
+		XShardTxCursorInfo: &types.XShardTxCursorInfo{},
+	}
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, GasUsed: 21000}
+	block := types.NewMinorBlock(header, meta, []*types.Transaction{tx}, []*types.Receipt{receipt}, nil)
+	field, err = TxEncoder(block, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash(), field["hash"])
+	assert.Equal(t, "QKC", field["gasTokenStr"])
+	assert.Equal(t, IDEncoder(block.Hash().Bytes(), 0), field["blockId"])
+	assert.Equal(t, hexutil.Uint64(12), field["blockHeight"])
+}
@@ -0,0 +1,40 @@
+// Modified from go-ethereum under GNU Lesser General Public License
+
+package qkcapi
+
+import (
+	"sync"
+
+	"github.com/QuarkChain/goquarkchain/account"
+)
+
+// AddrLocker serializes the txs signed for an account by the node, so that the
+// nonce picked for a tx is not picked again before the tx is in the tx pool.
+type AddrLocker struct {
+	mu    sync.Mutex
+	locks map[account.Recipient]*sync.Mutex
+}
+
+// lock returns the lock of the given address.
+func (l *AddrLocker) lock(address account.Recipient) *sync.Mutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks == nil {
+		l.locks = make(map[account.Recipient]*sync.Mutex)
+	}
+	if _, ok := l.locks[address]; !ok {
+		l.locks[address] = new(sync.Mutex)
+	}
+	return l.locks[address]
+}
+
+// LockAddr locks the mutex of the given account, which prevents another tx from
+// getting the same nonce until the lock is released.
+func (l *AddrLocker) LockAddr(address account.Recipient) {
+	l.lock(address).Lock()
+}
+
+// UnlockAddr unlocks the mutex of the given account.
+func (l *AddrLocker) UnlockAddr(address account.Recipient) {
+	l.lock(address).Unlock()
+}
@@ -11,8 +11,10 @@ import (
 	qrpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
 	qcom "github.com/QuarkChain/goquarkchain/common"
 	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/core"
 	"github.com/QuarkChain/goquarkchain/core/types"
 	"github.com/QuarkChain/goquarkchain/internal/encoder"
+	"github.com/QuarkChain/goquarkchain/params"
 	"github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -109,9 +111,53 @@ func (c *CommonAPI) GetLogs(args *rpc.FilterQuery, fullShardKey *hexutil.Uint) (
 	args.FullShardId = fullShardID
 
 	log, err := c.b.GetLogs(args)
+	if err != nil {
+		return nil, err
+	}
 	return encoder.LogListEncoder(log, false), nil
 }
 
+// NewFilter creates a filter of the logs matching args in the shard of fullShardKey,
+// which is polled with GetFilterChanges and expires if it is not polled for 5 minutes.
+func (c *CommonAPI) NewFilter(args rpc.FilterQuery, fullShardKey *hexutil.Uint) (rpc.ID, error) {
+	fullShardID, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return "", err
+	}
+	args.FullShardId = fullShardID
+	return c.b.NewFilter(&args)
+}
+
+// NewBlockFilter creates a filter of the blocks appended to the shard of fullShardKey,
+// which is polled with GetFilterChanges and expires if it is not polled for 5 minutes.
+func (c *CommonAPI) NewBlockFilter(fullShardKey *hexutil.Uint) (rpc.ID, error) {
+	fullShardID, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return "", err
+	}
+	return c.b.NewBlockFilter(account.Branch{Value: fullShardID})
+}
+
+// GetFilterChanges returns the block hashes or the logs of the filter since it was last polled.
+func (c *CommonAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
+	hashes, logs, err := c.b.GetFilterChanges(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) > 0 {
+		return hashes, nil
+	}
+	fields := make([]map[string]interface{}, 0, len(logs))
+	for _, log := range logs {
+		fields = append(fields, encoder.LogEncoder(log, log.Removed))
+	}
+	return fields, nil
+}
+
+func (c *CommonAPI) UninstallFilter(id rpc.ID) (bool, error) {
+	return c.b.UninstallFilter(id)
+}
+
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicBlockChainAPI struct {
 	CommonAPI
@@ -267,6 +313,9 @@ func (p *PublicBlockChainAPI) SendTransaction(args SendTxArgs) (hexutil.Bytes, e
 	if err := args.setDefaults(clusterCfg.Quarkchain); err != nil {
 		return nil, err
 	}
+	if args.V == nil || args.R == nil || args.S == nil {
+		return nil, errors.New("missing v r s")
+	}
 	tx, err := args.toTransaction()
 	if err != nil {
 		return nil, err
@@ -277,6 +326,115 @@ func (p *PublicBlockChainAPI) SendTransaction(args SendTxArgs) (hexutil.Bytes, e
 	return encoder.IDEncoder(tx.Hash().Bytes(), tx.EvmTx.FromFullShardKey()), nil
 }
 
+// cancelTx returns the unsigned zero-value self-send replacing the pending tx
+// txID, with its nonce and shard, paying gas in gasTokenID or in the gas token
+// of the tx. Without gasPrice it pays the lowest price the tx pool accepts as a
+// replacement, converted across gas tokens at the gas prices paid in them in the
+// recent blocks of the shard.
+func (p *PublicBlockChainAPI) cancelTx(txID hexutil.Bytes, gasPrice *big.Int, gasTokenID *hexutil.Uint64) (*types.Transaction, error) {
+	txHash, fullShardKey, err := encoder.IDDecoder(txID)
+	if err != nil {
+		return nil, err
+	}
+	fullShardID, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	branch := account.Branch{Value: fullShardID}
+	mBlock, index, err := p.b.GetTransactionByHash(txHash, branch)
+	if err != nil {
+		return nil, err
+	}
+	if mBlock == nil || len(mBlock.Transactions()) <= int(index) {
+		return nil, errors.New("transaction not found")
+	}
+	_, _, receipt, err := p.b.GetTransactionReceipt(txHash, branch)
+	if err != nil {
+		return nil, err
+	}
+	if receipt != nil {
+		return nil, errors.New("transaction already included in a block")
+	}
+	old := mBlock.Transactions()[index]
+	evmTx := old.EvmTx
+	sender, err := types.Sender(types.MakeSigner(evmTx.NetworkId()), evmTx)
+	if err != nil {
+		return nil, err
+	}
+	tokenID := evmTx.GasTokenID()
+	if gasTokenID != nil {
+		tokenID = uint64(*gasTokenID)
+	}
+	if !clusterCfg.Quarkchain.IsAllowedTokenID(tokenID) {
+		return nil, fmt.Errorf("token %v is not allowed", tokenID)
+	}
+	if gasPrice == nil {
+		rate := func(tokenID uint64) *big.Int {
+			price, err := p.b.GasPriceRate(branch, tokenID)
+			if err != nil {
+				return nil
+			}
+			return new(big.Int).SetUint64(price)
+		}
+		if gasPrice = core.ReplacementGasPrice(old, tokenID, core.DefaultTxPoolConfig.PriceBump, rate); gasPrice == nil {
+			return nil, fmt.Errorf("no gas price of token %v to convert to", tokenID)
+		}
+		if gasPrice.Cmp(clusterCfg.Quarkchain.MinTXPoolGasPrice) < 0 {
+			gasPrice = new(big.Int).Set(clusterCfg.Quarkchain.MinTXPoolGasPrice)
+		}
+	}
+	cancel := types.NewEvmTransaction(evmTx.Nonce(), sender, new(big.Int), params.DefaultInShardTxGasLimit.Uint64(), gasPrice,
+		evmTx.FromFullShardKey(), evmTx.FromFullShardKey(), evmTx.NetworkId(), 0, nil, tokenID, tokenID)
+	return &types.Transaction{EvmTx: cancel, TxType: types.EvmTx}, nil
+}
+
+// GetCancelTransaction returns the unsigned zero-value self-send which cancels the
+// pending tx txID by replacing it, paying gas in gasTokenID or in the gas token of
+// the tx. Once signed by the sender of the tx, it is sent with sendTransaction.
+func (p *PublicBlockChainAPI) GetCancelTransaction(txID hexutil.Bytes, gasTokenID *hexutil.Uint64) (map[string]interface{}, error) {
+	tx, err := p.cancelTx(txID, nil, gasTokenID)
+	if err != nil {
+		return nil, err
+	}
+	evmTx := tx.EvmTx
+	return map[string]interface{}{
+		"to":                hexutil.Bytes(evmTx.To().Bytes()),
+		"gas":               (*hexutil.Big)(new(big.Int).SetUint64(evmTx.Gas())),
+		"gasPrice":          (*hexutil.Big)(evmTx.GasPrice()),
+		"value":             (*hexutil.Big)(evmTx.Value()),
+		"nonce":             hexutil.Uint64(evmTx.Nonce()),
+		"data":              hexutil.Bytes(evmTx.Data()),
+		"fromFullShardKey":  hexutil.Uint(evmTx.FromFullShardKey()),
+		"toFullShardKey":    hexutil.Uint(evmTx.ToFullShardKey()),
+		"networkId":         hexutil.Uint(evmTx.NetworkId()),
+		"gas_token_id":      hexutil.Uint64(evmTx.GasTokenID()),
+		"transfer_token_id": hexutil.Uint64(evmTx.TransferTokenID()),
+		"hashToSign":        types.MakeSigner(evmTx.NetworkId()).Hash(evmTx),
+	}, nil
+}
+
+// CancelTransaction cancels the pending tx txID by replacing it with the zero-value
+// self-send of GetCancelTransaction, at the gas price and token of args, signed by
+// the sender of the tx. The gas price is the one signed, as returned by
+// GetCancelTransaction. It returns the id of the self-send.
+func (p *PublicBlockChainAPI) CancelTransaction(txID hexutil.Bytes, args CancelTxArgs) (hexutil.Bytes, error) {
+	if args.GasPrice == nil {
+		return nil, errors.New("missing gasPrice")
+	}
+	if args.V == nil || args.R == nil || args.S == nil {
+		return nil, errors.New("missing v r s")
+	}
+	tx, err := p.cancelTx(txID, (*big.Int)(args.GasPrice), args.GasTokenID)
+	if err != nil {
+		return nil, err
+	}
+	tx.EvmTx.SetVRS(args.V.ToInt(), args.R.ToInt(), args.S.ToInt())
+	if err := p.b.AddTransaction(tx); err != nil {
+		return EmptyTxID, err
+	}
+	return encoder.IDEncoder(tx.Hash().Bytes(), tx.EvmTx.FromFullShardKey()), nil
+}
+
 func (p *PublicBlockChainAPI) GetRootBlockById(hash common.Hash, needExtraInfo *bool) (map[string]interface{}, error) {
 	if needExtraInfo == nil {
 		temp := true
@@ -654,6 +812,141 @@ func (p *PublicBlockChainAPI) GetTransactionConfirmedByNumberRootBlocks(txID hex
 
 }
 
+// GetCrossShardTransactionStatus reports how far the cross-shard tx txID has
+// gone: "pending" in the tx pool of the source shard, "included" in a minor
+// block, "confirmed" by a root block, or "applied" as a deposit in a block of
+// the destination shard. The blocks of each stage reached are reported too.
+// The root block is only known with ENABLE_TRANSACTION_HISTORY.
+func (p *PublicBlockChainAPI) GetCrossShardTransactionStatus(txID hexutil.Bytes) (map[string]interface{}, error) {
+	txHash, fullShardKey, err := encoder.IDDecoder(txID)
+	if err != nil {
+		return nil, err
+	}
+	fullShardID, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	branch := account.Branch{Value: fullShardID}
+	mBlock, index, err := p.b.GetTransactionByHash(txHash, branch)
+	if err != nil {
+		return nil, err
+	}
+	if mBlock == nil || len(mBlock.Transactions()) <= int(index) {
+		return nil, nil
+	}
+	evmTx := mBlock.Transactions()[index].EvmTx
+	// the shard sizes of the tx are not set once it is read back from the slave
+	if clusterCfg.Quarkchain.IsSameFullShard(evmTx.FromFullShardKey(), evmTx.ToFullShardKey()) {
+		return nil, errors.New("not a cross-shard transaction")
+	}
+	toFullShardID, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(evmTx.ToFullShardKey())
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{
+		"transactionId":   txID,
+		"transactionHash": txHash,
+		"fromFullShardId": hexutil.Uint64(fullShardID),
+		"toFullShardId":   hexutil.Uint64(toFullShardID),
+		"status":          "pending",
+	}
+
+	mBlock, _, receipt, err := p.b.GetTransactionReceipt(txHash, branch)
+	if err != nil {
+		return nil, err
+	}
+	if mBlock == nil || receipt == nil {
+		return fields, nil
+	}
+	fields["status"] = "included"
+	fields["minorBlock"] = map[string]interface{}{
+		"id":     encoder.IDEncoder(mBlock.Hash().Bytes(), fullShardID),
+		"hash":   mBlock.Hash(),
+		"height": hexutil.Uint64(mBlock.NumberU64()),
+	}
+
+	rHash := p.b.GetRootHashConfirmingMinorBlock(encoder.IDEncoder(mBlock.Hash().Bytes(), fullShardID))
+	if rHash == (common.Hash{}) {
+		return fields, nil
+	}
+	rBlock, _, err := p.b.GetRootBlockByHash(rHash, false)
+	if err != nil {
+		return nil, err
+	}
+	if rBlock == nil {
+		return nil, errors.New("confirming root block not found")
+	}
+	rHeight := rBlock.NumberU64()
+	canonicalBlock, _, err := p.b.GetRootBlockByNumber(&rHeight, false)
+	if err != nil {
+		return nil, err
+	}
+	if canonicalBlock == nil || canonicalBlock.Hash() != rHash {
+		// the confirming root block is reverted
+		return fields, nil
+	}
+	fields["status"] = "confirmed"
+	fields["rootBlock"] = map[string]interface{}{
+		"hash":   rHash,
+		"height": hexutil.Uint64(rHeight),
+	}
+
+	header, err := p.b.GetXShardDepositBlock(account.Branch{Value: toFullShardID}, rHash, mBlock.Hash(), txHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return fields, nil
+	}
+	fields["status"] = "applied"
+	fields["depositBlock"] = map[string]interface{}{
+		"id":     encoder.IDEncoder(header.Hash().Bytes(), toFullShardID),
+		"hash":   header.Hash(),
+		"height": hexutil.Uint64(header.Number),
+	}
+	return fields, nil
+}
+
+// GetCrossShardDepositReceipt returns the receipt of the deposit of the
+// cross-shard tx txID in its destination shard, or nil if the deposit is not
+// applied yet or was applied before evm is enabled.
+func (p *PublicBlockChainAPI) GetCrossShardDepositReceipt(txID hexutil.Bytes) (map[string]interface{}, error) {
+	txHash, fullShardKey, err := encoder.IDDecoder(txID)
+	if err != nil {
+		return nil, err
+	}
+	fullShardID, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	mBlock, index, err := p.b.GetTransactionByHash(txHash, account.Branch{Value: fullShardID})
+	if err != nil {
+		return nil, err
+	}
+	if mBlock == nil || len(mBlock.Transactions()) <= int(index) {
+		return nil, nil
+	}
+	evmTx := mBlock.Transactions()[index].EvmTx
+	if clusterCfg.Quarkchain.IsSameFullShard(evmTx.FromFullShardKey(), evmTx.ToFullShardKey()) {
+		return nil, errors.New("not a cross-shard transaction")
+	}
+	toFullShardID, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(evmTx.ToFullShardKey())
+	if err != nil {
+		return nil, err
+	}
+	mBlock, index, receipt, err := p.b.GetXShardDepositReceipt(txHash, account.Branch{Value: toFullShardID})
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	ret, err := encoder.ReceiptEncoder(mBlock, int(index), receipt)
+	if err != nil {
+		return nil, err
+	}
+	ret["transactionId"] = txID.String()
+	ret["transactionHash"] = txHash.String()
+	return ret, nil
+}
+
 func (p *PublicBlockChainAPI) NetVersion() hexutil.Uint {
 	return hexutil.Uint(clusterCfg.Quarkchain.NetworkID)
 }
@@ -837,3 +1130,85 @@ func (e *EthBlockChainAPI) GetStorageAt(address common.Address, key common.Hash,
 	hash, err := e.b.GetStorageAt(&addr, key, nil)
 	return hash.Bytes(), err
 }
+
+// ChainId returns the EIP-155 chain id of Ethereum txs sent to the chain of fullShardKey,
+// or the network id used to sign QuarkChain txs if Ethereum txs are not accepted.
+func (e *EthBlockChainAPI) ChainId(fullShardKey *hexutil.Uint) (hexutil.Uint64, error) {
+	fullShardId, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return 0, err
+	}
+	if ethChainID := clusterCfg.Quarkchain.GetEthChainID(fullShardId >> 16); ethChainID != 0 {
+		return hexutil.Uint64(ethChainID), nil
+	}
+	return hexutil.Uint64(clusterCfg.Quarkchain.NetworkID), nil
+}
+
+// SendRawTransaction adds the signed tx to the pool of its shard and returns the tx hash
+// rather than the tx id returned by qkc_sendRawTransaction. Besides QuarkChain txs, it
+// accepts EIP-155 signed Ethereum txs, which are sent in-shard to the first shard of the
+// chain of their chain id in the default token. The returned hash of an Ethereum tx is
+// keccak256 of the raw tx as Ethereum tools expect, which the shard maps back to the tx.
+func (e *EthBlockChainAPI) SendRawTransaction(encodedTx hexutil.Bytes) (common.Hash, error) {
+	evmTx, err := decodeRawTransaction(encodedTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx := &types.Transaction{
+		EvmTx:  evmTx,
+		TxType: types.EvmTx,
+	}
+	if err := e.b.AddTransaction(tx); err != nil {
+		return common.Hash{}, err
+	}
+	if evmTx.Version() == types.EthTxVersion {
+		// Ethereum tools expect keccak256 of the raw tx, which the shard maps back
+		return evmTx.EthHash(), nil
+	}
+	return tx.Hash(), nil
+}
+
+func (e *EthBlockChainAPI) GetBlockByHash(blockHash common.Hash, fullTx bool, fullShardKey *hexutil.Uint) (map[string]interface{}, error) {
+	fullShardId, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	minorBlock, _, err := e.b.GetMinorBlockByHash(blockHash, account.Branch{Value: fullShardId}, false)
+	if err != nil {
+		return nil, err
+	}
+	if minorBlock == nil {
+		return nil, nil
+	}
+	return encoder.EthMinorBlockEncoder(minorBlock, fullTx)
+}
+
+func (e *EthBlockChainAPI) GetTransactionByHash(txHash common.Hash, fullShardKey *hexutil.Uint) (map[string]interface{}, error) {
+	fullShardId, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	minorBlock, index, err := e.b.GetTransactionByHash(txHash, account.Branch{Value: fullShardId})
+	if err != nil {
+		return nil, err
+	}
+	if minorBlock == nil || int(index) >= len(minorBlock.Transactions()) {
+		return nil, nil
+	}
+	return encoder.EthTxEncoder(minorBlock, int(index))
+}
+
+func (e *EthBlockChainAPI) GetTransactionReceipt(txHash common.Hash, fullShardKey *hexutil.Uint) (map[string]interface{}, error) {
+	fullShardId, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	minorBlock, index, receipt, err := e.b.GetTransactionReceipt(txHash, account.Branch{Value: fullShardId})
+	if err != nil {
+		return nil, err
+	}
+	if minorBlock == nil || receipt == nil {
+		return nil, nil
+	}
+	return encoder.EthReceiptEncoder(minorBlock, int(index), txHash, receipt)
+}
@@ -17,13 +17,21 @@ type Backend interface {
 	GetMinorBlockByHeight(height *uint64, branch account.Branch, needExtraInfo bool) (*types.MinorBlock, *qrpc.PoSWInfo, error)
 	GetTransactionByHash(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, error)
 	GetTransactionReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error)
+	TraceTransaction(txHash common.Hash, branch account.Branch, config *qrpc.TraceConfig) ([]byte, error)
+	TraceCall(tx *types.Transaction, address *account.Address, height *uint64, config *qrpc.TraceConfig) ([]byte, error)
+	TraceBlockByHash(blockHash common.Hash, branch account.Branch, config *qrpc.TraceConfig) ([]byte, error)
 	GetTransactionsByAddress(address *account.Address, start []byte, limit uint32, transferTokenID *uint64) ([]*qrpc.TransactionDetail, []byte, error)
 	GetAllTx(branch account.Branch, start []byte, limit uint32) ([]*qrpc.TransactionDetail, []byte, error)
 	GetLogs(args *rpc.FilterQuery) ([]*types.Log, error)
+	NewFilter(args *rpc.FilterQuery) (rpc.ID, error)
+	NewBlockFilter(branch account.Branch) (rpc.ID, error)
+	GetFilterChanges(id rpc.ID) ([]common.Hash, []*types.Log, error)
+	UninstallFilter(id rpc.ID) (bool, error)
 	EstimateGas(tx *types.Transaction, address *account.Address) (uint32, error)
 	GetStorageAt(address *account.Address, key common.Hash, height *uint64) (common.Hash, error)
 	GetCode(address *account.Address, height *uint64) ([]byte, error)
 	GasPrice(branch account.Branch, tokenID uint64) (uint64, error)
+	GasPriceRate(branch account.Branch, tokenID uint64) (uint64, error)
 	GetWork(fullShardId *uint32, address *common.Address) (*consensus.MiningWork, error)
 	SubmitWork(fullShardId *uint32, headerHash common.Hash, nonce uint64, mixHash common.Hash, signature *[65]byte) (bool, error)
 	GetRootBlockByNumber(blockNr *uint64, needExtraInfo bool) (*types.RootBlock, *qrpc.PoSWInfo, error)
@@ -44,6 +52,10 @@ type Backend interface {
 	GetSlavePoolLen() int
 	GetLastMinorBlockByFullShardID(fullShardId uint32) (uint64, error)
 	GetRootHashConfirmingMinorBlock(mBlockID []byte) common.Hash
+	GetTxPoolContent(branch account.Branch) ([]*qrpc.TxPoolAccount, []*qrpc.TxPoolAccount, error)
+	GetTxPoolStatus(branch account.Branch) (uint32, uint32, error)
+	GetXShardDepositReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error)
+	GetXShardDepositBlock(branch account.Branch, rHash, mHash, txHash common.Hash) (*types.MinorBlockHeader, error)
 	// p2p discovery healty nodes
 	GetKadRoutingTable() ([]string, error)
 }
@@ -71,5 +83,17 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Service:   NewEthAPI(apiBackend),
 			Public:    true,
 		},
+		{
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   NewPublicTxPoolAPI(apiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(apiBackend),
+			Public:    false,
+		},
 	}
 }
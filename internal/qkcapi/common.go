@@ -5,9 +5,11 @@ import (
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/cluster/config"
 	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/core/types"
 	"github.com/QuarkChain/goquarkchain/internal/encoder"
 	"github.com/QuarkChain/goquarkchain/rpc"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 	"math/big"
 	"sync"
 )
@@ -45,6 +47,50 @@ func convertEthCallData(data *EthCallArgs) (*CallArgs, error) {
 	return args, nil
 }
 
+// ethTxFieldCount is the number of the fields of a signed Ethereum tx, while a QuarkChain
+// tx also has its network id, full shard keys, token ids and version.
+const ethTxFieldCount = 9
+
+// decodeRawTransaction decodes a raw QuarkChain tx or an EIP-155 signed Ethereum tx, told
+// apart by the number of the fields of the RLP list, so that the error is the one of the
+// format the tx is in.
+func decodeRawTransaction(encodedTx []byte) (*types.EvmTransaction, error) {
+	kind, content, _, err := rlp.Split(encodedTx)
+	if err != nil {
+		return nil, err
+	}
+	if kind == rlp.List {
+		if count, err := rlp.CountValues(content); err == nil && count == ethTxFieldCount {
+			return decodeEthTransaction(encodedTx)
+		}
+	}
+	evmTx := new(types.EvmTransaction)
+	if err := rlp.DecodeBytes(encodedTx, evmTx); err != nil {
+		return nil, err
+	}
+	return evmTx, nil
+}
+
+// decodeEthTransaction decodes an EIP-155 signed Ethereum tx into a tx sent in-shard to
+// the first shard of the chain of its chain id.
+func decodeEthTransaction(encodedTx []byte) (*types.EvmTransaction, error) {
+	qkcConfig := clusterCfg.Quarkchain
+	// the chain of the tx is unknown until the chain id is decoded from its signature
+	evmTx, err := types.DecodeEthTransaction(encodedTx, qkcConfig.NetworkID, 0, qkcConfig.GetDefaultChainTokenID())
+	if err != nil {
+		return nil, err
+	}
+	ethChainID, err := evmTx.EthChainID()
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := qkcConfig.GetChainIDByEthChainID(ethChainID)
+	if err != nil {
+		return nil, err
+	}
+	return types.DecodeEthTransaction(encodedTx, qkcConfig.NetworkID, chainID<<16, qkcConfig.GetDefaultChainTokenID())
+}
+
 func decodeBlockNumberToUint64(b Backend, blockNumber *rpc.BlockNumber) (*uint64, error) {
 	if blockNumber == nil {
 		return nil, nil
@@ -0,0 +1,61 @@
+package qkcapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRawTransaction(t *testing.T) {
+	clusterCfg = config.NewClusterConfig()
+	clusterCfg.Quarkchain.BaseEthChainID = 100000
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	// an Ethereum tx is sent to the chain of its chain id
+	to := common.HexToAddress("0x000000000000000000000000000000000000abcd")
+	ethTx, err := ethTypes.SignTx(ethTypes.NewTransaction(3, to, big.NewInt(100), 21000, big.NewInt(1), nil),
+		ethTypes.NewEIP155Signer(big.NewInt(100001)), key)
+	assert.NoError(t, err)
+	encodedTx, err := rlp.EncodeToBytes(ethTx)
+	assert.NoError(t, err)
+	evmTx, err := decodeRawTransaction(encodedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(types.EthTxVersion), evmTx.Version())
+	assert.Equal(t, uint32(1<<16), evmTx.FromFullShardKey())
+
+	// the error of an Ethereum tx is the one of decoding it as an Ethereum tx
+	ethTx, err = ethTypes.SignTx(ethTypes.NewTransaction(3, to, big.NewInt(100), 21000, big.NewInt(1), nil),
+		ethTypes.NewEIP155Signer(big.NewInt(1)), key)
+	assert.NoError(t, err)
+	encodedTx, err = rlp.EncodeToBytes(ethTx)
+	assert.NoError(t, err)
+	_, err = decodeRawTransaction(encodedTx)
+	assert.EqualError(t, err, "no chain for eth chain id 1")
+
+	// a QuarkChain tx
+	qkcTx := types.NewEvmTransaction(3, account.Recipient(to), big.NewInt(100), 21000, big.NewInt(1), 0, 0, 3, 0, nil, 0, 0)
+	encodedTx, err = rlp.EncodeToBytes(qkcTx)
+	assert.NoError(t, err)
+	evmTx, err = decodeRawTransaction(encodedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, qkcTx.Hash(), evmTx.Hash())
+
+	// the error of a malformed QuarkChain tx is the one of decoding it as a QuarkChain tx
+	fields := make([]interface{}, 15)
+	for i := range fields {
+		fields[i] = []uint{}
+	}
+	encodedTx, err = rlp.EncodeToBytes(fields)
+	assert.NoError(t, err)
+	_, err = decodeRawTransaction(encodedTx)
+	assert.EqualError(t, err, "rlp: expected input string or byte for uint64, decoding into (types.EvmTransaction)(types.txdata).AccountNonce")
+}
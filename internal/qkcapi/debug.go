@@ -0,0 +1,83 @@
+package qkcapi
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
+	"github.com/QuarkChain/goquarkchain/rpc"
+)
+
+// PrivateDebugAPI offers evm tracing of transactions executed on the shards.
+// It is only exposed on the private endpoint since tracing is expensive.
+type PrivateDebugAPI struct {
+	b Backend
+}
+
+func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
+	return &PrivateDebugAPI{b}
+}
+
+// TraceTransaction replays the transaction against the parent state of its block
+// on the owning slave and returns the struct logs of the execution, or the result
+// of the tracer selected by config.
+func (d *PrivateDebugAPI) TraceTransaction(txID hexutil.Bytes, config *TraceArgs) (json.RawMessage, error) {
+	txHash, fullShardKey, err := encoder.IDDecoder(txID)
+	if err != nil {
+		return nil, err
+	}
+	fullShardId, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	result, err := d.b.TraceTransaction(txHash, account.Branch{Value: fullShardId}, config.toTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(result), nil
+}
+
+// TraceBlockByHash re-executes the minor block against its parent state on the owning
+// slave, including the xshard deposits applied before its txs, and returns the trace of
+// each of them next to the gas used, xshard cursor and state root of the replay and of the block.
+func (d *PrivateDebugAPI) TraceBlockByHash(blockID hexutil.Bytes, config *TraceArgs) (json.RawMessage, error) {
+	blockHash, fullShardKey, err := encoder.IDDecoder(blockID)
+	if err != nil {
+		return nil, err
+	}
+	fullShardId, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	result, err := d.b.TraceBlockByHash(blockHash, account.Branch{Value: fullShardId}, config.toTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(result), nil
+}
+
+// TraceCall executes the call like qkc_call and returns the trace like TraceTransaction.
+func (d *PrivateDebugAPI) TraceCall(args CallArgs, blockNr *rpc.BlockNumber, config *TraceArgs) (json.RawMessage, error) {
+	if args.To == nil {
+		return nil, errors.New("missing to")
+	}
+	args.setDefaults()
+	if !clusterCfg.Quarkchain.IsSameFullShard(args.From.FullShardKey, args.To.FullShardKey) {
+		return nil, errors.New("trace cross-shard call not supported")
+	}
+	height, err := decodeBlockNumberToUint64(d.b, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := args.toTx(clusterCfg.Quarkchain)
+	if err != nil {
+		return nil, err
+	}
+	result, err := d.b.TraceCall(tx, args.From, height, config.toTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(result), nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/QuarkChain/goquarkchain/account"
 	"github.com/QuarkChain/goquarkchain/cluster/config"
+	qrpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
 	"github.com/QuarkChain/goquarkchain/common/hexutil"
 	"github.com/QuarkChain/goquarkchain/core/types"
 	"github.com/QuarkChain/goquarkchain/params"
@@ -48,6 +49,34 @@ type CallArgs struct {
 	TransferTokenID *hexutil.Uint64  `json:"transferTokenId"`
 }
 
+// TraceArgs are the options of debug_traceTransaction and debug_traceCall,
+// Tracer selects callTracer, prestateTracer or 4byteTracer instead of the struct logs
+type TraceArgs struct {
+	DisableStorage bool          `json:"disableStorage"`
+	DisableMemory  bool          `json:"disableMemory"`
+	DisableStack   bool          `json:"disableStack"`
+	Limit          *hexutil.Uint `json:"limit"`
+	Tracer         *string       `json:"tracer"`
+}
+
+func (t *TraceArgs) toTraceConfig() *qrpc.TraceConfig {
+	if t == nil {
+		return new(qrpc.TraceConfig)
+	}
+	config := &qrpc.TraceConfig{
+		DisableStorage: t.DisableStorage,
+		DisableMemory:  t.DisableMemory,
+		DisableStack:   t.DisableStack,
+	}
+	if t.Limit != nil {
+		config.Limit = uint32(*t.Limit)
+	}
+	if t.Tracer != nil {
+		config.Tracer = *t.Tracer
+	}
+	return config
+}
+
 type GetAccountDataArgs struct {
 	Address       account.Address  `json:"address"`
 	IncludeShards *bool            `json:"include_shards"`
@@ -146,12 +175,14 @@ func (c *CreateTxArgs) toTx(config *config.QuarkChainConfig) *types.Transaction
 }
 
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
+// From is the keystore account signing it with personal_sendTransaction, instead of v r s.
 type SendTxArgs struct {
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Big    `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"value"`
-	Nonce    *hexutil.Uint64 `json:"nonce"`
+	From     *account.Address `json:"from"`
+	To       *common.Address  `json:"to"`
+	Gas      *hexutil.Big     `json:"gas"`
+	GasPrice *hexutil.Big     `json:"gasPrice"`
+	Value    *hexutil.Big     `json:"value"`
+	Nonce    *hexutil.Uint64  `json:"nonce"`
 	// We accept "data" and "input" for backwards-compatibility reasons. "input" is the
 	// newer name and should be preferred by clients.
 	Data             *hexutil.Bytes  `json:"data"`
@@ -165,6 +196,16 @@ type SendTxArgs struct {
 	TransferTokenID  *hexutil.Uint64 `json:"transfer_token_id"`
 }
 
+// CancelTxArgs represents the arguments to cancel a pending transaction: the gas price
+// and gas token of the zero-value self-send replacing it, and its signature.
+type CancelTxArgs struct {
+	GasPrice   *hexutil.Big    `json:"gasPrice"`
+	GasTokenID *hexutil.Uint64 `json:"gas_token_id"`
+	V          *hexutil.Big    `json:"v"`
+	R          *hexutil.Big    `json:"r"`
+	S          *hexutil.Big    `json:"s"`
+}
+
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
 func (args *SendTxArgs) setDefaults(config *config.QuarkChainConfig) error {
 	if args.Gas == nil {
@@ -202,10 +243,6 @@ func (args *SendTxArgs) setDefaults(config *config.QuarkChainConfig) error {
 		t := hexutil.Uint64(config.GetDefaultChainTokenID())
 		args.TransferTokenID = &t
 	}
-
-	if args.V == nil || args.R == nil || args.S == nil {
-		return errors.New("missing v r s")
-	}
 	return nil
 }
 
@@ -221,7 +258,9 @@ func (args *SendTxArgs) toTransaction() (*types.Transaction, error) {
 			uint32(*args.ToFullShardKey), uint32(*args.NetWorkID), 0, *args.Data, uint64(*args.GasTokenID), uint64(*args.TransferTokenID))
 	}
 
-	evmTx.SetVRS(args.V.ToInt(), args.R.ToInt(), args.S.ToInt())
+	if args.V != nil {
+		evmTx.SetVRS(args.V.ToInt(), args.R.ToInt(), args.S.ToInt())
+	}
 
 	return &types.Transaction{
 		EvmTx:  evmTx,
@@ -0,0 +1,108 @@
+package qkcapi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/account/keystore"
+	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultUnlockDuration is how long personal_unlockAccount unlocks an account
+// for if no duration is given.
+const defaultUnlockDuration = 300 * time.Second
+
+// PrivatePersonalAPI manages the accounts of the keystore of the master, and
+// sends txs signed with their keys.
+type PrivatePersonalAPI struct {
+	b  Backend
+	ks *keystore.KeyStore
+}
+
+func NewPrivatePersonalAPI(b Backend, ks *keystore.KeyStore) *PrivatePersonalAPI {
+	return &PrivatePersonalAPI{b, ks}
+}
+
+// ListAccounts returns the addresses of the accounts of the keystore, in the
+// default full shard key of their key.
+func (p *PrivatePersonalAPI) ListAccounts() []account.Address {
+	accounts := p.ks.Accounts()
+	addresses := make([]account.Address, len(accounts))
+	for i, kf := range accounts {
+		addresses[i] = kf.Address
+	}
+	return addresses
+}
+
+// NewAccount generates a new key stored encrypted with the password.
+func (p *PrivatePersonalAPI) NewAccount(password string) (account.Address, error) {
+	kf, err := p.ks.NewAccount(password)
+	if err != nil {
+		return account.Address{}, err
+	}
+	return kf.Address, nil
+}
+
+// ImportRawKey stores the hex encoded private key encrypted with the password.
+func (p *PrivatePersonalAPI) ImportRawKey(privkey string, password string) (account.Address, error) {
+	key := common.FromHex(privkey)
+	if len(key) != account.KeyLength {
+		return account.Address{}, errors.New("invalid private key length")
+	}
+	kf, err := p.ks.ImportKey(account.BytesToIdentityKey(key), password)
+	if err != nil {
+		return account.Address{}, err
+	}
+	return kf.Address, nil
+}
+
+// UnlockAccount unlocks the account for duration seconds, 300 by default, or
+// until it is locked if duration is 0.
+func (p *PrivatePersonalAPI) UnlockAccount(addr account.Address, password string, duration *uint64) (bool, error) {
+	timeout := defaultUnlockDuration
+	if duration != nil {
+		timeout = time.Duration(*duration) * time.Second
+	}
+	if err := p.ks.TimedUnlock(addr.Recipient, password, timeout); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LockAccount removes the key of the account from memory.
+func (p *PrivatePersonalAPI) LockAccount(addr account.Address) bool {
+	return p.ks.Lock(addr.Recipient) == nil
+}
+
+// SendTransaction signs the tx with the key of args.From, decrypted with the
+// password for the signature only, and adds it to the tx pool. The tx is sent
+// from the full shard key of args.From unless fromFullShardKey is given.
+func (p *PrivatePersonalAPI) SendTransaction(args SendTxArgs, password string) (hexutil.Bytes, error) {
+	if args.From == nil {
+		return nil, errors.New("from is missing")
+	}
+	if args.FromFullShardKey == nil {
+		t := hexutil.Uint(args.From.FullShardKey)
+		args.FromFullShardKey = &t
+	}
+	if err := args.setDefaults(clusterCfg.Quarkchain); err != nil {
+		return nil, err
+	}
+	if args.V != nil || args.R != nil || args.S != nil {
+		return nil, errors.New("v r s must not be set")
+	}
+	tx, err := args.toTransaction()
+	if err != nil {
+		return nil, err
+	}
+	if tx.EvmTx, err = p.ks.SignTxWithPassphrase(args.From.Recipient, password, tx.EvmTx); err != nil {
+		return nil, err
+	}
+	if err := p.b.AddTransaction(tx); err != nil {
+		return EmptyTxID, err
+	}
+	return encoder.IDEncoder(tx.Hash().Bytes(), tx.EvmTx.FromFullShardKey()), nil
+}
@@ -0,0 +1,254 @@
+package qkcapi
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/account/keystore"
+	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/core"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SignTxArgs represents the arguments of a tx signed by the node with an unlocked
+// keystore account. The shards of the tx are those of the full shard keys of From
+// and To, the contract being created in the shard of From if To is nil. Nonce, Gas
+// and GasPrice are filled by the node if they are not given.
+type SignTxArgs struct {
+	From            account.Address  `json:"from"`
+	To              *account.Address `json:"to"`
+	Gas             *hexutil.Big     `json:"gas"`
+	GasPrice        *hexutil.Big     `json:"gasPrice"`
+	Value           *hexutil.Big     `json:"value"`
+	Nonce           *hexutil.Uint64  `json:"nonce"`
+	Data            *hexutil.Bytes   `json:"data"`
+	GasTokenID      *hexutil.Uint64  `json:"gasTokenId"`
+	TransferTokenID *hexutil.Uint64  `json:"transferTokenId"`
+}
+
+// SignTransactionResult is a tx signed by the node, which is sent with
+// sendRawTransaction.
+type SignTransactionResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+	ID  hexutil.Bytes `json:"id"`
+}
+
+// PrivateSigningAPI signs txs with the keys of the unlocked accounts of the
+// keystore of the master, filling the fields of the tx which are not given.
+type PrivateSigningAPI struct {
+	b         Backend
+	ks        *keystore.KeyStore
+	nonceLock *AddrLocker
+}
+
+func NewPrivateSigningAPI(b Backend, ks *keystore.KeyStore) *PrivateSigningAPI {
+	return &PrivateSigningAPI{b, ks, new(AddrLocker)}
+}
+
+// SignTransaction signs the tx with the key of args.From without sending it.
+func (s *PrivateSigningAPI) SignTransaction(args SignTxArgs) (*SignTransactionResult, error) {
+	tx, err := s.signTx(&args)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(tx.EvmTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{
+		Raw: raw,
+		ID:  encoder.IDEncoder(tx.Hash().Bytes(), tx.EvmTx.FromFullShardKey()),
+	}, nil
+}
+
+// SignAndSend signs the tx with the key of args.From and adds it to the tx pool.
+// The txs of an account are sent one at a time, so that concurrent calls do not
+// fill the same nonce.
+func (s *PrivateSigningAPI) SignAndSend(args SignTxArgs) (hexutil.Bytes, error) {
+	s.nonceLock.LockAddr(args.From.Recipient)
+	defer s.nonceLock.UnlockAddr(args.From.Recipient)
+
+	tx, err := s.signTx(&args)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.b.AddTransaction(tx); err != nil {
+		return EmptyTxID, err
+	}
+	return encoder.IDEncoder(tx.Hash().Bytes(), tx.EvmTx.FromFullShardKey()), nil
+}
+
+// signTx fills the missing fields of the tx and signs it with the key of the
+// unlocked account of args.From.
+func (s *PrivateSigningAPI) signTx(args *SignTxArgs) (*types.Transaction, error) {
+	if !s.ks.IsUnlocked(args.From.Recipient) {
+		if _, err := s.ks.Find(args.From.Recipient); err != nil {
+			return nil, err
+		}
+		return nil, keystore.ErrLocked
+	}
+	if err := s.setDefaults(args); err != nil {
+		return nil, err
+	}
+	tx := args.toTransaction(clusterCfg.Quarkchain.NetworkID)
+	if args.Gas == nil {
+		gas, err := s.estimateGas(tx, args)
+		if err != nil {
+			return nil, err
+		}
+		args.Gas = (*hexutil.Big)(new(big.Int).SetUint64(gas))
+		tx = args.toTransaction(clusterCfg.Quarkchain.NetworkID)
+	} else {
+		intrinsic, err := args.intrinsicGas()
+		if err != nil {
+			return nil, err
+		}
+		if tx.EvmTx.Gas() < intrinsic {
+			return nil, core.ErrIntrinsicGas
+		}
+	}
+	evmTx, err := s.ks.SignTx(args.From.Recipient, tx.EvmTx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Transaction{EvmTx: evmTx, TxType: types.EvmTx}, nil
+}
+
+// setDefaults fills the nonce and the gas price of the tx, but its gas, which is
+// estimated for the tx with the other fields filled.
+func (s *PrivateSigningAPI) setDefaults(args *SignTxArgs) error {
+	fullShardID, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(args.From.FullShardKey)
+	if err != nil {
+		return err
+	}
+	branch := account.Branch{Value: fullShardID}
+	if args.To != nil {
+		if _, err := clusterCfg.Quarkchain.GetFullShardIdByFullShardKey(args.To.FullShardKey); err != nil {
+			return err
+		}
+	}
+	if args.Value == nil {
+		args.Value = new(hexutil.Big)
+	}
+	if args.Data == nil {
+		args.Data = new(hexutil.Bytes)
+	}
+	if args.GasTokenID == nil {
+		t := hexutil.Uint64(clusterCfg.Quarkchain.GetDefaultChainTokenID())
+		args.GasTokenID = &t
+	}
+	if args.TransferTokenID == nil {
+		t := hexutil.Uint64(clusterCfg.Quarkchain.GetDefaultChainTokenID())
+		args.TransferTokenID = &t
+	}
+	if args.GasPrice == nil {
+		price, err := s.b.GasPrice(branch, uint64(*args.GasTokenID))
+		if err != nil {
+			return err
+		}
+		args.GasPrice = (*hexutil.Big)(new(big.Int).SetUint64(price))
+	}
+	if args.Nonce == nil {
+		nonce, err := s.pendingNonce(args.From, branch)
+		if err != nil {
+			return err
+		}
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	return nil
+}
+
+// pendingNonce returns the transaction count of the account, after the txs of
+// the account pending in the tx pool of its shard.
+func (s *PrivateSigningAPI) pendingNonce(from account.Address, branch account.Branch) (uint64, error) {
+	data, err := s.b.GetPrimaryAccountData(&from, nil)
+	if err != nil {
+		return 0, err
+	}
+	nonce := data.TransactionCount
+	pending, _, err := s.b.GetTxPoolContent(branch)
+	if err != nil {
+		return 0, err
+	}
+	for _, acc := range pending {
+		if acc.Sender != from.Recipient {
+			continue
+		}
+		for _, tx := range acc.Txs {
+			if tx.EvmTx.Nonce() >= nonce {
+				nonce = tx.EvmTx.Nonce() + 1
+			}
+		}
+	}
+	return nonce, nil
+}
+
+// estimateGas estimates the gas of the tx, which is at least its intrinsic gas,
+// including the cost of a cross-shard tx.
+func (s *PrivateSigningAPI) estimateGas(tx *types.Transaction, args *SignTxArgs) (uint64, error) {
+	intrinsic, err := args.intrinsicGas()
+	if err != nil {
+		return 0, err
+	}
+	estimated, err := s.b.EstimateGas(tx, &args.From)
+	if err != nil {
+		return 0, err
+	}
+	if estimated == 0 {
+		return 0, errors.New("gas estimation failed")
+	}
+	if uint64(estimated) < intrinsic {
+		return intrinsic, nil
+	}
+	return uint64(estimated), nil
+}
+
+// isCrossShard returns whether the tx is sent to another shard, the shard sizes
+// of the tx not being set before it is added to the tx pool.
+func (args *SignTxArgs) isCrossShard() bool {
+	return args.To != nil && !clusterCfg.Quarkchain.IsSameFullShard(args.From.FullShardKey, args.To.FullShardKey)
+}
+
+func (args *SignTxArgs) intrinsicGas() (uint64, error) {
+	return core.IntrinsicGas(*args.Data, args.To == nil, args.isCrossShard())
+}
+
+func (args *SignTxArgs) toTransaction(networkID uint32) *types.Transaction {
+	var (
+		evmTx *types.EvmTransaction
+		gas   uint64
+	)
+	if args.Gas != nil {
+		gas = args.Gas.ToInt().Uint64()
+	}
+	if args.To == nil {
+		evmTx = types.NewEvmContractCreation(uint64(*args.Nonce), args.Value.ToInt(), gas, args.GasPrice.ToInt(),
+			args.From.FullShardKey, args.From.FullShardKey, networkID, 0, *args.Data,
+			uint64(*args.GasTokenID), uint64(*args.TransferTokenID))
+	} else {
+		evmTx = types.NewEvmTransaction(uint64(*args.Nonce), args.To.Recipient, args.Value.ToInt(), gas,
+			args.GasPrice.ToInt(), args.From.FullShardKey, args.To.FullShardKey, networkID, 0, *args.Data,
+			uint64(*args.GasTokenID), uint64(*args.TransferTokenID))
+	}
+	return &types.Transaction{
+		EvmTx:  evmTx,
+		TxType: types.EvmTx,
+	}
+}
+
+// PrivateEthSigningAPI provides eth_signTransaction on the private endpoint.
+type PrivateEthSigningAPI struct {
+	s *PrivateSigningAPI
+}
+
+func NewPrivateEthSigningAPI(s *PrivateSigningAPI) *PrivateEthSigningAPI {
+	return &PrivateEthSigningAPI{s}
+}
+
+// SignTransaction signs the tx with the key of args.From without sending it.
+func (e *PrivateEthSigningAPI) SignTransaction(args SignTxArgs) (*SignTransactionResult, error) {
+	return e.s.SignTransaction(args)
+}
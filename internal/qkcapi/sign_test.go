@@ -0,0 +1,169 @@
+package qkcapi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	"github.com/QuarkChain/goquarkchain/account/keystore"
+	"github.com/QuarkChain/goquarkchain/cluster/config"
+	qrpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
+	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/core"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	qkcParam "github.com/QuarkChain/goquarkchain/params"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testGasPrice = 123
+
+// fakeSigningBackend holds the tx count of the accounts and the txs added to
+// its tx pool, which are all pending.
+type fakeSigningBackend struct {
+	Backend
+	mu      sync.Mutex
+	nonce   uint64
+	pending map[account.Recipient][]*types.Transaction
+}
+
+func (b *fakeSigningBackend) GetPrimaryAccountData(address *account.Address, blockHeight *uint64) (*qrpc.AccountBranchData, error) {
+	return &qrpc.AccountBranchData{TransactionCount: b.nonce}, nil
+}
+
+func (b *fakeSigningBackend) GetTxPoolContent(branch account.Branch) ([]*qrpc.TxPoolAccount, []*qrpc.TxPoolAccount, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending := make([]*qrpc.TxPoolAccount, 0, len(b.pending))
+	for sender, txs := range b.pending {
+		pending = append(pending, &qrpc.TxPoolAccount{Sender: sender, Txs: txs})
+	}
+	return pending, nil, nil
+}
+
+func (b *fakeSigningBackend) GasPrice(branch account.Branch, tokenID uint64) (uint64, error) {
+	return testGasPrice, nil
+}
+
+func (b *fakeSigningBackend) EstimateGas(tx *types.Transaction, address *account.Address) (uint32, error) {
+	// below the intrinsic gas of any tx
+	return 1, nil
+}
+
+func (b *fakeSigningBackend) AddTransaction(tx *types.Transaction) error {
+	// the latency of the call to the slave
+	time.Sleep(10 * time.Millisecond)
+	sender, err := types.Sender(types.MakeSigner(tx.EvmTx.NetworkId()), tx.EvmTx)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending[sender] = append(b.pending[sender], tx)
+	return nil
+}
+
+func newTestSigningAPI(t *testing.T) (*PrivateSigningAPI, *fakeSigningBackend, account.Address, func()) {
+	clusterCfg = config.NewClusterConfig()
+	dir, err := ioutil.TempDir("", "qkcapi")
+	assert.NoError(t, err)
+	ks := keystore.NewKeyStore(filepath.Join(dir, "keystore"))
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	keyFile, err := ks.ImportKey(account.BytesToIdentityKey(crypto.FromECDSA(key)), "pass")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.Unlock(keyFile.Address.Recipient, "pass"))
+
+	b := &fakeSigningBackend{pending: make(map[account.Recipient][]*types.Transaction)}
+	return NewPrivateSigningAPI(b, ks), b, keyFile.Address, func() {
+		ks.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func decodeSignedTx(t *testing.T, raw hexutil.Bytes) *types.EvmTransaction {
+	evmTx := new(types.EvmTransaction)
+	assert.NoError(t, rlp.DecodeBytes(raw, evmTx))
+	return evmTx
+}
+
+func TestSignTransaction(t *testing.T) {
+	api, b, from, cleanup := newTestSigningAPI(t)
+	defer cleanup()
+	b.nonce = 3
+	b.pending[from.Recipient] = []*types.Transaction{{
+		EvmTx:  types.NewEvmTransaction(3, from.Recipient, nil, 0, nil, 0, 0, 3, 0, nil, 0, 0),
+		TxType: types.EvmTx,
+	}}
+
+	// the nonce follows the pending txs, the gas is the intrinsic gas of a
+	// cross-shard tx
+	to := account.NewAddress(account.BytesToIdentityRecipient([]byte{1}), 1<<16)
+	assert.False(t, clusterCfg.Quarkchain.IsSameFullShard(from.FullShardKey, to.FullShardKey))
+	result, err := api.SignTransaction(SignTxArgs{From: from, To: &to})
+	assert.NoError(t, err)
+	evmTx := decodeSignedTx(t, result.Raw)
+	assert.Equal(t, uint64(4), evmTx.Nonce())
+	assert.Equal(t, uint64(testGasPrice), evmTx.GasPrice().Uint64())
+	assert.Equal(t, params.TxGas+qkcParam.GtxxShardCost.Uint64(), evmTx.Gas())
+	assert.Equal(t, to.Recipient, *evmTx.To())
+	assert.Equal(t, to.FullShardKey, evmTx.ToFullShardKey())
+	assert.Equal(t, clusterCfg.Quarkchain.GetDefaultChainTokenID(), evmTx.GasTokenID())
+	sender, err := types.Sender(types.MakeSigner(evmTx.NetworkId()), evmTx)
+	assert.NoError(t, err)
+	assert.Equal(t, from.Recipient, sender)
+
+	// the given fields are kept
+	nonce, price, gas := hexutil.Uint64(7), (*hexutil.Big)(hexutil.MustDecodeBig("0x5")), (*hexutil.Big)(hexutil.MustDecodeBig("0x9c40"))
+	result, err = api.SignTransaction(SignTxArgs{From: from, To: &to, Nonce: &nonce, GasPrice: price, Gas: gas})
+	assert.NoError(t, err)
+	evmTx = decodeSignedTx(t, result.Raw)
+	assert.Equal(t, uint64(7), evmTx.Nonce())
+	assert.Equal(t, uint64(5), evmTx.GasPrice().Uint64())
+	assert.Equal(t, uint64(40000), evmTx.Gas())
+
+	// a given gas below the intrinsic gas of the cross-shard tx
+	gas = (*hexutil.Big)(hexutil.MustDecodeBig("0x5208"))
+	_, err = api.SignTransaction(SignTxArgs{From: from, To: &to, Gas: gas})
+	assert.Equal(t, core.ErrIntrinsicGas, err)
+
+	// a locked or unknown account
+	other := account.NewAddress(account.BytesToIdentityRecipient([]byte{2}), 0)
+	_, err = api.SignTransaction(SignTxArgs{From: other, To: &to})
+	assert.Error(t, err)
+}
+
+func TestSignAndSendConcurrently(t *testing.T) {
+	api, b, from, cleanup := newTestSigningAPI(t)
+	defer cleanup()
+	to := account.NewAddress(account.BytesToIdentityRecipient([]byte{1}), from.FullShardKey)
+
+	const count = 10
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := api.SignAndSend(SignTxArgs{From: from, To: &to})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// every tx got its own nonce
+	nonces := make(map[uint64]bool)
+	for _, tx := range b.pending[from.Recipient] {
+		nonces[tx.EvmTx.Nonce()] = true
+	}
+	assert.Equal(t, count, len(nonces))
+	for i := uint64(0); i < count; i++ {
+		assert.True(t, nonces[i], "nonce %d", i)
+	}
+}
@@ -0,0 +1,120 @@
+package qkcapi
+
+import (
+	"fmt"
+
+	"github.com/QuarkChain/goquarkchain/account"
+	qrpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
+	"github.com/QuarkChain/goquarkchain/common"
+	"github.com/QuarkChain/goquarkchain/common/hexutil"
+	"github.com/QuarkChain/goquarkchain/core/types"
+	"github.com/QuarkChain/goquarkchain/internal/encoder"
+)
+
+// PublicTxPoolAPI offers the txs waiting in the tx pools of the shards, e.g. to
+// find the txs of a sender stuck behind a nonce gap.
+type PublicTxPoolAPI struct {
+	b Backend
+}
+
+func NewPublicTxPoolAPI(b Backend) *PublicTxPoolAPI {
+	return &PublicTxPoolAPI{b}
+}
+
+func (t *PublicTxPoolAPI) getContent(fullShardKey *hexutil.Uint) (map[string][]*qrpc.TxPoolAccount, error) {
+	fullShardId, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	pending, queued, err := t.b.GetTxPoolContent(account.Branch{Value: fullShardId})
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]*qrpc.TxPoolAccount{"pending": pending, "queued": queued}, nil
+}
+
+// Content returns the pending and the queued txs of the tx pool of the shard of
+// fullShardKey, by sender and nonce.
+func (t *PublicTxPoolAPI) Content(fullShardKey *hexutil.Uint) (map[string]map[string]map[string]map[string]interface{}, error) {
+	content, err := t.getContent(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]map[string]map[string]map[string]interface{}, len(content))
+	for kind, accounts := range content {
+		fields[kind] = make(map[string]map[string]map[string]interface{}, len(accounts))
+		for _, acc := range accounts {
+			txs := make(map[string]map[string]interface{}, len(acc.Txs))
+			for _, tx := range acc.Txs {
+				field, err := encoder.PendingTxEncoder(tx)
+				if err != nil {
+					return nil, err
+				}
+				txs[fmt.Sprintf("%d", tx.EvmTx.Nonce())] = field
+			}
+			fields[kind][encoder.DataEncoder(acc.Sender.Bytes()).String()] = txs
+		}
+	}
+	return fields, nil
+}
+
+// Inspect returns a summary of the pending and the queued txs of the tx pool of
+// the shard of fullShardKey, by sender and nonce.
+func (t *PublicTxPoolAPI) Inspect(fullShardKey *hexutil.Uint) (map[string]map[string]map[string]string, error) {
+	content, err := t.getContent(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]map[string]map[string]string, len(content))
+	for kind, accounts := range content {
+		fields[kind] = make(map[string]map[string]string, len(accounts))
+		for _, acc := range accounts {
+			txs := make(map[string]string, len(acc.Txs))
+			for _, tx := range acc.Txs {
+				summary, err := inspectTx(tx)
+				if err != nil {
+					return nil, err
+				}
+				txs[fmt.Sprintf("%d", tx.EvmTx.Nonce())] = summary
+			}
+			fields[kind][encoder.DataEncoder(acc.Sender.Bytes()).String()] = txs
+		}
+	}
+	return fields, nil
+}
+
+// inspectTx summarizes the recipient, value and gas of the tx, with the tokens
+// they are paid in.
+func inspectTx(tx *types.Transaction) (string, error) {
+	evmTx := tx.EvmTx
+	transferToken, err := common.TokenIdDecode(evmTx.TransferTokenID())
+	if err != nil {
+		return "", err
+	}
+	gasToken, err := common.TokenIdDecode(evmTx.GasTokenID())
+	if err != nil {
+		return "", err
+	}
+	to := "contract creation"
+	if evmTx.To() != nil {
+		to = account.NewAddress(*evmTx.To(), evmTx.ToFullShardKey()).ToHex()
+	}
+	return fmt.Sprintf("%s: %v %s + %v gas × %v %s", to, evmTx.Value(), transferToken, evmTx.Gas(), evmTx.GasPrice(), gasToken), nil
+}
+
+// Status returns the number of pending and of queued txs of the tx pool of the
+// shard of fullShardKey.
+func (t *PublicTxPoolAPI) Status(fullShardKey *hexutil.Uint) (map[string]hexutil.Uint, error) {
+	fullShardId, err := getFullShardId(fullShardKey)
+	if err != nil {
+		return nil, err
+	}
+	pending, queued, err := t.b.GetTxPoolStatus(account.Branch{Value: fullShardId})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(pending),
+		"queued":  hexutil.Uint(queued),
+	}, nil
+}
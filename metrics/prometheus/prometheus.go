@@ -0,0 +1,108 @@
+// Package prometheus exposes the metrics of a registry in the Prometheus text
+// exposition format, so that master and slaves can be scraped on /metrics.
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// quantiles are the quantiles reported for timers and histograms.
+var quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+
+// Handler returns an http.Handler writing the metrics of reg, or of the default
+// registry if it is nil.
+func Handler(reg metrics.Registry) http.Handler {
+	if reg == nil {
+		reg = metrics.DefaultRegistry
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		reg.Each(func(name string, _ interface{}) {
+			names = append(names, name)
+		})
+		sort.Strings(names)
+
+		buf := new(bytes.Buffer)
+		for _, name := range names {
+			writeMetric(buf, mutateName(name), reg.Get(name))
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			log.Debug("Failed to write metrics", "err", err)
+		}
+	})
+}
+
+// mutateName turns a metric name like "shard/1/chain/inserts" into a valid
+// Prometheus one.
+func mutateName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func writeMetric(buf *bytes.Buffer, name string, i interface{}) {
+	switch m := i.(type) {
+	case metrics.Counter:
+		writeValue(buf, name, "counter", m.Count())
+	case metrics.Gauge:
+		writeValue(buf, name, "gauge", m.Value())
+	case metrics.GaugeFloat64:
+		writeValue(buf, name, "gauge", m.Value())
+	case metrics.Meter:
+		writeValue(buf, name, "counter", m.Count())
+	case metrics.Timer:
+		t := m.Snapshot()
+		writeSummary(buf, name, t.Percentiles(quantiles), t.Count(), t.Sum())
+	case metrics.Histogram:
+		h := m.Snapshot()
+		writeSummary(buf, name, h.Percentiles(quantiles), h.Count(), h.Sum())
+	case metrics.ResettingTimer:
+		t := m.Snapshot()
+		if len(t.Values()) == 0 {
+			return
+		}
+		var sum int64
+		for _, v := range t.Values() {
+			sum += v
+		}
+		ps := make([]float64, len(quantiles))
+		for j, p := range t.Percentiles(percents(quantiles)) {
+			ps[j] = float64(p)
+		}
+		writeSummary(buf, name, ps, int64(len(t.Values())), sum)
+	}
+}
+
+func writeValue(buf *bytes.Buffer, name, typ string, value interface{}) {
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, typ)
+	fmt.Fprintf(buf, "%s %v\n", name, value)
+}
+
+func writeSummary(buf *bytes.Buffer, name string, ps []float64, count, sum int64) {
+	fmt.Fprintf(buf, "# TYPE %s summary\n", name)
+	for j, q := range quantiles {
+		fmt.Fprintf(buf, "%s{quantile=\"%v\"} %v\n", name, q, ps[j])
+	}
+	fmt.Fprintf(buf, "%s_sum %d\n", name, sum)
+	fmt.Fprintf(buf, "%s_count %d\n", name, count)
+}
+
+// percents converts quantiles to the percentiles taken by resetting timers.
+func percents(qs []float64) []float64 {
+	ps := make([]float64, len(qs))
+	for i, q := range qs {
+		ps[i] = q * 100
+	}
+	return ps
+}
@@ -0,0 +1,32 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	metrics.Enabled = true
+	reg := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("root/chain/head", reg).Update(12)
+	metrics.NewRegisteredCounter("p2p/dials", reg).Inc(3)
+	timer := metrics.NewRegisteredTimer("shard/1/chain/inserts", reg)
+	timer.Update(2 * time.Millisecond)
+	timer.Update(4 * time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(rec.Body)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(body), "# TYPE root_chain_head gauge\nroot_chain_head 12\n")
+	assert.Contains(t, string(body), "# TYPE p2p_dials counter\np2p_dials 3\n")
+	assert.Contains(t, string(body), "# TYPE shard_1_chain_inserts summary\n")
+	assert.Contains(t, string(body), "shard_1_chain_inserts_sum 6000000\n")
+	assert.Contains(t, string(body), "shard_1_chain_inserts_count 2\n")
+}
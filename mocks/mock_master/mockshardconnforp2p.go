@@ -5,6 +5,7 @@
 package mock_master
 
 import (
+	context "context"
 	account "github.com/QuarkChain/goquarkchain/account"
 	rpc "github.com/QuarkChain/goquarkchain/cluster/rpc"
 	consensus "github.com/QuarkChain/goquarkchain/consensus"
@@ -652,3 +653,247 @@ func (mr *MockISlaveConnMockRecorder) CheckMinorBlocksInRoot(rootBlock interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMinorBlocksInRoot", reflect.TypeOf((*MockISlaveConn)(nil).CheckMinorBlocksInRoot), rootBlock)
 }
+
+// TraceTransaction mocks base method
+func (m *MockISlaveConn) TraceTransaction(txHash common.Hash, branch account.Branch, config *rpc.TraceConfig) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceTransaction", txHash, branch, config)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceTransaction indicates an expected call of TraceTransaction
+func (mr *MockISlaveConnMockRecorder) TraceTransaction(txHash, branch, config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceTransaction", reflect.TypeOf((*MockISlaveConn)(nil).TraceTransaction), txHash, branch, config)
+}
+
+// TraceCall mocks base method
+func (m *MockISlaveConn) TraceCall(tx *types.Transaction, fromAddress *account.Address, height *uint64, config *rpc.TraceConfig) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceCall", tx, fromAddress, height, config)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceCall indicates an expected call of TraceCall
+func (mr *MockISlaveConnMockRecorder) TraceCall(tx, fromAddress, height, config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceCall", reflect.TypeOf((*MockISlaveConn)(nil).TraceCall), tx, fromAddress, height, config)
+}
+
+// TraceBlockByHash mocks base method
+func (m *MockISlaveConn) TraceBlockByHash(blockHash common.Hash, branch account.Branch, config *rpc.TraceConfig) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceBlockByHash", blockHash, branch, config)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceBlockByHash indicates an expected call of TraceBlockByHash
+func (mr *MockISlaveConnMockRecorder) TraceBlockByHash(blockHash, branch, config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceBlockByHash", reflect.TypeOf((*MockISlaveConn)(nil).TraceBlockByHash), blockHash, branch, config)
+}
+
+// NewFilter mocks base method
+func (m *MockISlaveConn) NewFilter(args *rpc0.FilterQuery) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewFilter", args)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewFilter indicates an expected call of NewFilter
+func (mr *MockISlaveConnMockRecorder) NewFilter(args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFilter", reflect.TypeOf((*MockISlaveConn)(nil).NewFilter), args)
+}
+
+// NewBlockFilter mocks base method
+func (m *MockISlaveConn) NewBlockFilter(branch account.Branch) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewBlockFilter", branch)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewBlockFilter indicates an expected call of NewBlockFilter
+func (mr *MockISlaveConnMockRecorder) NewBlockFilter(branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlockFilter", reflect.TypeOf((*MockISlaveConn)(nil).NewBlockFilter), branch)
+}
+
+// GetFilterChanges mocks base method
+func (m *MockISlaveConn) GetFilterChanges(filterID string) ([]common.Hash, []*types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilterChanges", filterID)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].([]*types.Log)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFilterChanges indicates an expected call of GetFilterChanges
+func (mr *MockISlaveConnMockRecorder) GetFilterChanges(filterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilterChanges", reflect.TypeOf((*MockISlaveConn)(nil).GetFilterChanges), filterID)
+}
+
+// UninstallFilter mocks base method
+func (m *MockISlaveConn) UninstallFilter(filterID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallFilter", filterID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UninstallFilter indicates an expected call of UninstallFilter
+func (mr *MockISlaveConnMockRecorder) UninstallFilter(filterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallFilter", reflect.TypeOf((*MockISlaveConn)(nil).UninstallFilter), filterID)
+}
+
+// SubscribeEvents mocks base method
+func (m *MockISlaveConn) SubscribeEvents(ctx context.Context, typ uint8, handle func(*rpc.ShardEvent)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", ctx, typ, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents
+func (mr *MockISlaveConnMockRecorder) SubscribeEvents(ctx, typ, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockISlaveConn)(nil).SubscribeEvents), ctx, typ, handle)
+}
+
+// GetXShardDepositBlock mocks base method
+func (m *MockISlaveConn) GetXShardDepositBlock(branch account.Branch, rHash common.Hash, mHash common.Hash, txHash common.Hash) (*types.MinorBlockHeader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetXShardDepositBlock", branch, rHash, mHash, txHash)
+	ret0, _ := ret[0].(*types.MinorBlockHeader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetXShardDepositBlock indicates an expected call of GetXShardDepositBlock
+func (mr *MockISlaveConnMockRecorder) GetXShardDepositBlock(branch, rHash, mHash, txHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXShardDepositBlock", reflect.TypeOf((*MockISlaveConn)(nil).GetXShardDepositBlock), branch, rHash, mHash, txHash)
+}
+
+// GetXShardDepositReceipt mocks base method
+func (m *MockISlaveConn) GetXShardDepositReceipt(txHash common.Hash, branch account.Branch) (*types.MinorBlock, uint32, *types.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetXShardDepositReceipt", txHash, branch)
+	ret0, _ := ret[0].(*types.MinorBlock)
+	ret1, _ := ret[1].(uint32)
+	ret2, _ := ret[2].(*types.Receipt)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetXShardDepositReceipt indicates an expected call of GetXShardDepositReceipt
+func (mr *MockISlaveConnMockRecorder) GetXShardDepositReceipt(txHash, branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXShardDepositReceipt", reflect.TypeOf((*MockISlaveConn)(nil).GetXShardDepositReceipt), txHash, branch)
+}
+
+// GetTrieNodes mocks base method
+func (m *MockISlaveConn) GetTrieNodes(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrieNodes", req)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrieNodes indicates an expected call of GetTrieNodes
+func (mr *MockISlaveConnMockRecorder) GetTrieNodes(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrieNodes", reflect.TypeOf((*MockISlaveConn)(nil).GetTrieNodes), req)
+}
+
+// GetCrossShardTxLists mocks base method
+func (m *MockISlaveConn) GetCrossShardTxLists(req *rpc.P2PRedirectRequest) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCrossShardTxLists", req)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCrossShardTxLists indicates an expected call of GetCrossShardTxLists
+func (mr *MockISlaveConnMockRecorder) GetCrossShardTxLists(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCrossShardTxLists", reflect.TypeOf((*MockISlaveConn)(nil).GetCrossShardTxLists), req)
+}
+
+// GetTxPoolContent mocks base method
+func (m *MockISlaveConn) GetTxPoolContent(branch account.Branch) ([]*rpc.TxPoolAccount, []*rpc.TxPoolAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxPoolContent", branch)
+	ret0, _ := ret[0].([]*rpc.TxPoolAccount)
+	ret1, _ := ret[1].([]*rpc.TxPoolAccount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTxPoolContent indicates an expected call of GetTxPoolContent
+func (mr *MockISlaveConnMockRecorder) GetTxPoolContent(branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxPoolContent", reflect.TypeOf((*MockISlaveConn)(nil).GetTxPoolContent), branch)
+}
+
+// GetTxPoolStatus mocks base method
+func (m *MockISlaveConn) GetTxPoolStatus(branch account.Branch) (uint32, uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxPoolStatus", branch)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(uint32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTxPoolStatus indicates an expected call of GetTxPoolStatus
+func (mr *MockISlaveConnMockRecorder) GetTxPoolStatus(branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxPoolStatus", reflect.TypeOf((*MockISlaveConn)(nil).GetTxPoolStatus), branch)
+}
+
+// SubmitShare mocks base method
+func (m *MockISlaveConn) SubmitShare(share *rpc.SubmitShareRequest) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitShare", share)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitShare indicates an expected call of SubmitShare
+func (mr *MockISlaveConnMockRecorder) SubmitShare(share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitShare", reflect.TypeOf((*MockISlaveConn)(nil).SubmitShare), share)
+}
+
+// GasPriceRate mocks base method
+func (m *MockISlaveConn) GasPriceRate(branch account.Branch, tokenID uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasPriceRate", branch, tokenID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GasPriceRate indicates an expected call of GasPriceRate
+func (mr *MockISlaveConnMockRecorder) GasPriceRate(branch, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasPriceRate", reflect.TypeOf((*MockISlaveConn)(nil).GasPriceRate), branch, tokenID)
+}
@@ -139,6 +139,26 @@ func verifySerializeData(t *testing.T, decodeMsg QKCMsg, v codeC) error {
 		if err := serialize.DeserializeFromBytes(decodeMsg.Data, &cmd); err != nil {
 			t.Fatal("deserialize from Bytes err", err)
 		}
+	case GetTrieNodesRequestMsg:
+		cmd := new(GetTrieNodesRequest)
+		if err := serialize.DeserializeFromBytes(decodeMsg.Data, &cmd); err != nil {
+			t.Fatal("deserialize from Bytes err", err)
+		}
+	case GetTrieNodesResponseMsg:
+		cmd := new(GetTrieNodesResponse)
+		if err := serialize.DeserializeFromBytes(decodeMsg.Data, &cmd); err != nil {
+			t.Fatal("deserialize from Bytes err", err)
+		}
+	case GetCrossShardTxListsRequestMsg:
+		cmd := new(GetCrossShardTxListsRequest)
+		if err := serialize.DeserializeFromBytes(decodeMsg.Data, &cmd); err != nil {
+			t.Fatal("deserialize from Bytes err", err)
+		}
+	case GetCrossShardTxListsResponseMsg:
+		cmd := new(GetCrossShardTxListsResponse)
+		if err := serialize.DeserializeFromBytes(decodeMsg.Data, &cmd); err != nil {
+			t.Fatal("deserialize from Bytes err", err)
+		}
 	default:
 		t.Fatal("unexcepted decodeMsg op")
 	}
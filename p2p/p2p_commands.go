@@ -37,6 +37,10 @@ const (
 	NewRootBlockMsg
 	GetMinorBlockHeaderListWithSkipRequestMsg
 	GetMinorBlockHeaderListWithSkipResponseMsg
+	GetTrieNodesRequestMsg
+	GetTrieNodesResponseMsg
+	GetCrossShardTxListsRequestMsg
+	GetCrossShardTxListsResponseMsg
 	MaxOPNum
 )
 
@@ -63,6 +67,10 @@ var OPSerializerMap = map[P2PCommandOp]interface{}{
 	NewRootBlockMsg:                            NewRootBlockCommand{},
 	GetMinorBlockHeaderListWithSkipRequestMsg:  GetMinorBlockHeaderListWithSkipRequest{},
 	GetMinorBlockHeaderListWithSkipResponseMsg: GetMinorBlockHeaderListResponse{},
+	GetTrieNodesRequestMsg:                     GetTrieNodesRequest{},
+	GetTrieNodesResponseMsg:                    GetTrieNodesResponse{},
+	GetCrossShardTxListsRequestMsg:             GetCrossShardTxListsRequest{},
+	GetCrossShardTxListsResponseMsg:            GetCrossShardTxListsResponse{},
 }
 
 func (p P2PCommandOp) String() string {
@@ -241,3 +249,36 @@ func (r *GetMinorBlockHeaderListWithSkipRequest) GetHash() common.Hash {
 	}
 	return common.Hash{}
 }
+
+// GetTrieNodesRequest get the state trie nodes of a shard by hash
+type GetTrieNodesRequest struct {
+	NodeHashList []common.Hash `bytesizeofslicelen:"4"`
+}
+
+// TrieNode the encoded trie node
+type TrieNode struct {
+	Data []byte `bytesizeofslicelen:"4"`
+}
+
+// GetTrieNodesResponse get trie nodes response, holding the nodes the peer
+// has in the order requested
+type GetTrieNodesResponse struct {
+	NodeList []*TrieNode `bytesizeofslicelen:"4"`
+}
+
+// GetCrossShardTxListsRequest get the xshard tx lists a shard keeps for the
+// neighbor minor blocks by hash
+type GetCrossShardTxListsRequest struct {
+	MinorBlockHashList []common.Hash `bytesizeofslicelen:"4"`
+}
+
+// CrossShardTxList the xshard tx list of a neighbor minor block
+type CrossShardTxList struct {
+	MinorBlockHash common.Hash
+	TxList         types.CrossShardTransactionDepositList
+}
+
+// GetCrossShardTxListsResponse get xshard tx lists response
+type GetCrossShardTxListsResponse struct {
+	CrossShardTxLists []*CrossShardTxList `bytesizeofslicelen:"4"`
+}
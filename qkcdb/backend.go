@@ -0,0 +1,160 @@
+package qkcdb
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Names of the key-value stores a database can be opened with. Builds with the
+// norocksdb tag need neither cgo nor a system rocksdb, and cannot open rocksdb
+// databases.
+const (
+	RocksDBBackend = "rocksdb"
+	LevelDBBackend = "leveldb"
+	MemoryBackend  = "memory"
+)
+
+const (
+	// DefaultCache is the default memory allowance of a database in MiB.
+	DefaultCache = 128
+	// DefaultCompression is the default block compression of a database.
+	DefaultCompression = "snappy"
+)
+
+const handles = 256
+
+// Config selects the key-value store of a database and tunes it.
+type Config struct {
+	Backend     string // one of the backends, DefaultBackend if empty
+	Cache       int    // memory allowance in MiB, DefaultCache if zero
+	Compression string // none, snappy, zlib, bz2, lz4, lz4hc or zstd, DefaultCompression if empty
+}
+
+// DefaultConfig returns the config of the databases of the default backend.
+func DefaultConfig() *Config {
+	return &Config{
+		Backend:     DefaultBackend,
+		Cache:       DefaultCache,
+		Compression: DefaultCompression,
+	}
+}
+
+func (c *Config) cache() int {
+	if c == nil || c.Cache <= 0 {
+		return DefaultCache
+	}
+	return c.Cache
+}
+
+func (c *Config) compression() string {
+	if c == nil || c.Compression == "" {
+		return DefaultCompression
+	}
+	return c.Compression
+}
+
+// Open opens the database in the directory file with the key-value store
+// selected by cfg, removing its content first if clean is set. Writes to a
+// rocksdb or leveldb database opened with isReadOnly are dropped.
+func Open(file string, cfg *Config, clean, isReadOnly bool) (Database, error) {
+	backend := DefaultBackend
+	if cfg != nil && cfg.Backend != "" {
+		backend = cfg.Backend
+	}
+	switch backend {
+	case RocksDBBackend:
+		return openRocksDB(file, cfg, clean, isReadOnly)
+	case LevelDBBackend:
+		return NewLDBDatabase(file, cfg, clean, isReadOnly)
+	case MemoryBackend:
+		return ethdb.NewMemDatabase(), nil
+	default:
+		return nil, fmt.Errorf("unknown database backend %s", backend)
+	}
+}
+
+// OpenReadOnly opens the existing database in the directory file with the
+// key-value store selected by cfg, which is opened read-only: its files are
+// neither written nor compacted, and writes to the database are dropped.
+func OpenReadOnly(file string, cfg *Config) (Database, error) {
+	backend := DefaultBackend
+	if cfg != nil && cfg.Backend != "" {
+		backend = cfg.Backend
+	}
+	if backend == RocksDBBackend || backend == LevelDBBackend {
+		if _, err := os.Stat(file); err != nil {
+			return nil, err
+		}
+	}
+	switch backend {
+	case RocksDBBackend:
+		return openRocksDBReadOnly(file, cfg)
+	case LevelDBBackend:
+		return newLDBDatabase(file, cfg, false, true, true)
+	default:
+		return nil, fmt.Errorf("database backend %s cannot be opened read-only", backend)
+	}
+}
+
+// iterable is a database of a key-value store which can be iterated in key order.
+type iterable interface {
+	iterate(prefix []byte, fn func(key, value []byte) bool) error
+	iterateReverse(start, end []byte, fn func(key, value []byte) bool) error
+}
+
+// Iterate calls fn with every key and value of db whose key starts with prefix,
+// in key order, until fn returns false. The slices passed to fn are only valid
+// during the call.
+func Iterate(db Database, prefix []byte, fn func(key, value []byte) bool) error {
+	switch db := db.(type) {
+	case iterable:
+		return db.iterate(prefix, fn)
+	case *ethdb.MemDatabase:
+		return iterateMemory(db, func(key []byte) bool { return bytes.HasPrefix(key, prefix) }, false, fn)
+	default:
+		return fmt.Errorf("iteration is not supported by database %T", db)
+	}
+}
+
+// IterateReverse calls fn with every key and value of db whose key is between end
+// and start, both included, in reverse key order, until fn returns false. The
+// slices passed to fn are only valid during the call.
+func IterateReverse(db Database, start, end []byte, fn func(key, value []byte) bool) error {
+	switch db := db.(type) {
+	case iterable:
+		return db.iterateReverse(start, end, fn)
+	case *ethdb.MemDatabase:
+		inRange := func(key []byte) bool { return bytes.Compare(key, end) >= 0 && bytes.Compare(key, start) <= 0 }
+		return iterateMemory(db, inRange, true, fn)
+	default:
+		return fmt.Errorf("iteration is not supported by database %T", db)
+	}
+}
+
+// iterateMemory calls fn with the keys of db matching filter and their values in
+// key order, or in reverse key order if reverse is set, until fn returns false.
+func iterateMemory(db *ethdb.MemDatabase, filter func(key []byte) bool, reverse bool, fn func(key, value []byte) bool) error {
+	var keys [][]byte
+	for _, key := range db.Keys() {
+		if filter(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return (bytes.Compare(keys[i], keys[j]) < 0) != reverse
+	})
+	for _, key := range keys {
+		value, err := db.Get(key)
+		if err != nil {
+			continue
+		}
+		if !fn(key, value) {
+			break
+		}
+	}
+	return nil
+}
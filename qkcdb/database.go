@@ -1,7 +1,11 @@
+// +build !norocksdb
+
 package qkcdb
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"sync"
 
@@ -9,10 +13,18 @@ import (
 	"github.com/tecbot/gorocksdb"
 )
 
-const (
-	cache   uint64 = 128
-	handles        = 256
-)
+// DefaultBackend is the key-value store of the databases if none is selected.
+const DefaultBackend = RocksDBBackend
+
+var rdbCompressions = map[string]gorocksdb.CompressionType{
+	"none":   gorocksdb.NoCompression,
+	"snappy": gorocksdb.SnappyCompression,
+	"zlib":   gorocksdb.ZLibCompression,
+	"bz2":    gorocksdb.Bz2Compression,
+	"lz4":    gorocksdb.LZ4Compression,
+	"lz4hc":  gorocksdb.LZ4HCCompression,
+	"zstd":   gorocksdb.ZSTDCompression,
+}
 
 type RDBDatabase struct {
 	fn         string        // filename for reporting
@@ -26,10 +38,30 @@ type RDBDatabase struct {
 
 // NewRDBDatabase returns a rocksdb wrapped object.
 func NewRDBDatabase(file string, clean, isReadOnly bool) (*RDBDatabase, error) {
+	return NewRDBDatabaseWithConfig(file, DefaultConfig(), clean, isReadOnly)
+}
+
+// NewRDBDatabaseWithConfig returns a rocksdb wrapped object using the cache
+// size and compression of cfg.
+func NewRDBDatabaseWithConfig(file string, cfg *Config, clean, isReadOnly bool) (*RDBDatabase, error) {
+	return newRDBDatabase(file, cfg, clean, isReadOnly, false)
+}
+
+// newRDBDatabase opens the rocksdb database in file. If readOnlyFiles is set,
+// the database must exist and is opened read-only by rocksdb, which neither
+// writes nor compacts its files.
+func newRDBDatabase(file string, cfg *Config, clean, isReadOnly, readOnlyFiles bool) (*RDBDatabase, error) {
 	logger := log.New("database", file)
 
-	if err := os.MkdirAll(file, 0700); err != nil {
-		return nil, err
+	cache, compression := cfg.cache(), cfg.compression()
+	ctype, ok := rdbCompressions[compression]
+	if !ok {
+		return nil, fmt.Errorf("unsupported rocksdb compression %s", compression)
+	}
+	if !readOnlyFiles {
+		if err := os.MkdirAll(file, 0700); err != nil {
+			return nil, err
+		}
 	}
 	opts := gorocksdb.NewDefaultOptions()
 	if clean {
@@ -39,19 +71,29 @@ func NewRDBDatabase(file string, clean, isReadOnly bool) (*RDBDatabase, error) {
 	}
 	// ubuntu 16.04 max files descriptors 524288
 	opts.SetMaxFileOpeningThreads(handles)
-	// 128 MiB
 	opts.SetMaxTotalWalSize(uint64(cache * 1024 * 1024))
+	bbto := gorocksdb.NewDefaultBlockBasedTableOptions()
+	bbto.SetBlockCache(gorocksdb.NewLRUCache(cache * 1024 * 1024))
+	opts.SetBlockBasedTableFactory(bbto)
 	// sets the maximum number of write buffers that are built up in memory.
 	opts.SetMaxWriteBufferNumber(3)
 	// sets the target file size for compaction.
 	opts.SetTargetFileSizeBase(6710886)
-	opts.SetCreateIfMissing(true)
+	opts.SetCreateIfMissing(!readOnlyFiles)
 	opts.IncreaseParallelism(3)
 	opts.SetMaxBackgroundFlushes(1)
-	opts.SetCompression(gorocksdb.SnappyCompression)
+	opts.SetCompression(ctype)
 
 	// Open the db and recover any potential corruptions
-	db, err := gorocksdb.OpenDb(opts, file)
+	var (
+		db  *gorocksdb.DB
+		err error
+	)
+	if readOnlyFiles {
+		db, err = gorocksdb.OpenDbForReadOnly(opts, file, false)
+	} else {
+		db, err = gorocksdb.OpenDb(opts, file)
+	}
 	// check for errors and abort if opening of the db failed
 	if err != nil {
 		return nil, err
@@ -60,7 +102,7 @@ func NewRDBDatabase(file string, clean, isReadOnly bool) (*RDBDatabase, error) {
 	ro := gorocksdb.NewDefaultReadOptions()
 	wo := gorocksdb.NewDefaultWriteOptions()
 
-	logger.Info("Allocated cache and file handles", "cache", cache, "handles", handles)
+	logger.Info("Allocated cache and file handles", "cache", cache, "handles", handles, "compression", compression)
 
 	return &RDBDatabase{
 		fn:         file,
@@ -72,6 +114,14 @@ func NewRDBDatabase(file string, clean, isReadOnly bool) (*RDBDatabase, error) {
 	}, nil
 }
 
+func openRocksDB(file string, cfg *Config, clean, isReadOnly bool) (Database, error) {
+	return NewRDBDatabaseWithConfig(file, cfg, clean, isReadOnly)
+}
+
+func openRocksDBReadOnly(file string, cfg *Config) (Database, error) {
+	return newRDBDatabase(file, cfg, false, true, true)
+}
+
 // Path returns the path to the database directory.
 func (db *RDBDatabase) Path() string {
 	return db.fn
@@ -131,6 +181,36 @@ func (db *RDBDatabase) NewIteratorWithPrefix(prefix []byte) *gorocksdb.Iterator
 	return it
 }
 
+func (db *RDBDatabase) iterate(prefix []byte, fn func(key, value []byte) bool) error {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Close()
+	for ; it.ValidForPrefix(prefix); it.Next() {
+		key, value := it.Key(), it.Value()
+		next := fn(key.Data(), value.Data())
+		key.Free()
+		value.Free()
+		if !next {
+			break
+		}
+	}
+	return it.Err()
+}
+
+func (db *RDBDatabase) iterateReverse(start, end []byte, fn func(key, value []byte) bool) error {
+	it := db.NewIterator()
+	defer it.Close()
+	for it.SeekForPrev(start); it.Valid(); it.Prev() {
+		key, value := it.Key(), it.Value()
+		next := bytes.Compare(key.Data(), end) >= 0 && fn(key.Data(), value.Data())
+		key.Free()
+		value.Free()
+		if !next {
+			break
+		}
+	}
+	return it.Err()
+}
+
 func (db *RDBDatabase) Close() {
 	db.closeOnce.Do(func() {
 		db.db.Close()
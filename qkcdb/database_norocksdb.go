@@ -0,0 +1,16 @@
+// +build norocksdb
+
+package qkcdb
+
+import "fmt"
+
+// DefaultBackend is the key-value store of the databases if none is selected.
+const DefaultBackend = LevelDBBackend
+
+func openRocksDB(file string, cfg *Config, clean, isReadOnly bool) (Database, error) {
+	return nil, fmt.Errorf("database backend %s is not supported by this build", RocksDBBackend)
+}
+
+func openRocksDBReadOnly(file string, cfg *Config) (Database, error) {
+	return openRocksDB(file, cfg, false, true)
+}
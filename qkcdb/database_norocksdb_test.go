@@ -0,0 +1,28 @@
+// +build norocksdb
+
+package qkcdb_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/qkcdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenWithoutRocksDB(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	defer os.RemoveAll(dirname)
+
+	_, err = qkcdb.Open(dirname, &qkcdb.Config{Backend: qkcdb.RocksDBBackend}, false, false)
+	assert.Error(t, err)
+	db, err := qkcdb.Open(dirname, nil, false, false)
+	assert.NoError(t, err)
+	_, ok := db.(*qkcdb.LDBDatabase)
+	assert.True(t, ok)
+	db.Close()
+}
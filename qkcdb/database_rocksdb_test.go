@@ -0,0 +1,95 @@
+// +build !norocksdb
+
+package qkcdb_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/QuarkChain/goquarkchain/qkcdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	testBackends = append(testBackends, qkcdb.RocksDBBackend)
+}
+
+func newTestRDB() (*qkcdb.RDBDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := qkcdb.NewRDBDatabase(dirname, false, false)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+func TestRDB_PutGet(t *testing.T) {
+	db, remove := newTestRDB()
+	defer remove()
+	testPutGet(db, t)
+}
+
+func Test_batch(t *testing.T) {
+	db, remove := newTestRDB()
+	defer remove()
+	batch := db.NewBatch()
+	testBatchPutGet(db, batch, t)
+}
+
+func TestRDB_ParallelPutGet(t *testing.T) {
+	db, remove := newTestRDB()
+	defer remove()
+	testParallelPutGet(db, t)
+}
+
+func TestNewDBWithClean(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := qkcdb.NewRDBDatabase(dirname, false, false)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+	var (
+		key1   = []byte("key1")
+		value1 = []byte("value1")
+	)
+
+	err = db.Put(key1, value1)
+	assert.NoError(t, err)
+	getValue1, err := db.Get(key1)
+	assert.NoError(t, err)
+	assert.Equal(t, value1, getValue1)
+	db.Close()
+
+	//not clean
+	dbNotClean, err := qkcdb.NewRDBDatabase(dirname, false, false)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+
+	getValue1, err = dbNotClean.Get(key1)
+	assert.NoError(t, err)
+	assert.Equal(t, value1, getValue1)
+	dbNotClean.Close()
+
+	//clean
+	dbClean, err := qkcdb.NewRDBDatabase(dirname, true, false)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+
+	getValue1, err = dbClean.Get(key1)
+	assert.Error(t, err)
+	dbClean.Close()
+	os.RemoveAll(dirname)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
@@ -13,21 +14,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func newTestRDB() (*qkcdb.RDBDatabase, func()) {
-	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
-	if err != nil {
-		panic("failed to create test file: " + err.Error())
-	}
-	db, err := qkcdb.NewRDBDatabase(dirname, false, false)
-	if err != nil {
-		panic("failed to create test database: " + err.Error())
-	}
-
-	return db, func() {
-		db.Close()
-		os.RemoveAll(dirname)
-	}
-}
+// testBackends are the backends of the build tested against the same data.
+var testBackends = []string{qkcdb.LevelDBBackend, qkcdb.MemoryBackend}
 
 func testData() map[string]string {
 	var testValues = make(map[string]string)
@@ -40,12 +28,6 @@ func testData() map[string]string {
 	return testValues
 }
 
-func TestRDB_PutGet(t *testing.T) {
-	db, remove := newTestRDB()
-	defer remove()
-	testPutGet(db, t)
-}
-
 func testPutGet(db qkcdb.Database, t *testing.T) {
 	t.Parallel()
 
@@ -102,13 +84,6 @@ func testPutGet(db qkcdb.Database, t *testing.T) {
 	}
 }
 
-func Test_batch(t *testing.T) {
-	db, remove := newTestRDB()
-	defer remove()
-	batch := db.NewBatch()
-	testBatchPutGet(db, batch, t)
-}
-
 func testBatchPutGet(db qkcdb.Database, batch qkcdb.Batch, t *testing.T) {
 	t.Parallel()
 	testValues := testData()
@@ -141,12 +116,6 @@ func testBatchPutGet(db qkcdb.Database, batch qkcdb.Batch, t *testing.T) {
 	}
 }
 
-func TestRDB_ParallelPutGet(t *testing.T) {
-	db, remove := newTestRDB()
-	defer remove()
-	testParallelPutGet(db, t)
-}
-
 func testParallelPutGet(db qkcdb.Database, t *testing.T) {
 	const n = 8
 	var pending sync.WaitGroup
@@ -203,46 +172,164 @@ func testParallelPutGet(db qkcdb.Database, t *testing.T) {
 	pending.Wait()
 }
 
-func TestNewDBWithClean(t *testing.T) {
+func newTestLDB() (*qkcdb.LDBDatabase, func()) {
 	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
 	if err != nil {
 		panic("failed to create test file: " + err.Error())
 	}
-	db, err := qkcdb.NewRDBDatabase(dirname, false, false)
+	db, err := qkcdb.NewLDBDatabase(dirname, qkcdb.DefaultConfig(), false, false)
 	if err != nil {
 		panic("failed to create test database: " + err.Error())
 	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+func TestLDB_PutGet(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testPutGet(db, t)
+}
+
+func TestLDB_Batch(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testBatchPutGet(db, db.NewBatch(), t)
+}
+
+func TestLDB_ParallelPutGet(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testParallelPutGet(db, t)
+}
+
+func TestOpen(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	defer os.RemoveAll(dirname)
+
 	var (
-		key1   = []byte("key1")
-		value1 = []byte("value1")
+		key   = []byte("key")
+		value = []byte("value")
 	)
+	for _, backend := range testBackends {
+		cfg := &qkcdb.Config{Backend: backend, Cache: 16, Compression: "none"}
+		db, err := qkcdb.Open(filepath.Join(dirname, backend), cfg, false, false)
+		assert.NoError(t, err, backend)
+		assert.NoError(t, db.Put(key, value), backend)
+		got, err := db.Get(key)
+		assert.NoError(t, err, backend)
+		assert.Equal(t, value, got, backend)
+		db.Close()
 
-	err = db.Put(key1, value1)
-	assert.NoError(t, err)
-	getValue1, err := db.Get(key1)
-	assert.NoError(t, err)
-	assert.Equal(t, value1, getValue1)
-	db.Close()
+		// reopening with clean drops the content
+		if backend != qkcdb.MemoryBackend {
+			db, err = qkcdb.Open(filepath.Join(dirname, backend), cfg, true, false)
+			assert.NoError(t, err, backend)
+			_, err = db.Get(key)
+			assert.Error(t, err, backend)
+			db.Close()
+		}
+	}
 
-	//not clean
-	dbNotClean, err := qkcdb.NewRDBDatabase(dirname, false, false)
+	_, err = qkcdb.Open(filepath.Join(dirname, "ldb"), &qkcdb.Config{Backend: qkcdb.LevelDBBackend, Compression: "zstd"}, false, false)
+	assert.Error(t, err)
+	_, err = qkcdb.Open(filepath.Join(dirname, "unknown"), &qkcdb.Config{Backend: "unknown"}, false, false)
+	assert.Error(t, err)
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
 	if err != nil {
-		panic("failed to create test database: " + err.Error())
+		panic("failed to create test file: " + err.Error())
 	}
+	defer os.RemoveAll(dirname)
 
-	getValue1, err = dbNotClean.Get(key1)
-	assert.NoError(t, err)
-	assert.Equal(t, value1, getValue1)
-	dbNotClean.Close()
+	var (
+		key   = []byte("key")
+		value = []byte("value")
+	)
+	for _, backend := range testBackends {
+		cfg := &qkcdb.Config{Backend: backend}
+		file := filepath.Join(dirname, backend)
+		if backend == qkcdb.MemoryBackend {
+			_, err := qkcdb.OpenReadOnly(file, cfg)
+			assert.Error(t, err, backend)
+			continue
+		}
+		// a missing database is not created
+		_, err := qkcdb.OpenReadOnly(file, cfg)
+		assert.Error(t, err, backend)
+		_, err = os.Stat(file)
+		assert.True(t, os.IsNotExist(err), backend)
+
+		db, err := qkcdb.Open(file, cfg, false, false)
+		assert.NoError(t, err, backend)
+		assert.NoError(t, db.Put(key, value), backend)
+		db.Close()
+
+		db, err = qkcdb.OpenReadOnly(file, cfg)
+		assert.NoError(t, err, backend)
+		got, err := db.Get(key)
+		assert.NoError(t, err, backend)
+		assert.Equal(t, value, got, backend)
+		// writes are dropped
+		assert.NoError(t, db.Put([]byte("other"), value), backend)
+		_, err = db.Get([]byte("other"))
+		assert.Error(t, err, backend)
+		db.Close()
+	}
+}
 
-	//clean
-	dbClean, err := qkcdb.NewRDBDatabase(dirname, true, false)
+func TestIterate(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "qkcdb_test_")
 	if err != nil {
-		panic("failed to create test database: " + err.Error())
+		panic("failed to create test file: " + err.Error())
 	}
+	defer os.RemoveAll(dirname)
 
-	getValue1, err = dbClean.Get(key1)
-	assert.Error(t, err)
-	dbClean.Close()
-	os.RemoveAll(dirname)
+	for _, backend := range testBackends {
+		db, err := qkcdb.Open(filepath.Join(dirname, backend), &qkcdb.Config{Backend: backend}, false, false)
+		assert.NoError(t, err, backend)
+		for _, key := range []string{"a1", "a2", "a3", "b1"} {
+			assert.NoError(t, db.Put([]byte(key), []byte("v"+key)), backend)
+		}
+
+		found := make(map[string]string)
+		err = qkcdb.Iterate(db, []byte("a"), func(key, value []byte) bool {
+			found[string(key)] = string(value)
+			return true
+		})
+		assert.NoError(t, err, backend)
+		assert.Equal(t, map[string]string{"a1": "va1", "a2": "va2", "a3": "va3"}, found, backend)
+
+		count := 0
+		err = qkcdb.Iterate(db, nil, func(key, value []byte) bool {
+			count++
+			return count < 2
+		})
+		assert.NoError(t, err, backend)
+		assert.Equal(t, 2, count, backend)
+
+		var keys []string
+		err = qkcdb.IterateReverse(db, []byte("a3"), []byte("a15"), func(key, value []byte) bool {
+			keys = append(keys, string(key))
+			return true
+		})
+		assert.NoError(t, err, backend)
+		assert.Equal(t, []string{"a3", "a2"}, keys, backend)
+		keys = nil
+		err = qkcdb.IterateReverse(db, []byte("a25"), nil, func(key, value []byte) bool {
+			keys = append(keys, string(key))
+			return len(keys) < 2
+		})
+		assert.NoError(t, err, backend)
+		assert.Equal(t, []string{"a2", "a1"}, keys, backend)
+		db.Close()
+	}
 }
@@ -0,0 +1,192 @@
+package qkcdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	lerrors "github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+var ldbCompressions = map[string]opt.Compression{
+	"none":   opt.NoCompression,
+	"snappy": opt.SnappyCompression,
+}
+
+// LDBDatabase is a pure Go leveldb database, which needs neither cgo nor a
+// system rocksdb.
+type LDBDatabase struct {
+	fn         string      // filename for reporting
+	db         *leveldb.DB // LevelDB instance
+	isReadOnly bool
+	closeOnce  sync.Once
+	log        log.Logger // Contextual logger tracking the database path
+}
+
+// NewLDBDatabase returns a leveldb wrapped object using the cache size and
+// compression of cfg. Only snappy and no compression are supported.
+func NewLDBDatabase(file string, cfg *Config, clean, isReadOnly bool) (*LDBDatabase, error) {
+	return newLDBDatabase(file, cfg, clean, isReadOnly, false)
+}
+
+// newLDBDatabase opens the leveldb database in file. If readOnlyFiles is set,
+// the database must exist and is opened read-only by leveldb, which neither
+// writes nor compacts its files.
+func newLDBDatabase(file string, cfg *Config, clean, isReadOnly, readOnlyFiles bool) (*LDBDatabase, error) {
+	logger := log.New("database", file)
+
+	cache, compression := cfg.cache(), cfg.compression()
+	ctype, ok := ldbCompressions[compression]
+	if !ok {
+		return nil, fmt.Errorf("unsupported leveldb compression %s", compression)
+	}
+	if clean {
+		if err := os.RemoveAll(file); err != nil {
+			return nil, err
+		}
+	}
+	if !readOnlyFiles {
+		if err := os.MkdirAll(file, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	// Open the db and recover any potential corruptions
+	db, err := leveldb.OpenFile(file, &opt.Options{
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache / 2 * opt.MiB,
+		WriteBuffer:            cache / 4 * opt.MiB, // Two of these are used internally
+		Filter:                 filter.NewBloomFilter(10),
+		Compression:            ctype,
+		ErrorIfMissing:         readOnlyFiles,
+		ReadOnly:               readOnlyFiles,
+	})
+	if _, corrupted := err.(*lerrors.ErrCorrupted); corrupted && !readOnlyFiles {
+		db, err = leveldb.RecoverFile(file, nil)
+	}
+	// check for errors and abort if opening of the db failed
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Allocated cache and file handles", "cache", cache, "handles", handles, "compression", compression)
+
+	return &LDBDatabase{
+		fn:         file,
+		db:         db,
+		isReadOnly: isReadOnly,
+		log:        logger,
+	}, nil
+}
+
+// Path returns the path to the database directory.
+func (db *LDBDatabase) Path() string {
+	return db.fn
+}
+
+// Put puts the given key / value to the queue
+func (db *LDBDatabase) Put(key []byte, value []byte) error {
+	if db.isReadOnly {
+		return nil
+	}
+	if len(key) == 0 || len(value) == 0 {
+		return errors.New("failed to put data, key or value can't be empty")
+	}
+	return db.db.Put(key, value, nil)
+}
+
+func (db *LDBDatabase) Has(key []byte) (bool, error) {
+	return db.db.Has(key, nil)
+}
+
+// Get returns the given key if it's present.
+func (db *LDBDatabase) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errors.New("failed to get data from database, key can't be empty")
+	}
+	return db.db.Get(key, nil)
+}
+
+// Delete deletes the key from the queue and database
+func (db *LDBDatabase) Delete(key []byte) error {
+	return db.db.Delete(key, nil)
+}
+
+func (db *LDBDatabase) NewIterator() iterator.Iterator {
+	return db.db.NewIterator(nil, nil)
+}
+
+// NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
+func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
+	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
+}
+
+func (db *LDBDatabase) iterate(prefix []byte, fn func(key, value []byte) bool) error {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+	for it.Next() {
+		if !fn(it.Key(), it.Value()) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+func (db *LDBDatabase) iterateReverse(start, end []byte, fn func(key, value []byte) bool) error {
+	// the limit of a range is excluded, and start is the greatest key below it
+	limit := append(append([]byte{}, start...), 0)
+	it := db.db.NewIterator(&util.Range{Start: end, Limit: limit}, nil)
+	defer it.Release()
+	for ok := it.Last(); ok; ok = it.Prev() {
+		if !fn(it.Key(), it.Value()) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+func (db *LDBDatabase) Close() {
+	db.closeOnce.Do(func() {
+		if err := db.db.Close(); err != nil {
+			db.log.Error("Failed to close database", "err", err)
+		}
+	})
+}
+
+func (db *LDBDatabase) NewBatch() Batch {
+	return &ldbBatch{db: db.db, b: new(leveldb.Batch)}
+}
+
+type ldbBatch struct {
+	db *leveldb.DB
+	b  *leveldb.Batch
+}
+
+func (b *ldbBatch) Put(key, value []byte) error {
+	b.b.Put(key, value)
+	return nil
+}
+
+func (b *ldbBatch) Delete(key []byte) error {
+	b.b.Delete(key)
+	return nil
+}
+
+func (b *ldbBatch) Write() error {
+	return b.db.Write(b.b, nil)
+}
+
+func (b *ldbBatch) ValueSize() int {
+	return b.b.Len()
+}
+
+func (b *ldbBatch) Reset() {
+	b.b.Reset()
+}